@@ -40,9 +40,13 @@ func (r *containerRouter) initRoutes() {
 		router.NewGetRoute("/containers/{name:.*}/top", r.getContainersTop),
 		router.NewGetRoute("/containers/{name:.*}/logs", r.getContainersLogs),
 		router.NewGetRoute("/containers/{name:.*}/stats", r.getContainersStats),
+		router.NewGetRoute("/containers/{name:.*}/stats/history", r.getContainersStatsHistory),
 		router.NewGetRoute("/containers/{name:.*}/attach/ws", r.wsContainersAttach),
 		router.NewGetRoute("/exec/{id:.*}/json", r.getExecByID),
 		router.NewGetRoute("/containers/{name:.*}/archive", r.getContainersArchive),
+		router.NewGetRoute("/containers/{name:.*}/fs/stat", r.getContainersFsStat),
+		router.NewGetRoute("/containers/{name:.*}/fs/ls", r.getContainersFsList),
+		router.NewGetRoute("/containers/{name:.*}/fs/read", r.getContainersFsRead),
 		// POST
 		router.NewPostRoute("/containers/create", r.postContainersCreate),
 		router.NewPostRoute("/containers/{name:.*}/kill", r.postContainersKill),
@@ -60,6 +64,7 @@ func (r *containerRouter) initRoutes() {
 		router.NewPostRoute("/exec/{name:.*}/resize", r.postContainerExecResize),
 		router.NewPostRoute("/containers/{name:.*}/rename", r.postContainerRename),
 		router.NewPostRoute("/containers/{name:.*}/update", r.postContainerUpdate),
+		router.NewPostRoute("/containers/{name:.*}/import", r.postContainersImport),
 		router.NewPostRoute("/containers/prune", r.postContainersPrune),
 		router.NewPostRoute("/commit", r.postCommit),
 		// PUT
@@ -55,6 +55,20 @@ func (s *containerRouter) postCommit(ctx context.Context, w http.ResponseWriter,
 		Changes: r.Form["changes"],
 	}
 
+	if versions.GreaterThanOrEqualTo(version, "1.41") {
+		if excludeJSON := r.Form.Get("exclude"); excludeJSON != "" {
+			if err := json.Unmarshal([]byte(excludeJSON), &commitCfg.Exclude); err != nil {
+				return errdefs.InvalidParameter(errors.Wrap(err, "invalid exclude"))
+			}
+		}
+		commitCfg.Squash = httputils.BoolValue(r, "squash")
+		if annotationsJSON := r.Form.Get("annotations"); annotationsJSON != "" {
+			if err := json.Unmarshal([]byte(annotationsJSON), &commitCfg.Annotations); err != nil {
+				return errdefs.InvalidParameter(errors.Wrap(err, "invalid annotations"))
+			}
+		}
+	}
+
 	imgID, err := s.backend.CreateImageFromContainer(r.Form.Get("container"), commitCfg)
 	if err != nil {
 		return err
@@ -115,6 +129,22 @@ func (s *containerRouter) getContainersStats(ctx context.Context, w http.Respons
 	return s.backend.ContainerStats(ctx, vars["name"], config)
 }
 
+func (s *containerRouter) getContainersStatsHistory(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	history, err := s.backend.ContainerStatsHistory(ctx, vars["name"], &backend.ContainerStatsHistoryConfig{
+		Since: r.Form.Get("since"),
+		Until: r.Form.Get("until"),
+		Step:  r.Form.Get("step"),
+	})
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, history)
+}
+
 func (s *containerRouter) getContainersLogs(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -130,6 +160,14 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 		return errdefs.InvalidParameter(errors.New("Bad parameters: you must choose at least one stream"))
 	}
 
+	logFilters := filters.NewArgs()
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.41") {
+		var err error
+		if logFilters, err = filters.FromJSON(r.Form.Get("filters")); err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+	}
+
 	containerName := vars["name"]
 	logsConfig := &types.ContainerLogsOptions{
 		Follow:     httputils.BoolValue(r, "follow"),
@@ -140,6 +178,7 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 		ShowStdout: stdout,
 		ShowStderr: stderr,
 		Details:    httputils.BoolValue(r, "details"),
+		Filters:    logFilters,
 	}
 
 	msgs, tty, err := s.backend.ContainerLogs(ctx, containerName, logsConfig)
@@ -156,9 +195,50 @@ func (s *containerRouter) getContainersLogs(ctx context.Context, w http.Response
 }
 
 func (s *containerRouter) getContainersExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.41") {
+		switch layer := r.Form.Get("layer"); layer {
+		case "":
+		case "rw":
+			return s.getContainersExportRWLayer(w, vars["name"])
+		default:
+			return errdefs.InvalidParameter(errors.Errorf("invalid layer %q, only \"rw\" is supported", layer))
+		}
+	}
 	return s.backend.ContainerExport(vars["name"], w)
 }
 
+// getContainersExportRWLayer writes the changes of the RW layer of the
+// container, and sends the ID of its image in the X-Docker-Image-Id header,
+// so that the tar stream only has the changes.
+func (s *containerRouter) getContainersExportRWLayer(w http.ResponseWriter, name string) error {
+	data, imageID, err := s.backend.ContainerExportRWLayer(name)
+	if err != nil {
+		return err
+	}
+	defer data.Close()
+
+	w.Header().Set("X-Docker-Image-Id", imageID)
+	_, err = io.Copy(w, data)
+	return err
+}
+
+func (s *containerRouter) postContainersImport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := s.backend.ContainerImportRWLayer(vars["name"], r.Form.Get("image"), r.Body); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
 type bodyOnStartError struct{}
 
 func (bodyOnStartError) Error() string {
@@ -200,7 +280,7 @@ func (s *containerRouter) postContainersStart(ctx context.Context, w http.Respon
 
 	checkpoint := r.Form.Get("checkpoint")
 	checkpointDir := r.Form.Get("checkpoint-dir")
-	if err := s.backend.ContainerStart(vars["name"], hostConfig, checkpoint, checkpointDir); err != nil {
+	if err := s.backend.ContainerStart(ctx, vars["name"], hostConfig, checkpoint, checkpointDir); err != nil {
 		return err
 	}
 
@@ -376,6 +456,21 @@ func (s *containerRouter) postContainersWait(ctx context.Context, w http.Respons
 }
 
 func (s *containerRouter) getContainersChanges(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if versions.GreaterThanOrEqualTo(httputils.VersionFromContext(ctx), "1.41") {
+		digests := httputils.BoolValue(r, "digest")
+		if digests || httputils.BoolValue(r, "size") {
+			changes, err := s.backend.ContainerChangesWithSize(vars["name"], digests)
+			if err != nil {
+				return err
+			}
+			return httputils.WriteJSON(w, http.StatusOK, changes)
+		}
+	}
+
 	changes, err := s.backend.ContainerChanges(vars["name"])
 	if err != nil {
 		return err
@@ -428,6 +523,18 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 	if versions.LessThan(httputils.VersionFromContext(ctx), "1.40") {
 		updateConfig.PidsLimit = nil
 	}
+	if versions.LessThan(httputils.VersionFromContext(ctx), "1.41") {
+		// these options were ignored by older daemons
+		updateConfig.Ulimits = nil
+		updateConfig.DeviceCgroupRules = nil
+		updateConfig.OomScoreAdj = nil
+		updateConfig.Labels = nil
+		// Ignore the restart delays and window because they were added in API 1.41.
+		updateConfig.RestartPolicy = container.RestartPolicy{
+			Name:              updateConfig.RestartPolicy.Name,
+			MaximumRetryCount: updateConfig.RestartPolicy.MaximumRetryCount,
+		}
+	}
 	if updateConfig.PidsLimit != nil && *updateConfig.PidsLimit <= 0 {
 		// Both `0` and `-1` are accepted to set "unlimited" when updating.
 		// Historically, any negative value was accepted, so treat them as
@@ -436,13 +543,8 @@ func (s *containerRouter) postContainerUpdate(ctx context.Context, w http.Respon
 		updateConfig.PidsLimit = &unlimited
 	}
 
-	hostConfig := &container.HostConfig{
-		Resources:     updateConfig.Resources,
-		RestartPolicy: updateConfig.RestartPolicy,
-	}
-
 	name := vars["name"]
-	resp, err := s.backend.ContainerUpdate(name, hostConfig)
+	resp, err := s.backend.ContainerUpdate(name, &updateConfig)
 	if err != nil {
 		return err
 	}
@@ -495,6 +597,22 @@ func (s *containerRouter) postContainersCreate(ctx context.Context, w http.Respo
 		if hostConfig.CgroupnsMode.IsEmpty() {
 			hostConfig.CgroupnsMode = container.CgroupnsMode("host")
 		}
+
+		// Ignore DependsOn because it was added in API 1.41.
+		hostConfig.DependsOn = nil
+
+		// Ignore the restart delays and window because they were added in API 1.41.
+		hostConfig.RestartPolicy = container.RestartPolicy{
+			Name:              hostConfig.RestartPolicy.Name,
+			MaximumRetryCount: hostConfig.RestartPolicy.MaximumRetryCount,
+		}
+	}
+	if config != nil && config.Healthcheck != nil && versions.LessThan(version, "1.41") {
+		// Ignore StartupTest, StartInterval and OnUnhealthy because they
+		// were added in API 1.41.
+		config.Healthcheck.StartupTest = nil
+		config.Healthcheck.StartInterval = 0
+		config.Healthcheck.OnUnhealthy = ""
 	}
 
 	if hostConfig != nil && hostConfig.PidsLimit != nil && *hostConfig.PidsLimit <= 0 {
@@ -143,3 +143,54 @@ func (s *containerRouter) putContainersArchive(ctx context.Context, w http.Respo
 
 	return s.backend.ContainerExtractToDir(v.Name, v.Path, copyUIDGID, noOverwriteDirNonDir, r.Body)
 }
+
+func (s *containerRouter) getContainersFsStat(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	v, err := httputils.ArchiveFormValues(r, vars)
+	if err != nil {
+		return err
+	}
+
+	stat, err := s.backend.ContainerStatPath(v.Name, v.Path)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, stat)
+}
+
+func (s *containerRouter) getContainersFsList(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	v, err := httputils.ArchiveFormValues(r, vars)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.backend.ContainerListPath(v.Name, v.Path)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, entries)
+}
+
+func (s *containerRouter) getContainersFsRead(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	v, err := httputils.ArchiveFormValues(r, vars)
+	if err != nil {
+		return err
+	}
+
+	content, stat, err := s.backend.ContainerReadPath(v.Name, v.Path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	if err := setContainerPathStatHeader(stat, w.Header()); err != nil {
+		return err
+	}
+
+	// ServeContent handles the Range, If-Range and If-Modified-Since headers
+	// of the request.
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, stat.Name, stat.Mtime, content)
+	return nil
+}
@@ -16,7 +16,7 @@ type Backend interface {
 	SystemInfo() (*types.Info, error)
 	SystemVersion() types.Version
 	SystemDiskUsage(ctx context.Context) (*types.DiskUsage, error)
-	SubscribeToEvents(since, until time.Time, ef filters.Args) ([]events.Message, chan interface{})
+	SubscribeToEventsWithReplay(since, until time.Time, ef filters.Args, replay func(events.Message) error) (chan interface{}, error)
 	UnsubscribeFromEvents(chan interface{})
 	AuthenticateToRegistry(ctx context.Context, authConfig *types.AuthConfig) (string, string, error)
 }
@@ -192,14 +192,13 @@ func (s *systemRouter) getEvents(ctx context.Context, w http.ResponseWriter, r *
 
 	enc := json.NewEncoder(output)
 
-	buffered, l := s.backend.SubscribeToEvents(since, until, ef)
-	defer s.backend.UnsubscribeFromEvents(l)
-
-	for _, ev := range buffered {
-		if err := enc.Encode(ev); err != nil {
-			return err
-		}
+	l, err := s.backend.SubscribeToEventsWithReplay(since, until, ef, func(ev events.Message) error {
+		return enc.Encode(ev)
+	})
+	if err != nil {
+		return err
 	}
+	defer s.backend.UnsubscribeFromEvents(l)
 
 	if onlyPastEvents {
 		return nil
@@ -2,7 +2,9 @@ package volume // import "github.com/docker/docker/api/server/router/volume"
 
 import (
 	"context"
+	"io"
 
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/volume/service/opts"
 	// TODO return types need to be refactored into pkg
 	"github.com/docker/docker/api/types"
@@ -17,4 +19,10 @@ type Backend interface {
 	Create(ctx context.Context, name, driverName string, opts ...opts.CreateOption) (*types.Volume, error)
 	Remove(ctx context.Context, name string, opts ...opts.RemoveOption) error
 	Prune(ctx context.Context, pruneFilters filters.Args) (*types.VolumesPruneReport, error)
+	Clone(ctx context.Context, name, newName string, opts ...opts.CloneOption) (*types.Volume, error)
+	Snapshot(ctx context.Context, name, newName string, opts ...opts.CloneOption) (*types.Volume, error)
+	Rename(ctx context.Context, name, newName string) error
+	Update(ctx context.Context, name string, opts ...opts.UpdateOption) (*types.Volume, error)
+	Export(ctx context.Context, name string, compression archive.Compression, out io.Writer) error
+	Import(ctx context.Context, name string, src io.Reader) error
 }
@@ -26,10 +26,16 @@ func (r *volumeRouter) initRoutes() {
 	r.routes = []router.Route{
 		// GET
 		router.NewGetRoute("/volumes", r.getVolumesList),
+		router.NewGetRoute("/volumes/{name:.*}/export", r.getVolumesExport),
 		router.NewGetRoute("/volumes/{name:.*}", r.getVolumeByName),
 		// POST
 		router.NewPostRoute("/volumes/create", r.postVolumesCreate),
 		router.NewPostRoute("/volumes/prune", r.postVolumesPrune),
+		router.NewPostRoute("/volumes/{name:.*}/clone", r.postVolumesClone),
+		router.NewPostRoute("/volumes/{name:.*}/snapshot", r.postVolumesSnapshot),
+		router.NewPostRoute("/volumes/{name:.*}/rename", r.postVolumesRename),
+		router.NewPostRoute("/volumes/{name:.*}/update", r.postVolumesUpdate),
+		router.NewPostRoute("/volumes/{name:.*}/import", r.postVolumesImport),
 		// DELETE
 		router.NewDeleteRoute("/volumes/{name:.*}", r.deleteVolumes),
 	}
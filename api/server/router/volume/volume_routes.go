@@ -10,6 +10,9 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	volumetypes "github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/docker/docker/volume/service/opts"
 	"github.com/pkg/errors"
 )
@@ -94,3 +97,131 @@ func (v *volumeRouter) postVolumesPrune(ctx context.Context, w http.ResponseWrit
 	}
 	return httputils.WriteJSON(w, http.StatusOK, pruneReport)
 }
+
+func (v *volumeRouter) postVolumesClone(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	req, err := decodeCloneRequest(r)
+	if err != nil {
+		return err
+	}
+
+	volume, err := v.backend.Clone(ctx, vars["name"], req.Name, opts.WithCloneLabels(req.Labels))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
+
+func (v *volumeRouter) postVolumesSnapshot(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	req, err := decodeCloneRequest(r)
+	if err != nil {
+		return err
+	}
+
+	volume, err := v.backend.Snapshot(ctx, vars["name"], req.Name, opts.WithCloneLabels(req.Labels))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusCreated, volume)
+}
+
+// decodeCloneRequest decodes the (optional) request body used by the clone
+// and snapshot endpoints.
+func decodeCloneRequest(r *http.Request) (volumetypes.VolumeCloneBody, error) {
+	var req volumetypes.VolumeCloneBody
+	if err := httputils.ParseForm(r); err != nil {
+		return req, err
+	}
+	if r.ContentLength == 0 {
+		return req, nil
+	}
+	if err := httputils.CheckForJSON(r); err != nil {
+		return req, err
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return req, nil
+		}
+		return req, errdefs.InvalidParameter(err)
+	}
+	return req, nil
+}
+
+func (v *volumeRouter) postVolumesRename(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := v.backend.Rename(ctx, vars["name"], r.Form.Get("name")); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (v *volumeRouter) postVolumesUpdate(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := httputils.CheckForJSON(r); err != nil {
+		return err
+	}
+
+	var req volumetypes.VolumeUpdateBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		if err == io.EOF {
+			return errdefs.InvalidParameter(errors.New("got EOF while reading request body"))
+		}
+		return errdefs.InvalidParameter(err)
+	}
+
+	volume, err := v.backend.Update(ctx, vars["name"], opts.WithUpdateLabels(req.Labels), opts.WithUpdateOptions(req.DriverOpts))
+	if err != nil {
+		return err
+	}
+	return httputils.WriteJSON(w, http.StatusOK, volume)
+}
+
+func (v *volumeRouter) getVolumesExport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	var (
+		compression = archive.Uncompressed
+		contentType = "application/x-tar"
+	)
+	switch c := r.Form.Get("compression"); c {
+	case "", "none":
+	case "gzip":
+		compression = archive.Gzip
+		contentType = "application/gzip"
+	default:
+		return errdefs.InvalidParameter(errors.Errorf("invalid compression: %q", c))
+	}
+
+	w.Header().Set("Content-Type", contentType)
+
+	output := ioutils.NewWriteFlusher(w)
+	defer output.Close()
+
+	if err := v.backend.Export(ctx, vars["name"], compression, output); err != nil {
+		if !output.Flushed() {
+			return err
+		}
+		output.Write(streamformatter.FormatError(err))
+	}
+	return nil
+}
+
+func (v *volumeRouter) postVolumesImport(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	if err := v.backend.Import(ctx, vars["name"], r.Body); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
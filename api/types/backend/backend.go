@@ -77,6 +77,14 @@ type ContainerStatsConfig struct {
 	Version   string
 }
 
+// ContainerStatsHistoryConfig holds the range and the step of the samples
+// returned by a backend.ContainerStatsHistory() call.
+type ContainerStatsHistoryConfig struct {
+	Since string
+	Until string
+	Step  string
+}
+
 // ExecInspect holds information about a running process started
 // with docker exec.
 type ExecInspect struct {
@@ -106,13 +114,16 @@ type ExecProcessConfig struct {
 // CreateImageConfig is the configuration for creating an image from a
 // container.
 type CreateImageConfig struct {
-	Repo    string
-	Tag     string
-	Pause   bool
-	Author  string
-	Comment string
-	Config  *container.Config
-	Changes []string
+	Repo        string
+	Tag         string
+	Pause       bool
+	Author      string
+	Comment     string
+	Config      *container.Config
+	Changes     []string
+	Exclude     []string
+	Squash      bool
+	Annotations map[string]string
 }
 
 // CommitConfig is the configuration for creating an image as part of a build.
@@ -125,4 +136,7 @@ type CommitConfig struct {
 	ContainerMountLabel string
 	ContainerOS         string
 	ParentImageID       string
+	Exclude             []string // patterns of the paths to exclude from the layer
+	Squash              bool     // squash the image, with the layers of its parent, into a single layer
+	Annotations         map[string]string
 }
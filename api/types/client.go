@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"io"
 	"net"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -40,12 +41,15 @@ type ContainerAttachOptions struct {
 
 // ContainerCommitOptions holds parameters to commit changes into a container.
 type ContainerCommitOptions struct {
-	Reference string
-	Comment   string
-	Author    string
-	Changes   []string
-	Pause     bool
-	Config    *container.Config
+	Reference   string
+	Comment     string
+	Author      string
+	Changes     []string
+	Pause       bool
+	Config      *container.Config
+	Exclude     []string          // Patterns of the paths to exclude from the committed changes
+	Squash      bool              // Squash the image, with the layers of its parent, into a single layer
+	Annotations map[string]string // Annotations of the image, which are kept by the daemon and not pushed
 }
 
 // ContainerExecInspect holds information returned by exec inspect.
@@ -79,6 +83,27 @@ type ContainerLogsOptions struct {
 	Follow     bool
 	Tail       string
 	Details    bool
+	Filters    filters.Args
+}
+
+// ContainerStatsHistoryOptions holds parameters to query the stats history
+// of a container with.
+type ContainerStatsHistoryOptions struct {
+	Since string
+	Until string
+	// Step is the duration of the steps over which the samples are
+	// aggregated. Zero returns every sample.
+	Step time.Duration
+}
+
+// ContainerFsReadOptions holds parameters to read a file in the filesystem
+// of a container with.
+type ContainerFsReadOptions struct {
+	// Offset is the offset in bytes to start reading the file at.
+	Offset int64
+	// Length is the number of bytes to read. Zero reads until the end of
+	// the file.
+	Length int64
 }
 
 // ContainerRemoveOptions holds parameters to remove containers.
@@ -413,3 +438,10 @@ type SwarmUnlockKeyResponse struct {
 type PluginCreateOptions struct {
 	RepoName string
 }
+
+// VolumeExportOptions holds parameters to export the content of a volume.
+type VolumeExportOptions struct {
+	// Compression is the compression to apply to the exported tar
+	// stream; "none" (the default) or "gzip".
+	Compression string
+}
@@ -22,18 +22,43 @@ type HealthConfig struct {
 	// {"NONE"} : disable healthcheck
 	// {"CMD", args...} : exec arguments directly
 	// {"CMD-SHELL", command} : run command with system's default shell
+	// {"HTTP", method, url} : send an HTTP request from the container's network namespace
+	// {"HTTP-GET", url} : send an HTTP GET request from the container's network namespace
+	// {"TCP", address} : connect to a host:port address, or a port on the
+	//                    loopback interface, from the container's network namespace
 	Test []string `json:",omitempty"`
 
+	// StartupTest is the test to perform instead of Test until the container
+	// is healthy for the first time. It takes the same options as Test,
+	// except {"NONE"}. An empty slice means to inherit, or to use Test.
+	StartupTest []string `json:",omitempty"`
+
 	// Zero means to inherit. Durations are expressed as integer nanoseconds.
-	Interval    time.Duration `json:",omitempty"` // Interval is the time to wait between checks.
-	Timeout     time.Duration `json:",omitempty"` // Timeout is the time to wait before considering the check to have hung.
-	StartPeriod time.Duration `json:",omitempty"` // The start period for the container to initialize before the retries starts to count down.
+	Interval      time.Duration `json:",omitempty"` // Interval is the time to wait between checks.
+	Timeout       time.Duration `json:",omitempty"` // Timeout is the time to wait before considering the check to have hung.
+	StartPeriod   time.Duration `json:",omitempty"` // The start period for the container to initialize before the retries starts to count down.
+	StartInterval time.Duration `json:",omitempty"` // StartInterval is the time to wait between checks until the container is healthy for the first time.
 
 	// Retries is the number of consecutive failures needed to consider a container as unhealthy.
 	// Zero means inherit.
 	Retries int `json:",omitempty"`
+
+	// OnUnhealthy is the action taken by the daemon when the container becomes unhealthy.
+	// Empty means inherit.
+	// The options are:
+	// "none" : take no action
+	// "restart" : restart the container, whatever its restart policy
+	// "stop" : stop the container
+	OnUnhealthy string `json:",omitempty"`
 }
 
+// Actions taken by the daemon when a container becomes unhealthy.
+const (
+	UnhealthyActionNone    = "none"
+	UnhealthyActionRestart = "restart"
+	UnhealthyActionStop    = "stop"
+)
+
 // Config contains the configuration data about a container.
 // It should hold only portable information about the container.
 // Here, "portable" means "independent from the host we are running on".
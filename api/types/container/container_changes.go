@@ -7,15 +7,51 @@ package container // import "github.com/docker/docker/api/types/container"
 // See hack/generate-swagger-api.sh
 // ----------------------------------------------------------------------------
 
+import "os"
+
 // ContainerChangeResponseItem change item in response to ContainerChanges operation
 // swagger:model ContainerChangeResponseItem
 type ContainerChangeResponseItem struct {
 
+	// The digest of the content of the file, if it is a regular file which was added or modified. Only returned if digests are requested.
+	Digest string `json:"Digest,omitempty"`
+
 	// Kind of change
 	// Required: true
 	Kind uint8 `json:"Kind"`
 
+	// The mode of the file, if it was added or modified. Only returned if sizes are requested.
+	Mode os.FileMode `json:"Mode,omitempty"`
+
 	// Path to file that has changed
 	// Required: true
 	Path string `json:"Path"`
+
+	// The size of the file in bytes, if it is a regular file which was added or modified. Only returned if sizes are requested.
+	Size int64 `json:"Size,omitempty"`
+}
+
+// ContainerChangesOKBody OK response to ContainerChanges operation with sizes
+// swagger:model ContainerChangesOKBody
+type ContainerChangesOKBody struct {
+
+	// The number of added files
+	// Required: true
+	Added int64 `json:"Added"`
+
+	// The changes of the filesystem of the container
+	// Required: true
+	Changes []ContainerChangeResponseItem `json:"Changes"`
+
+	// The number of deleted files
+	// Required: true
+	Deleted int64 `json:"Deleted"`
+
+	// The number of modified files
+	// Required: true
+	Modified int64 `json:"Modified"`
+
+	// The total size in bytes of the added and modified regular files
+	// Required: true
+	Size int64 `json:"Size"`
 }
@@ -15,7 +15,54 @@ type ContainerTopOKBody struct {
 	// Required: true
 	Processes [][]string `json:"Processes"`
 
+	// Each process running in the container, read from /proc. Only returned if no ps arguments are given.
+	ProcessList []*ContainerTopProcess `json:"ProcessList,omitempty"`
+
 	// The ps column titles
 	// Required: true
 	Titles []string `json:"Titles"`
 }
+
+// ContainerTopProcess A process running in a container
+// swagger:model ContainerTopProcess
+type ContainerTopProcess struct {
+
+	// The arguments of the process
+	Cmdline []string `json:"Cmdline"`
+
+	// The name of the executable of the process
+	Command string `json:"Command,omitempty"`
+
+	// The CPU usage of the process since it started, where 100 is the usage of a single CPU
+	CPUPercent float64 `json:"CPUPercent"`
+
+	// The CPU time used by the process, in nanoseconds
+	CPUTime int64 `json:"CPUTime"`
+
+	// The ID of the process, in the PID namespace of the daemon
+	PID int64 `json:"PID"`
+
+	// The ID of the parent process, in the PID namespace of the daemon
+	PPID int64 `json:"PPID"`
+
+	// The resident set size of the process, in bytes
+	RSS uint64 `json:"RSS"`
+
+	// The time at which the process started, in RFC 3339 format with nano-seconds
+	StartTime string `json:"StartTime,omitempty"`
+
+	// The state of the process, such as "R" (running) or "S" (sleeping)
+	State string `json:"State,omitempty"`
+
+	// The number of threads of the process
+	Threads int64 `json:"Threads"`
+
+	// The terminal of the process, or "?" if it has none
+	TTY string `json:"TTY,omitempty"`
+
+	// The real user ID of the process
+	UID uint32 `json:"UID"`
+
+	// The name of the user of the process, or its ID if the user is not known on the host
+	User string `json:"User,omitempty"`
+}
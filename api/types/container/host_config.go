@@ -2,6 +2,7 @@ package container // import "github.com/docker/docker/api/types/container"
 
 import (
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/blkiodev"
 	"github.com/docker/docker/api/types/mount"
@@ -288,6 +289,22 @@ type DeviceMapping struct {
 type RestartPolicy struct {
 	Name              string
 	MaximumRetryCount int
+
+	// InitialDelay is the delay before restarting the container the first
+	// time. The delay doubles after each restart, up to MaximumDelay, and is
+	// reset when the container ran for 10 seconds. Zero means the default
+	// of 100 ms.
+	InitialDelay time.Duration `json:",omitempty"`
+	// MaximumDelay is the maximum delay between restarts. Zero means the
+	// default of 1 minute.
+	MaximumDelay time.Duration `json:",omitempty"`
+
+	// MaximumRestartsInWindow is the maximum number of restarts within
+	// RestartWindow. The container is not restarted anymore once it is
+	// reached. Zero means no limit. The restarts are kept across daemon
+	// restarts, and are reset when the container is started manually.
+	MaximumRestartsInWindow int           `json:",omitempty"`
+	RestartWindow           time.Duration `json:",omitempty"`
 }
 
 // IsNone indicates whether the container has the "no" restart policy.
@@ -317,9 +334,30 @@ func (rp *RestartPolicy) IsUnlessStopped() bool {
 
 // IsSame compares two RestartPolicy to see if they are the same
 func (rp *RestartPolicy) IsSame(tp *RestartPolicy) bool {
-	return rp.Name == tp.Name && rp.MaximumRetryCount == tp.MaximumRetryCount
+	return rp.Name == tp.Name && rp.MaximumRetryCount == tp.MaximumRetryCount &&
+		rp.InitialDelay == tp.InitialDelay && rp.MaximumDelay == tp.MaximumDelay &&
+		rp.MaximumRestartsInWindow == tp.MaximumRestartsInWindow && rp.RestartWindow == tp.RestartWindow
+}
+
+// Dependency is a container which is started before the container which
+// depends on it.
+type Dependency struct {
+	// Container is the name or ID of the container. The daemon stores the ID
+	// of the container.
+	Container string
+	// Condition is the condition the container must meet before starting the
+	// container which depends on it. The options are:
+	// "started" : the container is started (the default)
+	// "healthy" : the container is healthy
+	Condition string `json:",omitempty"`
 }
 
+// Conditions of a Dependency.
+const (
+	DependencyConditionStarted = "started"
+	DependencyConditionHealthy = "healthy"
+)
+
 // LogMode is a type to define the available modes for logging
 // These modes affect how logs are handled when log messages start piling up.
 type LogMode string
@@ -383,6 +421,8 @@ type UpdateConfig struct {
 	// Contains container's resources (cgroups, ulimits)
 	Resources
 	RestartPolicy RestartPolicy
+	OomScoreAdj   *int              `json:",omitempty"` // Container preference for OOM-killing, or `null` to not change
+	Labels        map[string]string `json:",omitempty"` // Labels replacing all the labels of the container, or `null` to not change
 }
 
 // HostConfig the non-portable Config structure of a container.
@@ -399,6 +439,7 @@ type HostConfig struct {
 	AutoRemove      bool          // Automatically remove container when it exits
 	VolumeDriver    string        // Name of the volume driver used to mount volumes
 	VolumesFrom     []string      // List of volumes to take from other container
+	DependsOn       []Dependency  `json:",omitempty"` // Containers to start before the container
 
 	// Applicable to UNIX platforms
 	CapAdd          strslice.StrSlice // List of kernel capabilities to add to the container
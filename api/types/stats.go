@@ -69,6 +69,11 @@ type MemoryStats struct {
 	// number of times memory usage hits limits.
 	Failcnt uint64 `json:"failcnt,omitempty"`
 	Limit   uint64 `json:"limit,omitempty"`
+	// number of times the OOM killer was invoked for the container. Only
+	// available with cgroup v2.
+	OOMEvents uint64 `json:"oom_events,omitempty"`
+	// number of processes of the container killed by the OOM killer.
+	OOMKills uint64 `json:"oom_kills,omitempty"`
 
 	// Windows Memory Stats
 	// See https://technet.microsoft.com/en-us/magazine/ff382715.aspx
@@ -149,6 +154,37 @@ type PidsStats struct {
 	Limit uint64 `json:"limit,omitempty"`
 }
 
+// PressureData is the pressure stall information of a resource, as reported
+// in the pressure files of cgroup v2.
+type PressureData struct {
+	// Avg10, Avg60 and Avg300 are the percentages of time in which tasks
+	// were stalled on the resource over the last 10, 60 and 300 seconds.
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	// Total is the total time in which tasks were stalled, in microseconds.
+	Total uint64 `json:"total"`
+}
+
+// Pressure contains the pressure stall information of a resource.
+type Pressure struct {
+	// Some is the pressure of the time in which at least one task was
+	// stalled on the resource.
+	Some PressureData `json:"some"`
+	// Full is the pressure of the time in which all the non-idle tasks were
+	// stalled on the resource at the same time. It is not reported for the
+	// CPU on older kernels.
+	Full *PressureData `json:"full,omitempty"`
+}
+
+// PressureStats contains the pressure stall information (PSI) of the
+// resources of a container. Only available with cgroup v2.
+type PressureStats struct {
+	CPU    *Pressure `json:"cpu,omitempty"`
+	Memory *Pressure `json:"memory,omitempty"`
+	IO     *Pressure `json:"io,omitempty"`
+}
+
 // Stats is Ultimate struct aggregating all types of stats of one container
 type Stats struct {
 	// Common stats
@@ -156,8 +192,9 @@ type Stats struct {
 	PreRead time.Time `json:"preread"`
 
 	// Linux specific stats, not populated on Windows.
-	PidsStats  PidsStats  `json:"pids_stats,omitempty"`
-	BlkioStats BlkioStats `json:"blkio_stats,omitempty"`
+	PidsStats     PidsStats      `json:"pids_stats,omitempty"`
+	BlkioStats    BlkioStats     `json:"blkio_stats,omitempty"`
+	PressureStats *PressureStats `json:"pressure_stats,omitempty"`
 
 	// Windows specific stats, not populated on Linux.
 	NumProcs     uint32       `json:"num_procs"`
@@ -179,3 +216,44 @@ type StatsJSON struct {
 	// Networks request version >=1.21
 	Networks map[string]NetworkStats `json:"networks,omitempty"`
 }
+
+// StatsHistory is the history of the resource usage of a container, returned
+// by the GET /containers/{id}/stats/history endpoint.
+type StatsHistory struct {
+	ID   string
+	Name string
+	// Step is the duration of the steps over which the samples are
+	// aggregated, in nanoseconds. Zero if the samples are not aggregated.
+	Step time.Duration
+	// Samples are the samples of the history, in chronological order.
+	Samples []StatsHistorySample
+}
+
+// StatsHistorySample is the resource usage of a container over a step of its
+// stats history.
+type StatsHistorySample struct {
+	// Time is the time of the start of the step, or of the sample if the
+	// samples are not aggregated.
+	Time time.Time
+	// Samples is the number of samples aggregated in the step.
+	Samples int
+	// CPUPercent is the average CPU usage over the step, where 100 is the
+	// usage of a single CPU.
+	CPUPercent float64
+	// MemoryUsage is the average memory usage over the step, in bytes.
+	MemoryUsage uint64
+	// MemoryMaxUsage is the maximum memory usage over the step, in bytes.
+	MemoryMaxUsage uint64
+	// MemoryLimit is the memory limit at the end of the step, in bytes.
+	MemoryLimit uint64
+	// NetworkRxBytes, NetworkTxBytes, BlockReadBytes and BlockWriteBytes are
+	// the total number of bytes received and transmitted on all the network
+	// interfaces, and read and written from block devices, at the end of the
+	// step.
+	NetworkRxBytes  uint64
+	NetworkTxBytes  uint64
+	BlockReadBytes  uint64
+	BlockWriteBytes uint64
+	// Pids is the maximum number of processes and threads over the step.
+	Pids uint64
+}
@@ -40,7 +40,8 @@ type ImageInspect struct {
 	Config          *container.Config
 	Architecture    string
 	Os              string
-	OsVersion       string `json:",omitempty"`
+	OsVersion       string            `json:",omitempty"`
+	Annotations     map[string]string `json:",omitempty"`
 	Size            int64
 	VirtualSize     int64
 	GraphDriver     GraphDriverData
@@ -282,6 +283,13 @@ type HealthcheckResult struct {
 	End      time.Time // End is the time this check ended
 	ExitCode int       // ExitCode meanings: 0=healthy, 1=unhealthy, 2=reserved (considered unhealthy), else=error running probe
 	Output   string    // Output from last check
+
+	// StatusCode is the status code of the response to HTTP and HTTP-GET probes
+	StatusCode int `json:",omitempty"`
+	// Latency is the time taken to get a response to HTTP and HTTP-GET
+	// probes, or to connect for TCP probes. Durations are expressed as
+	// integer nanoseconds.
+	Latency time.Duration `json:",omitempty"`
 }
 
 // Health states
@@ -314,6 +322,10 @@ type ContainerState struct {
 	StartedAt  string
 	FinishedAt string
 	Health     *Health `json:",omitempty"`
+
+	// RestartDelay is the delay before the pending, or last, restart of the
+	// container by its restart policy. Zero when the container is stopped.
+	RestartDelay time.Duration `json:",omitempty"`
 }
 
 // ContainerNode stores information about the node that a container
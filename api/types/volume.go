@@ -48,11 +48,17 @@ type Volume struct {
 }
 
 // VolumeUsageData Usage details about the volume. This information is used by the
-// `GET /system/df` endpoint, and omitted in other endpoints.
+// `GET /system/df` endpoint, and by `GET /volumes/{name}` for volumes
+// with a size limit. It is omitted in other endpoints.
 //
 // swagger:model VolumeUsageData
 type VolumeUsageData struct {
 
+	// The size limit of the volume (in bytes), as set with the `size`
+	// option of the `"local"` volume driver. This field is omitted if
+	// the volume has no size limit.
+	Limit int64 `json:"Limit,omitempty"`
+
 	// The number of containers referencing this volume. This field
 	// is set to `-1` if the reference-count is not available.
 	//
@@ -0,0 +1,11 @@
+package volume // import "github.com/docker/docker/api/types/volume"
+
+// VolumeCloneBody is the request body used to clone or snapshot a volume.
+type VolumeCloneBody struct {
+
+	// Name of the new volume. If not specified, a name is generated.
+	Name string `json:"Name,omitempty"`
+
+	// User-defined key/value metadata for the new volume.
+	Labels map[string]string `json:"Labels,omitempty"`
+}
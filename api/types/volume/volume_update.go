@@ -0,0 +1,13 @@
+package volume // import "github.com/docker/docker/api/types/volume"
+
+// VolumeUpdateBody is the request body used to update a volume.
+type VolumeUpdateBody struct {
+
+	// A mapping of driver options and values, replacing the current options
+	// of the volume. The options are left unchanged if omitted.
+	DriverOpts map[string]string `json:"DriverOpts"`
+
+	// User-defined key/value metadata, replacing the current labels of the
+	// volume. The labels are left unchanged if omitted.
+	Labels map[string]string `json:"Labels"`
+}
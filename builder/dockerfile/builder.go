@@ -242,7 +242,7 @@ func buildLabelOptions(labels map[string]string, stages []instructions.Stage) {
 func (b *Builder) build(source builder.Source, dockerfile *parser.Result) (*builder.Result, error) {
 	defer b.imageSources.Unmount()
 
-	stages, metaArgs, err := instructions.Parse(dockerfile.AST)
+	stages, metaArgs, err := parseStages(dockerfile.AST)
 	if err != nil {
 		if instructions.IsUnknownInstruction(err) {
 			buildsFailed.WithValues(metricsUnknownInstructionError).Inc()
@@ -405,7 +405,7 @@ func BuildFromConfig(config *container.Config, changes []string, os string) (*co
 
 	var commands []instructions.Command
 	for _, n := range dockerfile.AST.Children {
-		cmd, err := instructions.ParseCommand(n)
+		cmd, err := parseCommand(n)
 		if err != nil {
 			return nil, errdefs.InvalidParameter(err)
 		}
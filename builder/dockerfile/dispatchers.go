@@ -203,7 +203,7 @@ func dispatchTriggeredOnBuild(d dispatchRequest, triggers []string) error {
 		if len(ast.AST.Children) != 1 {
 			return errors.New("onbuild trigger should be a single expression")
 		}
-		cmd, err := instructions.ParseCommand(ast.AST.Children[0])
+		cmd, err := parseCommand(ast.AST.Children[0])
 		if err != nil {
 			if instructions.IsUnknownInstruction(err) {
 				buildsFailed.WithValues(metricsUnknownInstructionError).Inc()
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/backend"
@@ -299,6 +300,73 @@ func TestHealthcheckCmd(t *testing.T) {
 	assert.Check(t, is.DeepEqual(expectedTest, sb.state.runConfig.Healthcheck.Test))
 }
 
+func TestHealthcheckStartInterval(t *testing.T) {
+	result, err := parser.Parse(strings.NewReader(`HEALTHCHECK --interval=5m --start-period=1m --start-interval=2s CMD ["true"]`))
+	assert.NilError(t, err)
+	cmd, err := parseCommand(result.AST.Children[0])
+	assert.NilError(t, err)
+
+	b := newBuilderWithMockBackend()
+	sb := newDispatchRequest(b, '`', nil, NewBuildArgs(make(map[string]*string)), newStagesBuildResults())
+	err = dispatch(sb, cmd)
+	assert.NilError(t, err)
+
+	assert.Assert(t, sb.state.runConfig.Healthcheck != nil)
+	assert.Check(t, is.Equal(5*time.Minute, sb.state.runConfig.Healthcheck.Interval))
+	assert.Check(t, is.Equal(time.Minute, sb.state.runConfig.Healthcheck.StartPeriod))
+	assert.Check(t, is.Equal(2*time.Second, sb.state.runConfig.Healthcheck.StartInterval))
+
+	result, err = parser.Parse(strings.NewReader(`HEALTHCHECK --start-interval=1us CMD ["true"]`))
+	assert.NilError(t, err)
+	_, err = parseCommand(result.AST.Children[0])
+	assert.Check(t, err != nil)
+}
+
+func TestHealthcheckNetworkProbes(t *testing.T) {
+	testCases := []struct {
+		dockerfile   string
+		expectedTest []string
+	}{
+		{
+			dockerfile:   `HEALTHCHECK HTTP-GET http://localhost:8080/health`,
+			expectedTest: []string{"HTTP-GET", "http://localhost:8080/health"},
+		},
+		{
+			dockerfile:   `HEALTHCHECK --interval=5s HTTP HEAD http://localhost/`,
+			expectedTest: []string{"HTTP", "HEAD", "http://localhost/"},
+		},
+		{
+			dockerfile:   `HEALTHCHECK TCP ["localhost:5432"]`,
+			expectedTest: []string{"TCP", "localhost:5432"},
+		},
+	}
+	for _, tc := range testCases {
+		result, err := parser.Parse(strings.NewReader(tc.dockerfile))
+		assert.NilError(t, err)
+		cmd, err := parseCommand(result.AST.Children[0])
+		assert.NilError(t, err)
+
+		b := newBuilderWithMockBackend()
+		sb := newDispatchRequest(b, '`', nil, NewBuildArgs(make(map[string]*string)), newStagesBuildResults())
+		err = dispatch(sb, cmd)
+		assert.NilError(t, err)
+
+		assert.Assert(t, sb.state.runConfig.Healthcheck != nil)
+		assert.Check(t, is.DeepEqual(tc.expectedTest, []string(sb.state.runConfig.Healthcheck.Test)))
+	}
+
+	for _, dockerfile := range []string{
+		`HEALTHCHECK HTTP-GET`,
+		`HEALTHCHECK HTTP http://localhost/`,
+		`HEALTHCHECK TCP localhost:5432 localhost:5433`,
+	} {
+		result, err := parser.Parse(strings.NewReader(dockerfile))
+		assert.NilError(t, err)
+		_, err = parseCommand(result.AST.Children[0])
+		assert.Check(t, err != nil, dockerfile)
+	}
+}
+
 func TestEntrypoint(t *testing.T) {
 	b := newBuilderWithMockBackend()
 	sb := newDispatchRequest(b, '`', nil, NewBuildArgs(make(map[string]*string)), newStagesBuildResults())
@@ -0,0 +1,160 @@
+package dockerfile // import "github.com/docker/docker/builder/dockerfile"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/moby/buildkit/frontend/dockerfile/command"
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+)
+
+// healthcheckExtension holds the parts of a HEALTHCHECK instruction which
+// are handled by the engine rather than by the Dockerfile parser: the HTTP,
+// HTTP-GET and TCP probes, and the --start-interval option.
+type healthcheckExtension struct {
+	test          strslice.StrSlice
+	startInterval time.Duration
+}
+
+// apply sets the parts of the extension to the parsed HEALTHCHECK.
+func (ext *healthcheckExtension) apply(c *instructions.HealthCheckCommand) {
+	if c.Health == nil || (len(c.Health.Test) > 0 && c.Health.Test[0] == "NONE") {
+		return
+	}
+	if ext.test != nil {
+		c.Health.Test = ext.test
+	}
+	c.Health.StartInterval = ext.startInterval
+}
+
+// extractHealthcheck returns a copy of the HEALTHCHECK node which the
+// Dockerfile parser accepts, and the extension removed from it.
+func extractHealthcheck(n *parser.Node) (*parser.Node, *healthcheckExtension, error) {
+	stripped := *n
+	ext := &healthcheckExtension{}
+
+	stripped.Flags = nil
+	for _, fl := range n.Flags {
+		if fl != "--start-interval" && !strings.HasPrefix(fl, "--start-interval=") {
+			stripped.Flags = append(stripped.Flags, fl)
+			continue
+		}
+		d, err := parseStartInterval(strings.TrimPrefix(strings.TrimPrefix(fl, "--start-interval"), "="))
+		if err != nil {
+			return nil, nil, err
+		}
+		ext.startInterval = d
+	}
+
+	if n.Next == nil {
+		return &stripped, ext, nil
+	}
+	typ := strings.ToUpper(n.Next.Value)
+	switch typ {
+	case "HTTP", "HTTP-GET", "TCP":
+	default:
+		return &stripped, ext, nil
+	}
+
+	var args []string
+	for arg := n.Next.Next; arg != nil; arg = arg.Next {
+		args = append(args, arg.Value)
+	}
+	if !n.Attributes["json"] && len(args) == 1 {
+		args = strings.Fields(args[0])
+	}
+	expected := 1
+	if typ == "HTTP" {
+		// HTTP takes the method and the URL
+		expected = 2
+	}
+	if len(args) != expected {
+		return nil, nil, fmt.Errorf("HEALTHCHECK %s takes %d arguments (not %d)", typ, expected, len(args))
+	}
+	ext.test = strslice.StrSlice(append([]string{typ}, args...))
+
+	// The probe is parsed as a placeholder command, with its options, and
+	// replaced by apply.
+	stripped.Next = &parser.Node{Value: "CMD", Next: &parser.Node{Value: "true"}}
+	stripped.Attributes = nil
+	return &stripped, ext, nil
+}
+
+// parseStartInterval parses the value of the --start-interval option, which
+// must be at least container.MinimumDuration, like the other intervals.
+func parseStartInterval(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("Missing a value on flag: start-interval")
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < container.MinimumDuration {
+		return 0, fmt.Errorf("Interval \"start-interval\" cannot be less than %s", container.MinimumDuration)
+	}
+	return d, nil
+}
+
+// parseCommand converts the node to a typed command, like
+// instructions.ParseCommand, with the HEALTHCHECK extensions.
+func parseCommand(n *parser.Node) (instructions.Command, error) {
+	if n.Value != command.Healthcheck {
+		return instructions.ParseCommand(n)
+	}
+	stripped, ext, err := extractHealthcheck(n)
+	if err != nil {
+		return nil, err
+	}
+	cmd, err := instructions.ParseCommand(stripped)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := cmd.(*instructions.HealthCheckCommand); ok {
+		ext.apply(c)
+	}
+	return cmd, nil
+}
+
+// parseStages parses the Dockerfile into stages, like instructions.Parse,
+// with the HEALTHCHECK extensions.
+func parseStages(ast *parser.Node) ([]instructions.Stage, []instructions.ArgCommand, error) {
+	root := *ast
+	root.Children = make([]*parser.Node, len(ast.Children))
+
+	var exts []*healthcheckExtension
+	for i, n := range ast.Children {
+		root.Children[i] = n
+		if n.Value != command.Healthcheck {
+			continue
+		}
+		stripped, ext, err := extractHealthcheck(n)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Dockerfile parse error line %d: %v", n.StartLine, err)
+		}
+		root.Children[i] = stripped
+		exts = append(exts, ext)
+	}
+
+	stages, metaArgs, err := instructions.Parse(&root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// HEALTHCHECK instructions are commands of the stages, in the order of
+	// the Dockerfile.
+	for _, s := range stages {
+		for _, cmd := range s.Commands {
+			if c, ok := cmd.(*instructions.HealthCheckCommand); ok {
+				exts[0].apply(c)
+				exts = exts[1:]
+			}
+		}
+	}
+	return stages, metaArgs, nil
+}
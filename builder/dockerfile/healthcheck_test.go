@@ -0,0 +1,51 @@
+package dockerfile // import "github.com/docker/docker/builder/dockerfile"
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/frontend/dockerfile/instructions"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParseStagesHealthcheckProbes(t *testing.T) {
+	dockerfile := `
+FROM busybox AS first
+HEALTHCHECK TCP localhost:5432
+FROM busybox
+HEALTHCHECK CMD ["true"]
+HEALTHCHECK --interval=5s --start-interval=1s HTTP-GET http://localhost/health
+`
+	result, err := parser.Parse(strings.NewReader(dockerfile))
+	assert.NilError(t, err)
+	stages, _, err := parseStages(result.AST)
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(stages, 2))
+
+	var tests [][]string
+	for _, s := range stages {
+		for _, cmd := range s.Commands {
+			c, ok := cmd.(*instructions.HealthCheckCommand)
+			assert.Assert(t, ok)
+			tests = append(tests, c.Health.Test)
+		}
+	}
+	assert.Check(t, is.DeepEqual([][]string{
+		{"TCP", "localhost:5432"},
+		{"CMD", "true"},
+		{"HTTP-GET", "http://localhost/health"},
+	}, tests))
+	assert.Check(t, is.Equal(stages[1].Commands[1].(fmt.Stringer).String(), "HEALTHCHECK --interval=5s --start-interval=1s HTTP-GET http://localhost/health"))
+	health := stages[1].Commands[1].(*instructions.HealthCheckCommand).Health
+	assert.Check(t, is.Equal(health.Interval, 5*time.Second))
+	assert.Check(t, is.Equal(health.StartInterval, time.Second))
+
+	result, err = parser.Parse(strings.NewReader("FROM busybox\nHEALTHCHECK HTTP GET\n"))
+	assert.NilError(t, err)
+	_, _, err = parseStages(result.AST)
+	assert.Check(t, is.ErrorContains(err, "line 2: HEALTHCHECK HTTP takes 2 arguments (not 1)"))
+}
@@ -42,6 +42,28 @@ func (cli *Client) ContainerCommit(ctx context.Context, container string, option
 	if !options.Pause {
 		query.Set("pause", "0")
 	}
+	if len(options.Exclude) > 0 || options.Squash || len(options.Annotations) > 0 {
+		if err := cli.NewVersionError("1.41", "commit with exclude, squash or annotations"); err != nil {
+			return types.IDResponse{}, err
+		}
+	}
+	if len(options.Exclude) > 0 {
+		excludeJSON, err := json.Marshal(options.Exclude)
+		if err != nil {
+			return types.IDResponse{}, err
+		}
+		query.Set("exclude", string(excludeJSON))
+	}
+	if options.Squash {
+		query.Set("squash", "1")
+	}
+	if len(options.Annotations) > 0 {
+		annotationsJSON, err := json.Marshal(options.Annotations)
+		if err != nil {
+			return types.IDResponse{}, err
+		}
+		query.Set("annotations", string(annotationsJSON))
+	}
 
 	var response types.IDResponse
 	resp, err := cli.post(ctx, "/commit", query, options.Config, nil)
@@ -98,3 +98,42 @@ func TestContainerCommit(t *testing.T) {
 		t.Fatalf("expected `new_container_id`, got %s", r.ID)
 	}
 }
+
+func TestContainerCommitExcludeSquashAnnotations(t *testing.T) {
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			query := req.URL.Query()
+			if exclude := query.Get("exclude"); exclude != `["/tmp/**","/var/cache/**"]` {
+				return nil, fmt.Errorf("exclude not set in URL query properly. Expected '[\"/tmp/**\",\"/var/cache/**\"]', got %s", exclude)
+			}
+			if squash := query.Get("squash"); squash != "1" {
+				return nil, fmt.Errorf("squash not set in URL query properly. Expected '1', got %s", squash)
+			}
+			if annotations := query.Get("annotations"); annotations != `{"org.example.reason":"debug"}` {
+				return nil, fmt.Errorf("annotations not set in URL query properly. Expected '{\"org.example.reason\":\"debug\"}', got %s", annotations)
+			}
+			b, err := json.Marshal(types.IDResponse{
+				ID: "new_image_id",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	r, err := client.ContainerCommit(context.Background(), "container_id", types.ContainerCommitOptions{
+		Exclude:     []string{"/tmp/**", "/var/cache/**"},
+		Squash:      true,
+		Annotations: map[string]string{"org.example.reason": "debug"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "new_image_id" {
+		t.Fatalf("expected `new_image_id`, got %s", r.ID)
+	}
+}
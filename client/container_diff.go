@@ -21,3 +21,28 @@ func (cli *Client) ContainerDiff(ctx context.Context, containerID string) ([]con
 	err = json.NewDecoder(serverResp.body).Decode(&changes)
 	return changes, err
 }
+
+// ContainerDiffWithSize shows differences in a container filesystem since it
+// was started, with the mode and size of the added and modified files, and
+// the digest of their content if digests is true.
+func (cli *Client) ContainerDiffWithSize(ctx context.Context, containerID string, digests bool) (container.ContainerChangesOKBody, error) {
+	var changes container.ContainerChangesOKBody
+	if err := cli.NewVersionError("1.41", "diff with size"); err != nil {
+		return changes, err
+	}
+
+	query := url.Values{}
+	query.Set("size", "1")
+	if digests {
+		query.Set("digest", "1")
+	}
+
+	serverResp, err := cli.get(ctx, "/containers/"+containerID+"/changes", query, nil)
+	defer ensureReaderClosed(serverResp)
+	if err != nil {
+		return changes, err
+	}
+
+	err = json.NewDecoder(serverResp.body).Decode(&changes)
+	return changes, err
+}
@@ -62,3 +62,48 @@ func TestContainerDiff(t *testing.T) {
 		t.Fatalf("expected an array of 2 changes, got %v", changes)
 	}
 }
+
+func TestContainerDiffWithSize(t *testing.T) {
+	expectedURL := "/containers/container_id/changes"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			query := req.URL.Query()
+			if size := query.Get("size"); size != "1" {
+				return nil, fmt.Errorf("size not set in URL query properly. Expected '1', got %s", size)
+			}
+			if digest := query.Get("digest"); digest != "1" {
+				return nil, fmt.Errorf("digest not set in URL query properly. Expected '1', got %s", digest)
+			}
+			b, err := json.Marshal(container.ContainerChangesOKBody{
+				Changes: []container.ContainerChangeResponseItem{
+					{
+						Kind:   1,
+						Path:   "/path/1",
+						Size:   4,
+						Digest: "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+					},
+				},
+				Added: 1,
+				Size:  4,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	changes, err := client.ContainerDiffWithSize(context.Background(), "container_id", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes.Changes) != 1 || changes.Added != 1 || changes.Size != 4 {
+		t.Fatalf("expected one added change of 4 bytes, got %v", changes)
+	}
+}
@@ -17,3 +17,39 @@ func (cli *Client) ContainerExport(ctx context.Context, containerID string) (io.
 
 	return serverResp.body, nil
 }
+
+// ContainerExportRWLayer retrieves the changes of the writable layer of a
+// container to its image, as a tar stream where the deleted files are OCI
+// whiteout files, and returns them as an io.ReadCloser, along with the ID of
+// the image. It's up to the caller to close the stream.
+func (cli *Client) ContainerExportRWLayer(ctx context.Context, containerID string) (io.ReadCloser, string, error) {
+	if err := cli.NewVersionError("1.41", "export of the rw layer"); err != nil {
+		return nil, "", err
+	}
+	query := url.Values{}
+	query.Set("layer", "rw")
+	serverResp, err := cli.get(ctx, "/containers/"+containerID+"/export", query, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return serverResp.body, serverResp.header.Get("X-Docker-Image-Id"), nil
+}
+
+// ContainerImportRWLayer applies the tar stream returned by
+// ContainerExportRWLayer for another container created from the same image
+// to the writable layer of a container which is not running. If imageID is
+// set, the daemon checks that the container was created from that image.
+func (cli *Client) ContainerImportRWLayer(ctx context.Context, containerID, imageID string, content io.Reader) error {
+	if err := cli.NewVersionError("1.41", "import of the rw layer"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	if imageID != "" {
+		query.Set("image", imageID)
+	}
+	headers := map[string][]string{"Content-Type": {"application/x-tar"}}
+	resp, err := cli.postRaw(ctx, "/containers/"+containerID+"/import", query, content, headers)
+	ensureReaderClosed(resp)
+	return err
+}
@@ -52,3 +52,84 @@ func TestContainerExport(t *testing.T) {
 		t.Fatalf("expected response to contain 'response', got %s", string(content))
 	}
 }
+
+func TestContainerExportRWLayer(t *testing.T) {
+	expectedURL := "/containers/container_id/export"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if layer := req.URL.Query().Get("layer"); layer != "rw" {
+				return nil, fmt.Errorf("layer not set in URL query properly. Expected 'rw', got %s", layer)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+				Header:     http.Header{"X-Docker-Image-Id": []string{"sha256:abcd"}},
+			}, nil
+		}),
+	}
+	body, imageID, err := client.ContainerExportRWLayer(context.Background(), "container_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	if imageID != "sha256:abcd" {
+		t.Fatalf("expected image ID 'sha256:abcd', got %s", imageID)
+	}
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "response" {
+		t.Fatalf("expected response to contain 'response', got %s", string(content))
+	}
+}
+
+func TestContainerImportRWLayerError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusConflict, "container is running")),
+	}
+	err := client.ContainerImportRWLayer(context.Background(), "nothing", "", bytes.NewReader([]byte("content")))
+	if err == nil || err.Error() != "Error response from daemon: container is running" {
+		t.Fatalf("expected a Conflict error, got %v", err)
+	}
+	if !errdefs.IsConflict(err) {
+		t.Fatalf("expected a Conflict error, got %T", err)
+	}
+}
+
+func TestContainerImportRWLayer(t *testing.T) {
+	expectedURL := "/containers/container_id/import"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if image := req.URL.Query().Get("image"); image != "sha256:abcd" {
+				return nil, fmt.Errorf("image not set in URL query properly. Expected 'sha256:abcd', got %s", image)
+			}
+			if contentType := req.Header.Get("Content-Type"); contentType != "application/x-tar" {
+				return nil, fmt.Errorf("expected Content-Type 'application/x-tar', got %s", contentType)
+			}
+			content, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if string(content) != "content" {
+				return nil, fmt.Errorf("expected body 'content', got %s", string(content))
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		}),
+	}
+	if err := client.ContainerImportRWLayer(context.Background(), "container_id", "sha256:abcd", bytes.NewReader([]byte("content"))); err != nil {
+		t.Fatal(err)
+	}
+}
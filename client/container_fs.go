@@ -0,0 +1,87 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+
+	"github.com/docker/docker/api/types"
+	"github.com/pkg/errors"
+)
+
+// ContainerFsStat returns stat information about a path inside the container
+// filesystem, without following a symlink as the last path element.
+func (cli *Client) ContainerFsStat(ctx context.Context, containerID, path string) (types.ContainerPathStat, error) {
+	var stat types.ContainerPathStat
+	if err := cli.NewVersionError("1.41", "container fs stat"); err != nil {
+		return stat, err
+	}
+	query := url.Values{}
+	query.Set("path", filepath.ToSlash(path)) // Normalize the paths used in the API.
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/fs/stat", query, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return stat, wrapResponseError(err, resp, "container:path", containerID+":"+path)
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&stat)
+	return stat, err
+}
+
+// ContainerFsList returns stat information about the entries of a directory
+// inside the container filesystem, sorted by name.
+func (cli *Client) ContainerFsList(ctx context.Context, containerID, path string) ([]types.ContainerPathStat, error) {
+	var entries []types.ContainerPathStat
+	if err := cli.NewVersionError("1.41", "container fs list"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	query.Set("path", filepath.ToSlash(path)) // Normalize the paths used in the API.
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/fs/ls", query, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return nil, wrapResponseError(err, resp, "container:path", containerID+":"+path)
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&entries)
+	return entries, err
+}
+
+// ContainerFsRead returns the content of a regular file inside the container
+// filesystem, or of the range of it given in the options, along with stat
+// information about the file. It's up to the caller to close the reader.
+func (cli *Client) ContainerFsRead(ctx context.Context, containerID, path string, options types.ContainerFsReadOptions) (io.ReadCloser, types.ContainerPathStat, error) {
+	if err := cli.NewVersionError("1.41", "container fs read"); err != nil {
+		return nil, types.ContainerPathStat{}, err
+	}
+	if options.Offset < 0 || options.Length < 0 {
+		return nil, types.ContainerPathStat{}, errors.New("offset and length must not be negative")
+	}
+	query := url.Values{}
+	query.Set("path", filepath.ToSlash(path)) // Normalize the paths used in the API.
+
+	var headers map[string][]string
+	switch {
+	case options.Length > 0:
+		headers = map[string][]string{"Range": {fmt.Sprintf("bytes=%d-%d", options.Offset, options.Offset+options.Length-1)}}
+	case options.Offset > 0:
+		headers = map[string][]string{"Range": {fmt.Sprintf("bytes=%d-", options.Offset)}}
+	}
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/fs/read", query, headers)
+	if err != nil {
+		return nil, types.ContainerPathStat{}, wrapResponseError(err, resp, "container:path", containerID+":"+path)
+	}
+
+	stat, err := getContainerPathStatFromHeader(resp.header)
+	if err != nil {
+		ensureReaderClosed(resp)
+		return nil, stat, fmt.Errorf("unable to get resource stat from response: %s", err)
+	}
+	return resp.body, stat, nil
+}
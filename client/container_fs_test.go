@@ -0,0 +1,180 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestContainerFsUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  &http.Client{},
+	}
+	_, err := client.ContainerFsStat(context.Background(), "container_id", "path")
+	if err == nil || err.Error() != `"container fs stat" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, err = client.ContainerFsList(context.Background(), "container_id", "path")
+	if err == nil || err.Error() != `"container fs list" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, _, err = client.ContainerFsRead(context.Background(), "container_id", "path", types.ContainerFsReadOptions{})
+	if err == nil || err.Error() != `"container fs read" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestContainerFsStatNotFoundError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "Not found")),
+	}
+	_, err := client.ContainerFsStat(context.Background(), "container_id", "path")
+	if !IsErrNotFound(err) {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestContainerFsStat(t *testing.T) {
+	expectedURL := "/containers/container_id/fs/stat"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodGet {
+				return nil, fmt.Errorf("expected GET method, got %s", req.Method)
+			}
+			if path := req.URL.Query().Get("path"); path != "path/to/file" {
+				return nil, fmt.Errorf("path not set in URL query properly, got %s", path)
+			}
+			b, err := json.Marshal(types.ContainerPathStat{Name: "file", Size: 42})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	stat, err := client.ContainerFsStat(context.Background(), "container_id", "path/to/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat.Name != "file" || stat.Size != 42 {
+		t.Fatalf("expected name 'file' and size 42, got %q and %d", stat.Name, stat.Size)
+	}
+}
+
+func TestContainerFsListError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusBadRequest, "path/to/file is not a directory")),
+	}
+	_, err := client.ContainerFsList(context.Background(), "container_id", "path/to/file")
+	if !errdefs.IsInvalidParameter(err) {
+		t.Fatalf("expected an invalid parameter error, got %v", err)
+	}
+}
+
+func TestContainerFsList(t *testing.T) {
+	expectedURL := "/containers/container_id/fs/ls"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if path := req.URL.Query().Get("path"); path != "path/to/dir" {
+				return nil, fmt.Errorf("path not set in URL query properly, got %s", path)
+			}
+			b, err := json.Marshal([]types.ContainerPathStat{{Name: "a"}, {Name: "b"}})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+	entries, err := client.ContainerFsList(context.Background(), "container_id", "path/to/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name != "a" || entries[1].Name != "b" {
+		t.Fatalf("expected entries a and b, got %v", entries)
+	}
+}
+
+func TestContainerFsReadNegativeOffset(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, _, err := client.ContainerFsRead(context.Background(), "container_id", "path", types.ContainerFsReadOptions{Offset: -1})
+	if err == nil {
+		t.Fatal("expected an error for a negative offset")
+	}
+}
+
+func TestContainerFsRead(t *testing.T) {
+	expectedURL := "/containers/container_id/fs/read"
+	cases := []struct {
+		options       types.ContainerFsReadOptions
+		expectedRange string
+	}{
+		{options: types.ContainerFsReadOptions{}, expectedRange: ""},
+		{options: types.ContainerFsReadOptions{Offset: 10}, expectedRange: "bytes=10-"},
+		{options: types.ContainerFsReadOptions{Length: 10}, expectedRange: "bytes=0-9"},
+		{options: types.ContainerFsReadOptions{Offset: 10, Length: 5}, expectedRange: "bytes=10-14"},
+	}
+	for _, tc := range cases {
+		client := &Client{
+			client: newMockClient(func(req *http.Request) (*http.Response, error) {
+				if !strings.HasPrefix(req.URL.Path, expectedURL) {
+					return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+				}
+				if path := req.URL.Query().Get("path"); path != "path/to/file" {
+					return nil, fmt.Errorf("path not set in URL query properly, got %s", path)
+				}
+				if r := req.Header.Get("Range"); r != tc.expectedRange {
+					return nil, fmt.Errorf("expected Range header %q, got %q", tc.expectedRange, r)
+				}
+				b, err := json.Marshal(types.ContainerPathStat{Name: "file"})
+				if err != nil {
+					return nil, err
+				}
+				resp := &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte("content"))),
+					Header:     http.Header{},
+				}
+				resp.Header.Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(b))
+				return resp, nil
+			}),
+		}
+		r, stat, err := client.ContainerFsRead(context.Background(), "container_id", "path/to/file", tc.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != "content" {
+			t.Fatalf("expected content 'content', got %s", content)
+		}
+		if stat.Name != "file" {
+			t.Fatalf("expected name 'file', got %s", stat.Name)
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -115,6 +116,17 @@ func TestContainerLogs(t *testing.T) {
 			},
 			expectedError: `invalid value for "until": failed to parse value as time or duration: "invalid value"`,
 		},
+		{
+			options: types.ContainerLogsOptions{
+				ShowStderr: true,
+				Filters:    filters.NewArgs(filters.Arg("grep", "ERROR")),
+			},
+			expectedQueryParams: map[string]string{
+				"tail":    "",
+				"stderr":  "1",
+				"filters": `{"grep":{"ERROR":true}}`,
+			},
+		},
 	}
 	for _, logCase := range cases {
 		client := &Client{
@@ -0,0 +1,48 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/pkg/errors"
+)
+
+// ContainerStatsHistory returns the samples of the stats history of a
+// container, which are kept by the daemon if its stats history is enabled.
+func (cli *Client) ContainerStatsHistory(ctx context.Context, containerID string, options types.ContainerStatsHistoryOptions) (types.StatsHistory, error) {
+	var history types.StatsHistory
+	if err := cli.NewVersionError("1.41", "container stats history"); err != nil {
+		return history, err
+	}
+	query := url.Values{}
+	if options.Since != "" {
+		ts, err := timetypes.GetTimestamp(options.Since, time.Now())
+		if err != nil {
+			return history, errors.Wrap(err, `invalid value for "since"`)
+		}
+		query.Set("since", ts)
+	}
+	if options.Until != "" {
+		ts, err := timetypes.GetTimestamp(options.Until, time.Now())
+		if err != nil {
+			return history, errors.Wrap(err, `invalid value for "until"`)
+		}
+		query.Set("until", ts)
+	}
+	if options.Step != 0 {
+		query.Set("step", options.Step.String())
+	}
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/stats/history", query, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return history, wrapResponseError(err, resp, "container", containerID)
+	}
+
+	err = json.NewDecoder(resp.body).Decode(&history)
+	return history, err
+}
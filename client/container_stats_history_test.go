@@ -0,0 +1,77 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestContainerStatsHistoryUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  &http.Client{},
+	}
+	_, err := client.ContainerStatsHistory(context.Background(), "container_id", types.ContainerStatsHistoryOptions{})
+	if err == nil || err.Error() != `"container stats history" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestContainerStatsHistoryError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.ContainerStatsHistory(context.Background(), "nothing", types.ContainerStatsHistoryOptions{})
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestContainerStatsHistory(t *testing.T) {
+	expectedURL := "/containers/container_id/stats/history"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			query := req.URL.Query()
+			if since := query.Get("since"); since != "1500000000" {
+				return nil, fmt.Errorf("since not set in URL query properly. Expected '1500000000', got %s", since)
+			}
+			if step := query.Get("step"); step != "1m0s" {
+				return nil, fmt.Errorf("step not set in URL query properly. Expected '1m0s', got %s", step)
+			}
+			b, err := json.Marshal(types.StatsHistory{
+				ID:      "container_id",
+				Samples: []types.StatsHistorySample{{Samples: 6, MemoryUsage: 1024}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}),
+	}
+
+	history, err := client.ContainerStatsHistory(context.Background(), "container_id", types.ContainerStatsHistoryOptions{
+		Since: "1500000000",
+		Step:  time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Samples) != 1 || history.Samples[0].MemoryUsage != 1024 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+}
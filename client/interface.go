@@ -49,12 +49,18 @@ type ContainerAPIClient interface {
 	ContainerCommit(ctx context.Context, container string, options types.ContainerCommitOptions) (types.IDResponse, error)
 	ContainerCreate(ctx context.Context, config *containertypes.Config, hostConfig *containertypes.HostConfig, networkingConfig *networktypes.NetworkingConfig, containerName string) (containertypes.ContainerCreateCreatedBody, error)
 	ContainerDiff(ctx context.Context, container string) ([]containertypes.ContainerChangeResponseItem, error)
+	ContainerDiffWithSize(ctx context.Context, container string, digests bool) (containertypes.ContainerChangesOKBody, error)
 	ContainerExecAttach(ctx context.Context, execID string, config types.ExecStartCheck) (types.HijackedResponse, error)
 	ContainerExecCreate(ctx context.Context, container string, config types.ExecConfig) (types.IDResponse, error)
 	ContainerExecInspect(ctx context.Context, execID string) (types.ContainerExecInspect, error)
 	ContainerExecResize(ctx context.Context, execID string, options types.ResizeOptions) error
 	ContainerExecStart(ctx context.Context, execID string, config types.ExecStartCheck) error
 	ContainerExport(ctx context.Context, container string) (io.ReadCloser, error)
+	ContainerExportRWLayer(ctx context.Context, container string) (io.ReadCloser, string, error)
+	ContainerImportRWLayer(ctx context.Context, container, imageID string, content io.Reader) error
+	ContainerFsList(ctx context.Context, container, path string) ([]types.ContainerPathStat, error)
+	ContainerFsRead(ctx context.Context, container, path string, options types.ContainerFsReadOptions) (io.ReadCloser, types.ContainerPathStat, error)
+	ContainerFsStat(ctx context.Context, container, path string) (types.ContainerPathStat, error)
 	ContainerInspect(ctx context.Context, container string) (types.ContainerJSON, error)
 	ContainerInspectWithRaw(ctx context.Context, container string, getSize bool) (types.ContainerJSON, []byte, error)
 	ContainerKill(ctx context.Context, container, signal string) error
@@ -67,6 +73,7 @@ type ContainerAPIClient interface {
 	ContainerRestart(ctx context.Context, container string, timeout *time.Duration) error
 	ContainerStatPath(ctx context.Context, container, path string) (types.ContainerPathStat, error)
 	ContainerStats(ctx context.Context, container string, stream bool) (types.ContainerStats, error)
+	ContainerStatsHistory(ctx context.Context, container string, options types.ContainerStatsHistoryOptions) (types.StatsHistory, error)
 	ContainerStart(ctx context.Context, container string, options types.ContainerStartOptions) error
 	ContainerStop(ctx context.Context, container string, timeout *time.Duration) error
 	ContainerTop(ctx context.Context, container string, arguments []string) (containertypes.ContainerTopOKBody, error)
@@ -172,11 +179,17 @@ type SystemAPIClient interface {
 
 // VolumeAPIClient defines API client methods for the volumes
 type VolumeAPIClient interface {
+	VolumeClone(ctx context.Context, volumeID string, options volumetypes.VolumeCloneBody) (types.Volume, error)
 	VolumeCreate(ctx context.Context, options volumetypes.VolumeCreateBody) (types.Volume, error)
+	VolumeExport(ctx context.Context, volumeID string, options types.VolumeExportOptions) (io.ReadCloser, error)
+	VolumeImport(ctx context.Context, volumeID string, content io.Reader) error
 	VolumeInspect(ctx context.Context, volumeID string) (types.Volume, error)
 	VolumeInspectWithRaw(ctx context.Context, volumeID string) (types.Volume, []byte, error)
 	VolumeList(ctx context.Context, filter filters.Args) (volumetypes.VolumeListOKBody, error)
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+	VolumeRename(ctx context.Context, volumeID, newVolumeName string) error
+	VolumeSnapshot(ctx context.Context, volumeID string, options volumetypes.VolumeCloneBody) (types.Volume, error)
+	VolumeUpdate(ctx context.Context, volumeID string, options volumetypes.VolumeUpdateBody) (types.Volume, error)
 	VolumesPrune(ctx context.Context, pruneFilter filters.Args) (types.VolumesPruneReport, error)
 }
 
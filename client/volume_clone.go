@@ -0,0 +1,36 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types"
+	volumetypes "github.com/docker/docker/api/types/volume"
+)
+
+// VolumeClone creates a new volume holding a copy of the data of the given volume.
+func (cli *Client) VolumeClone(ctx context.Context, volumeID string, options volumetypes.VolumeCloneBody) (types.Volume, error) {
+	if err := cli.NewVersionError("1.41", "volume clone"); err != nil {
+		return types.Volume{}, err
+	}
+	return cli.volumeCopy(ctx, "/volumes/"+volumeID+"/clone", volumeID, options)
+}
+
+// VolumeSnapshot creates a point-in-time snapshot of the given volume.
+func (cli *Client) VolumeSnapshot(ctx context.Context, volumeID string, options volumetypes.VolumeCloneBody) (types.Volume, error) {
+	if err := cli.NewVersionError("1.41", "volume snapshot"); err != nil {
+		return types.Volume{}, err
+	}
+	return cli.volumeCopy(ctx, "/volumes/"+volumeID+"/snapshot", volumeID, options)
+}
+
+func (cli *Client) volumeCopy(ctx context.Context, path, volumeID string, options volumetypes.VolumeCloneBody) (types.Volume, error) {
+	var volume types.Volume
+	resp, err := cli.post(ctx, path, nil, options, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return volume, wrapResponseError(err, resp, "volume", volumeID)
+	}
+	err = json.NewDecoder(resp.body).Decode(&volume)
+	return volume, err
+}
@@ -0,0 +1,116 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	volumetypes "github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumeCloneUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  &http.Client{},
+	}
+	_, err := client.VolumeClone(context.Background(), "volume_id", volumetypes.VolumeCloneBody{Name: "clone"})
+	if err == nil || err.Error() != `"volume clone" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+	_, err = client.VolumeSnapshot(context.Background(), "volume_id", volumetypes.VolumeCloneBody{Name: "snapshot"})
+	if err == nil || err.Error() != `"volume snapshot" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestVolumeCloneError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+
+	_, err := client.VolumeClone(context.Background(), "volume_id", volumetypes.VolumeCloneBody{})
+	if err == nil || err.Error() != "Error response from daemon: Server error" {
+		t.Fatalf("expected a Server Error, got %v", err)
+	}
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %T", err)
+	}
+}
+
+func TestVolumeCloneNotFound(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "missing")),
+	}
+
+	_, err := client.VolumeClone(context.Background(), "unknown", volumetypes.VolumeCloneBody{})
+	if !IsErrNotFound(err) {
+		t.Fatalf("expected a NotFoundError error, got %v", err)
+	}
+}
+
+func TestVolumeCloneAndSnapshot(t *testing.T) {
+	for _, tc := range []struct {
+		expectedURL string
+		doRequest   func(*Client) (types.Volume, error)
+	}{
+		{
+			expectedURL: "/volumes/volume_id/clone",
+			doRequest: func(c *Client) (types.Volume, error) {
+				return c.VolumeClone(context.Background(), "volume_id", volumetypes.VolumeCloneBody{Name: "myvolume"})
+			},
+		},
+		{
+			expectedURL: "/volumes/volume_id/snapshot",
+			doRequest: func(c *Client) (types.Volume, error) {
+				return c.VolumeSnapshot(context.Background(), "volume_id", volumetypes.VolumeCloneBody{Name: "myvolume"})
+			},
+		},
+	} {
+		expectedURL := tc.expectedURL
+		client := &Client{
+			client: newMockClient(func(req *http.Request) (*http.Response, error) {
+				if !strings.HasPrefix(req.URL.Path, expectedURL) {
+					return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+				}
+				if req.Method != "POST" {
+					return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+				}
+
+				var body volumetypes.VolumeCloneBody
+				if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+					return nil, err
+				}
+				if body.Name != "myvolume" {
+					return nil, fmt.Errorf("expected Name to be 'myvolume', got %s", body.Name)
+				}
+
+				content, err := json.Marshal(types.Volume{
+					Name:   body.Name,
+					Driver: "local",
+				})
+				if err != nil {
+					return nil, err
+				}
+				return &http.Response{
+					StatusCode: http.StatusCreated,
+					Body:       ioutil.NopCloser(bytes.NewReader(content)),
+				}, nil
+			}),
+		}
+
+		volume, err := tc.doRequest(client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if volume.Name != "myvolume" {
+			t.Fatalf("expected volume.Name to be 'myvolume', got %s", volume.Name)
+		}
+	}
+}
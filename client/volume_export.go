@@ -0,0 +1,28 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+
+	"github.com/docker/docker/api/types"
+)
+
+// VolumeExport retrieves the content of a volume as a tar stream
+// and returns it as an io.ReadCloser. It's up to the caller
+// to close the stream.
+func (cli *Client) VolumeExport(ctx context.Context, volumeID string, options types.VolumeExportOptions) (io.ReadCloser, error) {
+	if err := cli.NewVersionError("1.41", "volume export"); err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+	if options.Compression != "" {
+		query.Set("compression", options.Compression)
+	}
+	serverResp, err := cli.get(ctx, "/volumes/"+volumeID+"/export", query, nil)
+	if err != nil {
+		return nil, wrapResponseError(err, serverResp, "volume", volumeID)
+	}
+
+	return serverResp.body, nil
+}
@@ -0,0 +1,78 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumeExportUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  &http.Client{},
+	}
+	_, err := client.VolumeExport(context.Background(), "volume_id", types.VolumeExportOptions{})
+	if err == nil || err.Error() != `"volume export" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestVolumeExportError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	_, err := client.VolumeExport(context.Background(), "volume_id", types.VolumeExportOptions{})
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestVolumeExportNotFound(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusNotFound, "Server error")),
+	}
+	_, err := client.VolumeExport(context.Background(), "unknown", types.VolumeExportOptions{})
+	if !IsErrNotFound(err) {
+		t.Fatalf("expected a not found error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestVolumeExport(t *testing.T) {
+	expectedURL := "/volumes/volume_id/export"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodGet {
+				return nil, fmt.Errorf("expected GET method, got %s", req.Method)
+			}
+			if c := req.URL.Query().Get("compression"); c != "gzip" {
+				return nil, fmt.Errorf("expected compression 'gzip', got '%s'", c)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader([]byte("response"))),
+			}, nil
+		}),
+	}
+	body, err := client.VolumeExport(context.Background(), "volume_id", types.VolumeExportOptions{Compression: "gzip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "response" {
+		t.Fatalf("expected response to contain 'response', got %s", string(content))
+	}
+}
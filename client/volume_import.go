@@ -0,0 +1,19 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"io"
+	"net/url"
+)
+
+// VolumeImport extracts the (optionally compressed) tar stream read from
+// content into the given volume.
+func (cli *Client) VolumeImport(ctx context.Context, volumeID string, content io.Reader) error {
+	if err := cli.NewVersionError("1.41", "volume import"); err != nil {
+		return err
+	}
+	headers := map[string][]string{"Content-Type": {"application/x-tar"}}
+	resp, err := cli.postRaw(ctx, "/volumes/"+volumeID+"/import", url.Values{}, content, headers)
+	defer ensureReaderClosed(resp)
+	return wrapResponseError(err, resp, "volume", volumeID)
+}
@@ -0,0 +1,65 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+)
+
+func TestVolumeImportUnsupported(t *testing.T) {
+	client := &Client{
+		version: "1.40",
+		client:  &http.Client{},
+	}
+	err := client.VolumeImport(context.Background(), "volume_id", strings.NewReader(""))
+	if err == nil || err.Error() != `"volume import" requires API version 1.41, but the Docker daemon API version is 1.40` {
+		t.Fatalf("expected a version error, got %v", err)
+	}
+}
+
+func TestVolumeImportError(t *testing.T) {
+	client := &Client{
+		client: newMockClient(errorMock(http.StatusInternalServerError, "Server error")),
+	}
+	err := client.VolumeImport(context.Background(), "volume_id", strings.NewReader("content"))
+	if !errdefs.IsSystem(err) {
+		t.Fatalf("expected a Server Error, got %[1]T: %[1]v", err)
+	}
+}
+
+func TestVolumeImport(t *testing.T) {
+	expectedURL := "/volumes/volume_id/import"
+	client := &Client{
+		client: newMockClient(func(req *http.Request) (*http.Response, error) {
+			if !strings.HasPrefix(req.URL.Path, expectedURL) {
+				return nil, fmt.Errorf("Expected URL '%s', got '%s'", expectedURL, req.URL)
+			}
+			if req.Method != http.MethodPost {
+				return nil, fmt.Errorf("expected POST method, got %s", req.Method)
+			}
+			if ct := req.Header.Get("Content-Type"); ct != "application/x-tar" {
+				return nil, fmt.Errorf("expected Content-Type 'application/x-tar', got '%s'", ct)
+			}
+			b, err := ioutil.ReadAll(req.Body)
+			if err != nil {
+				return nil, err
+			}
+			if string(b) != "content" {
+				return nil, fmt.Errorf("expected body 'content', got '%s'", b)
+			}
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		}),
+	}
+	if err := client.VolumeImport(context.Background(), "volume_id", strings.NewReader("content")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,18 @@
+package client // import "github.com/docker/docker/client"
+
+import (
+	"context"
+	"net/url"
+)
+
+// VolumeRename changes the name of a given volume.
+func (cli *Client) VolumeRename(ctx context.Context, volumeID, newVolumeName string) error {
+	if err := cli.NewVersionError("1.41", "volume rename"); err != nil {
+		return err
+	}
+	query := url.Values{}
+	query.Set("name", newVolumeName)
+	resp, err := cli.post(ctx, "/volumes/"+volumeID+"/rename", query, nil, nil)
+	defer ensureReaderClosed(resp)
+	return wrapResponseError(err, resp, "volume", volumeID)
+}
@@ -80,8 +80,10 @@ type Container struct {
 	MountLabel             string
 	ProcessLabel           string
 	RestartCount           int
+	RestartTimes           []time.Time `json:",omitempty"` // times of the restarts within the restart window of the policy
 	HasBeenStartedBefore   bool
 	HasBeenManuallyStopped bool // used for unless-stopped restart policy
+	UnhealthyRestart       bool `json:"-"` // set when the container is stopped by the daemon to be restarted because it is unhealthy
 	MountPoints            map[string]*volumemounts.MountPoint
 	HostConfig             *containertypes.HostConfig `json:"-"` // do not serialize the host config in the json, otherwise we'll make the container unportable
 	ExecCommands           *exec.Store                `json:"-"`
@@ -415,6 +417,14 @@ func (container *Container) StartLogger() (logger.Logger, error) {
 		}
 		l = logger.NewRingLogger(l, info, bufferSize)
 	}
+
+	rateLimit, err := logger.ParseRateLimitConfig(cfg.Config)
+	if err != nil {
+		return nil, err
+	}
+	if rateLimit != nil {
+		l = logger.NewRateLimitedLogger(l, info, *rateLimit)
+	}
 	return l, nil
 }
 
@@ -562,7 +572,7 @@ func (container *Container) FullHostname() string {
 // RestartManager returns the current restartmanager instance connected to container.
 func (container *Container) RestartManager() restartmanager.RestartManager {
 	if container.restartManager == nil {
-		container.restartManager = restartmanager.New(container.HostConfig.RestartPolicy, container.RestartCount)
+		container.restartManager = restartmanager.New(container.HostConfig.RestartPolicy, container.RestartCount, container.RestartTimes)
 	}
 	return container.restartManager
 }
@@ -574,6 +584,7 @@ func (container *Container) ResetRestartManager(resetCount bool) {
 	}
 	if resetCount {
 		container.RestartCount = 0
+		container.RestartTimes = nil
 	}
 	container.restartManager = nil
 }
@@ -611,12 +622,23 @@ func (container *Container) startLogging() error {
 		return nil // do not start logging routines
 	}
 
+	multiline, err := logger.ParseMultilineConfig(container.HostConfig.LogConfig.Config)
+	if err != nil {
+		return fmt.Errorf("failed to initialize logging driver: %v", err)
+	}
+
 	l, err := container.StartLogger()
 	if err != nil {
 		return fmt.Errorf("failed to initialize logging driver: %v", err)
 	}
 
-	copier := logger.NewCopier(map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}, l)
+	srcs := map[string]io.Reader{"stdout": container.StdoutPipe(), "stderr": container.StderrPipe()}
+	var copier *logger.Copier
+	if multiline != nil {
+		copier = logger.NewMultilineCopier(srcs, l, *multiline)
+	} else {
+		copier = logger.NewCopier(srcs, l)
+	}
 	container.LogCopier = copier
 	copier.Run()
 	container.LogDriver = l
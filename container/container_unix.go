@@ -345,6 +345,12 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	if resources.PidsLimit != nil {
 		cResources.PidsLimit = resources.PidsLimit
 	}
+	if resources.Ulimits != nil {
+		cResources.Ulimits = resources.Ulimits
+	}
+	if resources.DeviceCgroupRules != nil {
+		cResources.DeviceCgroupRules = resources.DeviceCgroupRules
+	}
 
 	// update HostConfig of container
 	if hostConfig.RestartPolicy.Name != "" {
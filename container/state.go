@@ -31,6 +31,7 @@ type State struct {
 	ErrorMsg          string `json:"Error"` // contains last known error during container start, stop, or remove
 	StartedAt         time.Time
 	FinishedAt        time.Time
+	RestartDelay      time.Duration // delay before the pending, or last, restart
 	Health            *Health
 
 	waitStop   chan struct{}
@@ -284,6 +285,7 @@ func (s *State) SetStopped(exitStatus *ExitStatus) {
 	s.Running = false
 	s.Paused = false
 	s.Restarting = false
+	s.RestartDelay = 0
 	s.Pid = 0
 	if exitStatus.ExitedAt.IsZero() {
 		s.FinishedAt = time.Now().UTC()
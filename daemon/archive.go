@@ -3,6 +3,7 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/api/types"
@@ -119,6 +120,62 @@ func (daemon *Daemon) ContainerArchivePath(name string, path string) (content io
 	return nil, nil, errdefs.System(err)
 }
 
+// ContainerListPath lists the directory at the specified path in the
+// container identified by the given name. Returns stat info about each entry
+// of the directory, sorted by name.
+func (daemon *Daemon) ContainerListPath(name string, path string) ([]types.ContainerPathStat, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure an online file-system operation is permitted.
+	if err := daemon.isOnlineFSOperationPermitted(container); err != nil {
+		return nil, errdefs.System(err)
+	}
+
+	entries, err := daemon.containerListPath(container, path)
+	if err == nil {
+		return entries, nil
+	}
+
+	if os.IsNotExist(err) {
+		return nil, containerFileNotFound{path, name}
+	}
+	if errdefs.IsInvalidParameter(err) {
+		return nil, err
+	}
+	return nil, errdefs.System(err)
+}
+
+// ContainerReadPath opens the regular file at the specified path in the
+// container identified by the given name. Returns the content of the file
+// and stat info about it.
+func (daemon *Daemon) ContainerReadPath(name string, path string) (content ioutils.ReadSeekCloser, stat *types.ContainerPathStat, err error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Make sure an online file-system operation is permitted.
+	if err := daemon.isOnlineFSOperationPermitted(container); err != nil {
+		return nil, nil, errdefs.System(err)
+	}
+
+	content, stat, err = daemon.containerReadPath(container, path)
+	if err == nil {
+		return content, stat, nil
+	}
+
+	if os.IsNotExist(err) {
+		return nil, nil, containerFileNotFound{path, name}
+	}
+	if errdefs.IsInvalidParameter(err) {
+		return nil, nil, err
+	}
+	return nil, nil, errdefs.System(err)
+}
+
 // ContainerExtractToDir extracts the given archive to the specified location
 // in the filesystem of the container identified by the given name. The given
 // path must be of a directory in the container. If it is not, the error will
@@ -263,6 +320,157 @@ func (daemon *Daemon) containerArchivePath(container *container.Container, path
 	return content, stat, nil
 }
 
+// containerListPath lists the directory at the specified path in this
+// container, following a symlink as the last path element. Returns stat info
+// about each entry of the directory.
+func (daemon *Daemon) containerListPath(container *container.Container, path string) ([]types.ContainerPathStat, error) {
+	container.Lock()
+	defer container.Unlock()
+
+	if err := daemon.Mount(container); err != nil {
+		return nil, err
+	}
+	defer daemon.Unmount(container)
+
+	err := daemon.mountVolumes(container)
+	defer container.DetachAndUnmount(daemon.LogVolumeEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, resolvedPath, err := resolveContainerPath(container, path)
+	if err != nil {
+		return nil, err
+	}
+	driver := container.BaseFS
+
+	stat, err := driver.Stat(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return nil, errdefs.InvalidParameter(errors.Errorf("%s is not a directory", path))
+	}
+
+	dir, err := driver.Open(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+
+	fi, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]types.ContainerPathStat, 0, len(fi))
+	for _, f := range fi {
+		// Stat the entry through the requested path, so that the targets of
+		// symlinks are evaluated in the scope of the container rootfs.
+		stat, err := container.StatPath(
+			resolvedPath+string(driver.Separator())+f.Name(),
+			driver.Join(absPath, f.Name()))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// the entry was removed since the directory was read
+				continue
+			}
+			return nil, err
+		}
+		entries = append(entries, *stat)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// containerReadPath opens the regular file at the specified path in this
+// container, following a symlink as the last path element. Returns the
+// content of the file and stat info about it.
+func (daemon *Daemon) containerReadPath(container *container.Container, path string) (content ioutils.ReadSeekCloser, stat *types.ContainerPathStat, err error) {
+	container.Lock()
+
+	defer func() {
+		if err != nil {
+			// Wait to unlock the container until the file is closed (see the
+			// ReadSeekCloserWrapper below) or if there is an error before
+			// that occurs.
+			container.Unlock()
+		}
+	}()
+
+	if err = daemon.Mount(container); err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			// unmount any volumes
+			container.DetachAndUnmount(daemon.LogVolumeEvent)
+			// unmount the container's rootfs
+			daemon.Unmount(container)
+		}
+	}()
+
+	if err = daemon.mountVolumes(container); err != nil {
+		return nil, nil, err
+	}
+
+	absPath, resolvedPath, err := resolveContainerPath(container, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	driver := container.BaseFS
+
+	stat, err = container.StatPath(resolvedPath, absPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !stat.Mode.IsRegular() {
+		return nil, nil, errdefs.InvalidParameter(errors.Errorf("%s is not a regular file", path))
+	}
+
+	f, err := driver.Open(resolvedPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content = ioutils.NewReadSeekCloserWrapper(f, func() error {
+		err := f.Close()
+		container.DetachAndUnmount(daemon.LogVolumeEvent)
+		daemon.Unmount(container)
+		container.Unlock()
+		return err
+	})
+
+	return content, stat, nil
+}
+
+// resolveContainerPath resolves the given path in the container to a
+// resource on the host, evaluating all symlinks, including the last path
+// element, in the scope of the container rootfs. Returns the absolute path
+// to the resource in the container and the resolved path on the host. Locks
+// and mounts should be acquired before calling this function.
+func resolveContainerPath(container *container.Container, path string) (absPath, resolvedPath string, err error) {
+	// Normalize path before sending to rootfs
+	path = container.BaseFS.FromSlash(path)
+	driver := container.BaseFS
+
+	// Check if a drive letter supplied, it must be the system drive. No-op except on Windows
+	path, err = system.CheckSystemDriveAndRemoveDriveLetter(path, driver)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Consider the given path as an absolute path in the container.
+	absPath = driver.Join(string(driver.Separator()), path)
+
+	resolvedPath, err = container.GetResourcePath(absPath)
+	if err != nil {
+		return "", "", err
+	}
+	return absPath, resolvedPath, nil
+}
+
 // containerExtractToDir extracts the given tar archive to the specified location in the
 // filesystem of this container. The given path must be of a directory in the
 // container. If it is not, the error will be ErrExtractPointNotDirectory. If
@@ -2,10 +2,17 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"errors"
+	"os"
 	"runtime"
+	"strings"
 	"time"
 
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/containerfs"
+	"github.com/opencontainers/go-digest"
+	"github.com/vbatts/tar-split/tar/storage"
 )
 
 // ContainerChanges returns a list of container fs changes
@@ -32,3 +39,131 @@ func (daemon *Daemon) ContainerChanges(name string) ([]archive.Change, error) {
 	containerActions.WithValues("changes").UpdateSince(start)
 	return c, nil
 }
+
+// ContainerChangesWithSize returns the container fs changes with the mode
+// and size of the added and modified files, and the digest of their content
+// if requested, along with the totals of the changes.
+func (daemon *Daemon) ContainerChangesWithSize(name string, digests bool) (*containertypes.ContainerChangesOKBody, error) {
+	start := time.Now()
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.GOOS == "windows" && container.IsRunning() {
+		return nil, errors.New("Windows does not support diff of a running container")
+	}
+
+	// The changes are listed with the container locked, and described with
+	// a reference to its RW layer, without blocking the container while the
+	// files are read.
+	rwlayer, changes, err := func() (layer.RWLayer, []archive.Change, error) {
+		container.Lock()
+		defer container.Unlock()
+		if container.RWLayer == nil {
+			return nil, nil, errors.New("RWLayer of container " + name + " is unexpectedly nil")
+		}
+		changes, err := container.RWLayer.Changes()
+		if err != nil {
+			return nil, nil, err
+		}
+		rwlayer, err := daemon.imageService.GetLayerByID(container.ID, container.OS)
+		if err != nil {
+			return nil, nil, err
+		}
+		return rwlayer, changes, nil
+	}()
+	if err != nil {
+		return nil, err
+	}
+	defer daemon.imageService.ReleaseLayer(rwlayer, container.OS)
+
+	root, err := rwlayer.Mount(container.GetMountLabel())
+	if err != nil {
+		return nil, err
+	}
+	defer rwlayer.Unmount()
+
+	var files storage.FileGetter
+	if digests {
+		fileGetter, err := rwlayer.DiffGetter()
+		if err != nil {
+			return nil, err
+		}
+		defer fileGetter.Close()
+		files = fileGetter
+	}
+
+	body, err := describeChanges(root, changes, files)
+	if err != nil {
+		return nil, err
+	}
+	containerActions.WithValues("changes").UpdateSince(start)
+	return body, nil
+}
+
+// describeChanges returns the changes with the mode and size of the added
+// and modified files read from the root filesystem, and the digest of their
+// content read from files if it is not nil.
+func describeChanges(root containerfs.ContainerFS, changes []archive.Change, files storage.FileGetter) (*containertypes.ContainerChangesOKBody, error) {
+	body := &containertypes.ContainerChangesOKBody{
+		Changes: make([]containertypes.ContainerChangeResponseItem, 0, len(changes)),
+	}
+	for _, change := range changes {
+		item := containertypes.ContainerChangeResponseItem{
+			Path: change.Path,
+			Kind: uint8(change.Kind),
+		}
+		switch change.Kind {
+		case archive.ChangeDelete:
+			body.Deleted++
+			body.Changes = append(body.Changes, item)
+			continue
+		case archive.ChangeAdd:
+			body.Added++
+		default:
+			body.Modified++
+		}
+
+		// do not follow the symlinks out of the root in the parent directories
+		dir, err := root.ResolveScopedPath(root.Dir(change.Path), false)
+		if err != nil {
+			return nil, err
+		}
+		fi, err := root.Lstat(root.Join(dir, root.Base(change.Path)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				// the file was removed since the changes were listed
+				body.Changes = append(body.Changes, item)
+				continue
+			}
+			return nil, err
+		}
+		item.Mode = fi.Mode()
+		if fi.Mode().IsRegular() {
+			item.Size = fi.Size()
+			body.Size += fi.Size()
+			if files != nil {
+				if item.Digest, err = fileDigest(files, change.Path); err != nil && !os.IsNotExist(err) {
+					return nil, err
+				}
+			}
+		}
+		body.Changes = append(body.Changes, item)
+	}
+	return body, nil
+}
+
+// fileDigest returns the digest of the content of the file at path.
+func fileDigest(files storage.FileGetter, path string) (string, error) {
+	f, err := files.Get(strings.TrimPrefix(path, string(os.PathSeparator)))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	dgst, err := digest.FromReader(f)
+	if err != nil {
+		return "", err
+	}
+	return dgst.String(), nil
+}
@@ -0,0 +1,61 @@
+// +build !windows
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/containerfs"
+	"github.com/vbatts/tar-split/tar/storage"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestDescribeChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "changes")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "etc"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "etc", "test"), []byte("test"), 0644))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "big"), make([]byte, 4096), 0600))
+	assert.NilError(t, os.Symlink("/etc", filepath.Join(dir, "link")))
+
+	changes := []archive.Change{
+		{Path: "/etc", Kind: archive.ChangeModify},
+		{Path: "/etc/test", Kind: archive.ChangeAdd},
+		{Path: "/big", Kind: archive.ChangeModify},
+		{Path: "/link", Kind: archive.ChangeAdd},
+		{Path: "/removed", Kind: archive.ChangeDelete},
+		// removed since the changes were listed
+		{Path: "/gone", Kind: archive.ChangeAdd},
+	}
+
+	root := containerfs.NewLocalContainerFS(dir)
+	body, err := describeChanges(root, changes, nil)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(body.Added, int64(3)))
+	assert.Check(t, is.Equal(body.Modified, int64(2)))
+	assert.Check(t, is.Equal(body.Deleted, int64(1)))
+	assert.Check(t, is.Equal(body.Size, int64(4100)))
+	assert.Check(t, is.DeepEqual(body.Changes, []containertypes.ContainerChangeResponseItem{
+		{Path: "/etc", Kind: uint8(archive.ChangeModify), Mode: os.ModeDir | 0755},
+		{Path: "/etc/test", Kind: uint8(archive.ChangeAdd), Mode: 0644, Size: 4},
+		{Path: "/big", Kind: uint8(archive.ChangeModify), Mode: 0600, Size: 4096},
+		{Path: "/link", Kind: uint8(archive.ChangeAdd), Mode: os.ModeSymlink | 0777},
+		{Path: "/removed", Kind: uint8(archive.ChangeDelete)},
+		{Path: "/gone", Kind: uint8(archive.ChangeAdd)},
+	}))
+
+	body, err = describeChanges(root, changes, storage.NewPathFileGetter(dir))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(body.Changes[0].Digest, ""))
+	assert.Check(t, is.Equal(body.Changes[1].Digest, "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"))
+	assert.Check(t, is.Equal(body.Changes[2].Digest, "sha256:ad7facb2586fc6e966c004d7d1d16b024f5805ff7cb47c7a85dabd8b48892ca7"))
+	assert.Check(t, is.Equal(body.Changes[3].Digest, ""))
+}
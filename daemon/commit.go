@@ -81,6 +81,9 @@ func merge(userConf, imageConf *containertypes.Config) error {
 			if len(userConf.Healthcheck.Test) == 0 {
 				userConf.Healthcheck.Test = imageConf.Healthcheck.Test
 			}
+			if len(userConf.Healthcheck.StartupTest) == 0 {
+				userConf.Healthcheck.StartupTest = imageConf.Healthcheck.StartupTest
+			}
 			if userConf.Healthcheck.Interval == 0 {
 				userConf.Healthcheck.Interval = imageConf.Healthcheck.Interval
 			}
@@ -90,9 +93,15 @@ func merge(userConf, imageConf *containertypes.Config) error {
 			if userConf.Healthcheck.StartPeriod == 0 {
 				userConf.Healthcheck.StartPeriod = imageConf.Healthcheck.StartPeriod
 			}
+			if userConf.Healthcheck.StartInterval == 0 {
+				userConf.Healthcheck.StartInterval = imageConf.Healthcheck.StartInterval
+			}
 			if userConf.Healthcheck.Retries == 0 {
 				userConf.Healthcheck.Retries = imageConf.Healthcheck.Retries
 			}
+			if userConf.Healthcheck.OnUnhealthy == "" {
+				userConf.Healthcheck.OnUnhealthy = imageConf.Healthcheck.OnUnhealthy
+			}
 		}
 	}
 
@@ -138,6 +147,10 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 		return "", errdefs.Conflict(err)
 	}
 
+	if c.Squash && container.OS == "windows" {
+		return "", errdefs.InvalidParameter(errors.New("squashing a commit is not supported for Windows containers"))
+	}
+
 	if c.Pause && !container.IsPaused() {
 		daemon.containerPause(container)
 		defer daemon.containerUnpause(container)
@@ -163,6 +176,9 @@ func (daemon *Daemon) CreateImageFromContainer(name string, c *backend.CreateIma
 		ContainerMountLabel: container.MountLabel,
 		ContainerOS:         container.OS,
 		ParentImageID:       string(container.ImageID),
+		Exclude:             c.Exclude,
+		Squash:              c.Squash,
+		Annotations:         c.Annotations,
 	})
 	if err != nil {
 		return "", err
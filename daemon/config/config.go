@@ -55,14 +55,20 @@ var flatOptions = map[string]bool{
 	"default-ulimits":    true,
 	"features":           true,
 	"builder":            true,
+	"events-journal":     true,
+	"container-metrics":  true,
+	"stats-history":      true,
 }
 
 // skipValidateOptions contains configuration keys
 // that will be skipped from findConfigurationConflicts
 // for unknown flag validation.
 var skipValidateOptions = map[string]bool{
-	"features": true,
-	"builder":  true,
+	"features":          true,
+	"builder":           true,
+	"events-journal":    true,
+	"container-metrics": true,
+	"stats-history":     true,
 	// Corresponding flag has been removed because it was already unusable
 	"deprecated-key-path": true,
 }
@@ -236,6 +242,16 @@ type CommonConfig struct {
 
 	Builder BuilderConfig `json:"builder,omitempty"`
 
+	// EventsJournal configures the on-disk journal of events.
+	EventsJournal EventsJournalConfig `json:"events-journal,omitempty"`
+
+	// ContainerMetrics configures the metrics of each container exported on
+	// the metrics address.
+	ContainerMetrics ContainerMetricsConfig `json:"container-metrics,omitempty"`
+
+	// StatsHistory configures the stats history of containers.
+	StatsHistory StatsHistoryConfig `json:"stats-history,omitempty"`
+
 	ContainerdNamespace       string `json:"containerd-namespace,omitempty"`
 	ContainerdPluginNamespace string `json:"containerd-plugin-namespace,omitempty"`
 }
@@ -576,6 +592,21 @@ func Validate(config *Config) error {
 		return err
 	}
 
+	if _, _, err := config.EventsJournal.Limits(); err != nil {
+		return err
+	}
+
+	if err := config.ContainerMetrics.validate(); err != nil {
+		return err
+	}
+	if config.ContainerMetrics.Enabled && config.MetricsAddress == "" {
+		return fmt.Errorf("container metrics require a metrics address (metrics-addr)")
+	}
+
+	if _, _, _, err := config.StatsHistory.Limits(); err != nil {
+		return err
+	}
+
 	if defaultRuntime := config.GetDefaultRuntimeName(); defaultRuntime != "" && defaultRuntime != StockRuntimeName {
 		runtimes := config.GetAllRuntimes()
 		if _, ok := runtimes[defaultRuntime]; !ok {
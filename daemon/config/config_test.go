@@ -313,6 +313,64 @@ func TestValidateConfigurationErrors(t *testing.T) {
 				},
 			},
 		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventsJournal: EventsJournalConfig{Enabled: true, MaxSize: "lots"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					EventsJournal: EventsJournalConfig{Enabled: true, MaxAge: "-1h"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					ContainerMetrics: ContainerMetricsConfig{Enabled: true},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					MetricsAddress:   "127.0.0.1:9323",
+					ContainerMetrics: ContainerMetricsConfig{Enabled: true, Labels: []string{"team", "team"}},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					MetricsAddress:   "127.0.0.1:9323",
+					ContainerMetrics: ContainerMetricsConfig{Enabled: true, MaxContainers: -1},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					StatsHistory: StatsHistoryConfig{Enabled: true, MaxSamples: -1},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					StatsHistory: StatsHistoryConfig{Enabled: true, Interval: "10ms"},
+				},
+			},
+		},
+		{
+			config: &Config{
+				CommonConfig: CommonConfig{
+					StatsHistory: StatsHistoryConfig{Enabled: true, Retention: "forever"},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		err := Validate(tc.config)
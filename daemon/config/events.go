@@ -0,0 +1,47 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/docker/go-units"
+)
+
+const (
+	// DefaultEventsJournalMaxSize is the default maximum size of the events journal
+	DefaultEventsJournalMaxSize = 64 * 1024 * 1024
+	// DefaultEventsJournalMaxAge is the default maximum age of the events in the journal
+	DefaultEventsJournalMaxAge = 7 * 24 * time.Hour
+)
+
+// EventsJournalConfig contains the config for the on-disk journal of events,
+// from which events older than the ones kept in memory are replayed.
+type EventsJournalConfig struct {
+	Enabled bool   `json:",omitempty"`
+	MaxSize string `json:",omitempty"`
+	MaxAge  string `json:",omitempty"`
+}
+
+// Limits returns the maximum size in bytes of the journal, and the maximum
+// age of the events it keeps. A zero maximum age keeps events until the
+// maximum size is reached.
+func (c EventsJournalConfig) Limits() (int64, time.Duration, error) {
+	maxSize := int64(DefaultEventsJournalMaxSize)
+	if c.MaxSize != "" {
+		size, err := units.RAMInBytes(c.MaxSize)
+		if err != nil || size <= 0 {
+			return 0, 0, fmt.Errorf("invalid events journal max size: %q", c.MaxSize)
+		}
+		maxSize = size
+	}
+
+	maxAge := DefaultEventsJournalMaxAge
+	if c.MaxAge != "" {
+		age, err := time.ParseDuration(c.MaxAge)
+		if err != nil || age < 0 {
+			return 0, 0, fmt.Errorf("invalid events journal max age: %q", c.MaxAge)
+		}
+		maxAge = age
+	}
+	return maxSize, maxAge, nil
+}
@@ -0,0 +1,48 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+)
+
+// DefaultContainerMetricsMaxContainers is the default maximum number of
+// containers for which metrics are exported.
+const DefaultContainerMetricsMaxContainers = 1000
+
+// ContainerMetricsConfig contains the config for the metrics of the resource
+// usage of each container, which are exported on the metrics address.
+type ContainerMetricsConfig struct {
+	Enabled bool `json:",omitempty"`
+	// Labels are the container labels which are exported as labels of the
+	// metrics. Other container labels are not exported, to bound the
+	// cardinality of the metrics.
+	Labels []string `json:",omitempty"`
+	// MaxContainers is the maximum number of containers for which metrics
+	// are exported. Zero means the default.
+	MaxContainers int `json:",omitempty"`
+}
+
+// GetMaxContainers returns the maximum number of containers for which
+// metrics are exported.
+func (c ContainerMetricsConfig) GetMaxContainers() int {
+	if c.MaxContainers == 0 {
+		return DefaultContainerMetricsMaxContainers
+	}
+	return c.MaxContainers
+}
+
+func (c ContainerMetricsConfig) validate() error {
+	if c.MaxContainers < 0 {
+		return fmt.Errorf("invalid container metrics max containers: %d", c.MaxContainers)
+	}
+	seen := make(map[string]bool)
+	for _, l := range c.Labels {
+		if l == "" {
+			return fmt.Errorf("invalid container metrics label: label cannot be empty")
+		}
+		if seen[l] {
+			return fmt.Errorf("invalid container metrics labels: duplicate label %q", l)
+		}
+		seen[l] = true
+	}
+	return nil
+}
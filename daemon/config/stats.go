@@ -0,0 +1,59 @@
+package config // import "github.com/docker/docker/daemon/config"
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultStatsHistoryMaxSamples is the default maximum number of samples
+	// kept in the stats history of each container
+	DefaultStatsHistoryMaxSamples = 360
+	// DefaultStatsHistoryInterval is the default interval between the samples
+	// of the stats history
+	DefaultStatsHistoryInterval = 10 * time.Second
+	// DefaultStatsHistoryRetention is the default time for which the stats
+	// history of a container is kept after it exited
+	DefaultStatsHistoryRetention = time.Hour
+)
+
+// StatsHistoryConfig contains the config for the stats history, which keeps
+// samples of the resource usage of each container, including for a while
+// after it exited.
+type StatsHistoryConfig struct {
+	Enabled    bool   `json:",omitempty"`
+	MaxSamples int    `json:",omitempty"`
+	Interval   string `json:",omitempty"`
+	Retention  string `json:",omitempty"`
+}
+
+// Limits returns the maximum number of samples kept for each container, the
+// interval between the samples, and the time for which the samples of a
+// container are kept after it exited.
+func (c StatsHistoryConfig) Limits() (int, time.Duration, time.Duration, error) {
+	maxSamples := DefaultStatsHistoryMaxSamples
+	if c.MaxSamples < 0 {
+		return 0, 0, 0, fmt.Errorf("invalid stats history max samples: %d", c.MaxSamples)
+	} else if c.MaxSamples > 0 {
+		maxSamples = c.MaxSamples
+	}
+
+	interval := DefaultStatsHistoryInterval
+	if c.Interval != "" {
+		i, err := time.ParseDuration(c.Interval)
+		if err != nil || i < time.Second {
+			return 0, 0, 0, fmt.Errorf("invalid stats history interval: %q", c.Interval)
+		}
+		interval = i
+	}
+
+	retention := DefaultStatsHistoryRetention
+	if c.Retention != "" {
+		r, err := time.ParseDuration(c.Retention)
+		if err != nil || r < 0 {
+			return 0, 0, 0, fmt.Errorf("invalid stats history retention: %q", c.Retention)
+		}
+		retention = r
+	}
+	return maxSamples, interval, retention, nil
+}
@@ -296,6 +296,9 @@ func validateHostConfig(hostConfig *containertypes.HostConfig, platform string)
 	if err := validateRestartPolicy(hostConfig.RestartPolicy); err != nil {
 		return err
 	}
+	if err := validateDependsOn(hostConfig.DependsOn); err != nil {
+		return err
+	}
 	if err := validateCapabilities(hostConfig); err != nil {
 		return err
 	}
@@ -342,7 +345,21 @@ func validateHealthCheck(healthConfig *containertypes.HealthConfig) error {
 	if healthConfig.StartPeriod != 0 && healthConfig.StartPeriod < containertypes.MinimumDuration {
 		return errors.Errorf("StartPeriod in Healthcheck cannot be less than %s", containertypes.MinimumDuration)
 	}
-	return nil
+	if healthConfig.StartInterval != 0 && healthConfig.StartInterval < containertypes.MinimumDuration {
+		return errors.Errorf("StartInterval in Healthcheck cannot be less than %s", containertypes.MinimumDuration)
+	}
+	switch healthConfig.OnUnhealthy {
+	case "", containertypes.UnhealthyActionNone, containertypes.UnhealthyActionRestart, containertypes.UnhealthyActionStop:
+	default:
+		return errors.Errorf("invalid OnUnhealthy action in Healthcheck: %q", healthConfig.OnUnhealthy)
+	}
+	if len(healthConfig.StartupTest) > 0 && healthConfig.StartupTest[0] == "NONE" {
+		return errors.Errorf("StartupTest in Healthcheck cannot be NONE")
+	}
+	if err := validateHealthcheckTest(healthConfig.StartupTest); err != nil {
+		return err
+	}
+	return validateHealthcheckTest(healthConfig.Test)
 }
 
 func validatePortBindings(ports nat.PortMap) error {
@@ -377,6 +394,18 @@ func validateRestartPolicy(policy containertypes.RestartPolicy) error {
 	default:
 		return errors.Errorf("invalid restart policy '%s'", policy.Name)
 	}
+	if policy.InitialDelay < 0 || policy.MaximumDelay < 0 {
+		return errors.Errorf("restart delay cannot be negative")
+	}
+	if policy.MaximumDelay != 0 && policy.MaximumDelay < policy.InitialDelay {
+		return errors.Errorf("maximum restart delay cannot be less than the initial delay")
+	}
+	if policy.MaximumRestartsInWindow < 0 || policy.RestartWindow < 0 {
+		return errors.Errorf("maximum restarts in window and restart window cannot be negative")
+	}
+	if (policy.MaximumRestartsInWindow == 0) != (policy.RestartWindow == 0) {
+		return errors.Errorf("maximum restarts in window and restart window must be set together")
+	}
 	return nil
 }
 
@@ -0,0 +1,239 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// containerMetricsSyncInterval is the interval at which the containers for
+// which metrics are exported are updated.
+const containerMetricsSyncInterval = 5 * time.Second
+
+// containerMetrics exports metrics of the resource usage of each running
+// container, from the samples of the stats collector.
+type containerMetrics struct {
+	daemon        *Daemon
+	labels        []string // container labels exported as labels of the metrics
+	maxContainers int
+
+	cpuUsage    *prometheus.Desc
+	memoryUsage *prometheus.Desc
+	memoryLimit *prometheus.Desc
+	networkRx   *prometheus.Desc
+	networkTx   *prometheus.Desc
+	blkioRead   *prometheus.Desc
+	blkioWrite  *prometheus.Desc
+	pids        *prometheus.Desc
+
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu            sync.Mutex
+	subscriptions map[string]*containerSubscription // by container ID
+	limitReached  bool
+}
+
+// containerSubscription holds the latest stats sample of a container.
+type containerSubscription struct {
+	container *container.Container
+	ch        chan interface{}
+	stats     *types.StatsJSON
+}
+
+func newContainerMetrics(daemon *Daemon, ns *metrics.Namespace, cfg config.ContainerMetricsConfig) *containerMetrics {
+	var (
+		labels        = []string{"name", "image"}
+		exported      []string
+		exportedNames = make(map[string]bool)
+	)
+	for _, l := range cfg.Labels {
+		name := metricLabelName(l)
+		if exportedNames[name] {
+			logrus.Warnf("Not exporting container label %s in metrics: metric label %s is already exported", l, name)
+			continue
+		}
+		exportedNames[name] = true
+		exported = append(exported, l)
+		labels = append(labels, name)
+	}
+	m := &containerMetrics{
+		daemon:        daemon,
+		labels:        exported,
+		maxContainers: cfg.GetMaxContainers(),
+		stop:          make(chan struct{}),
+		subscriptions: make(map[string]*containerSubscription),
+
+		cpuUsage:    ns.NewDesc("container_cpu_usage_seconds", "The total CPU time consumed by the container", metrics.Total, labels...),
+		memoryUsage: ns.NewDesc("container_memory_usage", "The memory usage of the container", metrics.Bytes, labels...),
+		memoryLimit: ns.NewDesc("container_memory_limit", "The memory limit of the container", metrics.Bytes, labels...),
+		networkRx:   ns.NewDesc("container_network_receive", "The number of bytes received by the container on all its interfaces", metrics.Bytes, labels...),
+		networkTx:   ns.NewDesc("container_network_transmit", "The number of bytes transmitted by the container on all its interfaces", metrics.Bytes, labels...),
+		blkioRead:   ns.NewDesc("container_blkio_read", "The number of bytes read by the container from block devices", metrics.Bytes, labels...),
+		blkioWrite:  ns.NewDesc("container_blkio_write", "The number of bytes written by the container to block devices", metrics.Bytes, labels...),
+		pids:        ns.NewDesc("container_pids", "The number of processes and threads in the container", "", labels...),
+	}
+	ns.Add(m)
+	return m
+}
+
+// metricLabelName returns the name of the metric label of a container label,
+// which may contain characters which are not valid in metric label names.
+func metricLabelName(label string) string {
+	return "container_label_" + strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, label)
+}
+
+// run updates the containers for which metrics are exported until close is
+// called.
+func (m *containerMetrics) run() {
+	ticker := time.NewTicker(containerMetricsSyncInterval)
+	defer ticker.Stop()
+	for {
+		m.sync()
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync subscribes to the stats of the running containers, up to the maximum
+// number of containers, and unsubscribes from the stats of the containers
+// which are not running anymore.
+func (m *containerMetrics) sync() {
+	running := make(map[string]*container.Container)
+	for _, c := range m.daemon.List() {
+		if c.IsRunning() {
+			running[c.ID] = c
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, s := range m.subscriptions {
+		if _, ok := running[id]; !ok {
+			m.daemon.unsubscribeToContainerStats(s.container, s.ch)
+			delete(m.subscriptions, id)
+		}
+	}
+	for id, c := range running {
+		if _, ok := m.subscriptions[id]; ok {
+			continue
+		}
+		if len(m.subscriptions) >= m.maxContainers {
+			if !m.limitReached {
+				logrus.Warnf("Not exporting the metrics of more than %d containers", m.maxContainers)
+				m.limitReached = true
+			}
+			break
+		}
+		s := &containerSubscription{container: c, ch: m.daemon.subscribeToContainerStats(c)}
+		m.subscriptions[id] = s
+		go m.receive(id, s)
+	}
+}
+
+// receive keeps the latest stats sample of the subscription.
+func (m *containerMetrics) receive(id string, s *containerSubscription) {
+	for v := range s.ch {
+		stats, ok := v.(types.StatsJSON)
+		if !ok {
+			continue
+		}
+		m.mu.Lock()
+		s.stats = &stats
+		m.mu.Unlock()
+	}
+
+	// the channel is closed when the container is removed
+	m.mu.Lock()
+	if m.subscriptions[id] == s {
+		delete(m.subscriptions, id)
+	}
+	m.mu.Unlock()
+}
+
+// close stops updating the containers, and unsubscribes from their stats.
+func (m *containerMetrics) close() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, s := range m.subscriptions {
+		m.daemon.unsubscribeToContainerStats(s.container, s.ch)
+		delete(m.subscriptions, id)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *containerMetrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{m.cpuUsage, m.memoryUsage, m.memoryLimit, m.networkRx, m.networkTx, m.blkioRead, m.blkioWrite, m.pids} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (m *containerMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range m.subscriptions {
+		// the stats of containers which are not running only hold their
+		// name and ID
+		if s.stats == nil || s.stats.Read.IsZero() {
+			continue
+		}
+		labels := m.labelValues(s.container)
+		stats := s.stats
+
+		var rx, tx uint64
+		for _, n := range stats.Networks {
+			rx += n.RxBytes
+			tx += n.TxBytes
+		}
+		read, write := stats.StorageStats.ReadSizeBytes, stats.StorageStats.WriteSizeBytes
+		for _, e := range stats.BlkioStats.IoServiceBytesRecursive {
+			switch {
+			case strings.EqualFold(e.Op, "read"):
+				read += e.Value
+			case strings.EqualFold(e.Op, "write"):
+				write += e.Value
+			}
+		}
+
+		ch <- prometheus.MustNewConstMetric(m.cpuUsage, prometheus.CounterValue, float64(stats.CPUStats.CPUUsage.TotalUsage)/float64(time.Second), labels...)
+		ch <- prometheus.MustNewConstMetric(m.memoryUsage, prometheus.GaugeValue, float64(stats.MemoryStats.Usage), labels...)
+		ch <- prometheus.MustNewConstMetric(m.memoryLimit, prometheus.GaugeValue, float64(stats.MemoryStats.Limit), labels...)
+		ch <- prometheus.MustNewConstMetric(m.networkRx, prometheus.CounterValue, float64(rx), labels...)
+		ch <- prometheus.MustNewConstMetric(m.networkTx, prometheus.CounterValue, float64(tx), labels...)
+		ch <- prometheus.MustNewConstMetric(m.blkioRead, prometheus.CounterValue, float64(read), labels...)
+		ch <- prometheus.MustNewConstMetric(m.blkioWrite, prometheus.CounterValue, float64(write), labels...)
+		ch <- prometheus.MustNewConstMetric(m.pids, prometheus.GaugeValue, float64(stats.PidsStats.Current), labels...)
+	}
+}
+
+// labelValues returns the values of the labels of the metrics of the
+// container.
+func (m *containerMetrics) labelValues(c *container.Container) []string {
+	values := []string{strings.TrimPrefix(c.Name, "/"), c.Config.Image}
+	for _, l := range m.labels {
+		values = append(values, c.Config.Labels[l])
+	}
+	return values
+}
@@ -0,0 +1,91 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"github.com/docker/go-metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestMetricLabelName(t *testing.T) {
+	assert.Check(t, is.Equal(metricLabelName("com.example.team"), "container_label_com_example_team"))
+	assert.Check(t, is.Equal(metricLabelName("app-name"), "container_label_app_name"))
+}
+
+func TestContainerMetricsCollect(t *testing.T) {
+	ns := metrics.NewNamespace("engine", "daemon", nil)
+	m := newContainerMetrics(&Daemon{}, ns, config.ContainerMetricsConfig{
+		Enabled: true,
+		Labels:  []string{"com.example.team", "com_example.team"},
+	})
+	registry := prometheus.NewRegistry()
+	assert.NilError(t, registry.Register(ns))
+
+	c := &container.Container{
+		ID:   "container_id",
+		Name: "/web",
+		Config: &containertypes.Config{
+			Image:  "nginx:alpine",
+			Labels: map[string]string{"com.example.team": "frontend", "other": "ignored"},
+		},
+	}
+	stats := types.StatsJSON{}
+	stats.Read = time.Now()
+	stats.CPUStats.CPUUsage.TotalUsage = uint64(3 * time.Second)
+	stats.MemoryStats.Usage = 1024
+	stats.MemoryStats.Limit = 4096
+	stats.Networks = map[string]types.NetworkStats{
+		"eth0": {RxBytes: 10, TxBytes: 20},
+		"eth1": {RxBytes: 1, TxBytes: 2},
+	}
+	stats.BlkioStats.IoServiceBytesRecursive = []types.BlkioStatEntry{
+		{Op: "Read", Value: 100},
+		{Op: "Write", Value: 200},
+		{Op: "Total", Value: 300},
+	}
+	stats.PidsStats.Current = 7
+	m.subscriptions[c.ID] = &containerSubscription{container: c, stats: &stats}
+	// containers without samples are not exported
+	m.subscriptions["stopped_id"] = &containerSubscription{container: c, stats: &types.StatsJSON{ID: "stopped_id"}}
+
+	families, err := registry.Gather()
+	assert.NilError(t, err)
+
+	values := make(map[string]float64)
+	for _, f := range families {
+		assert.Assert(t, is.Len(f.Metric, 1), f.GetName())
+		metric := f.Metric[0]
+		labels := make(map[string]string)
+		for _, l := range metric.Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		assert.Check(t, is.DeepEqual(labels, map[string]string{
+			"name":                             "web",
+			"image":                            "nginx:alpine",
+			"container_label_com_example_team": "frontend",
+		}), f.GetName())
+
+		if metric.Counter != nil {
+			values[f.GetName()] = metric.Counter.GetValue()
+		} else {
+			values[f.GetName()] = metric.Gauge.GetValue()
+		}
+	}
+	assert.Check(t, is.DeepEqual(values, map[string]float64{
+		"engine_daemon_container_cpu_usage_seconds_total": 3,
+		"engine_daemon_container_memory_usage_bytes":      1024,
+		"engine_daemon_container_memory_limit_bytes":      4096,
+		"engine_daemon_container_network_receive_bytes":   11,
+		"engine_daemon_container_network_transmit_bytes":  22,
+		"engine_daemon_container_blkio_read_bytes":        100,
+		"engine_daemon_container_blkio_write_bytes":       200,
+		"engine_daemon_container_pids":                    7,
+	}))
+}
@@ -66,6 +66,7 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
 	volumesservice "github.com/docker/docker/volume/service"
+	"github.com/docker/go-metrics"
 	"github.com/docker/libnetwork"
 	"github.com/docker/libnetwork/cluster"
 	nwconfig "github.com/docker/libnetwork/config"
@@ -91,6 +92,7 @@ type Daemon struct {
 	idIndex           *truncindex.TruncIndex
 	configStore       *config.Config
 	statsCollector    *stats.Collector
+	containerMetrics  *containerMetrics
 	defaultLogConfig  containertypes.LogConfig
 	RegistryService   registry.Service
 	EventsService     *events.Events
@@ -406,6 +408,9 @@ func (daemon *Daemon) restore() error {
 				}
 
 				c.ResetRestartManager(false)
+				if c.IsRunning() {
+					daemon.statsCollector.Track(c)
+				}
 				if !c.HostConfig.NetworkMode.IsContainer() && c.IsRunning() {
 					options, err := daemon.buildSandboxOptions(c)
 					if err != nil {
@@ -478,35 +483,74 @@ func (daemon *Daemon) restore() error {
 	}
 	group.Wait()
 
-	for c, notifier := range restartContainers {
-		group.Add(1)
-		go func(c *container.Container, chNotify chan struct{}) {
-			_ = sem.Acquire(context.Background(), 1)
-			logrus.Debugf("Starting container %s", c.ID)
-
-			// ignore errors here as this is a best effort to wait for children to be
-			//   running before we try to start the container
-			children := daemon.children(c)
-			timeout := time.NewTimer(5 * time.Second)
-			defer timeout.Stop()
-
-			for _, child := range children {
-				if notifier, exists := restartContainers[child]; exists {
-					select {
-					case <-notifier:
-					case <-timeout.C:
-					}
+	// Containers whose dependencies cannot be ordered, such as when they form
+	// a cycle, are started without waiting for their dependencies.
+	dependencies := make(map[*container.Container][]dependency)
+	for c := range restartContainers {
+		if _, err := daemon.dependencyOrder(c); err != nil {
+			logrus.WithError(err).Errorf("Failed to order the start of container %s after its dependencies", c.ID)
+			continue
+		}
+		dependencies[c], _ = daemon.dependencies(c)
+	}
+
+	restartContainer := func(c *container.Container, chNotify chan struct{}) {
+		_ = sem.Acquire(context.Background(), 1)
+		logrus.Debugf("Starting container %s", c.ID)
+
+		// ignore errors here as this is a best effort to wait for children to be
+		//   running before we try to start the container
+		children := daemon.children(c)
+		timeout := time.NewTimer(5 * time.Second)
+		defer timeout.Stop()
+
+		for _, child := range children {
+			if notifier, exists := restartContainers[child]; exists {
+				select {
+				case <-notifier:
+				case <-timeout.C:
 				}
 			}
+		}
 
-			// Make sure networks are available before starting
-			daemon.waitForNetworks(c)
-			if err := daemon.containerStart(c, "", "", true); err != nil {
-				logrus.Errorf("Failed to start container %s: %s", c.ID, err)
-			}
-			close(chNotify)
+		// Make sure networks are available before starting
+		daemon.waitForNetworks(c)
+		if err := daemon.containerStart(c, "", "", true); err != nil {
+			logrus.Errorf("Failed to start container %s: %s", c.ID, err)
+		}
+		close(chNotify)
 
-			sem.Release(1)
+		sem.Release(1)
+	}
+
+	for c, notifier := range restartContainers {
+		if deps := dependencies[c]; len(deps) > 0 {
+			// Containers with dependencies are started in the background once
+			// their dependencies are, as waiting for them to be healthy must
+			// not delay the start of the daemon.
+			go func(c *container.Container, deps []dependency, chNotify chan struct{}) {
+				for _, dep := range deps {
+					if notifier, exists := restartContainers[dep.container]; exists {
+						<-notifier
+					}
+					if dep.condition == containertypes.DependencyConditionHealthy {
+						if err := daemon.waitForHealthy(context.Background(), dep.container, dependencyHealthyTimeout); err != nil {
+							logrus.WithError(err).Warnf("Starting container %s without waiting for its dependency to be healthy", c.ID)
+						}
+					}
+				}
+				if daemon.IsShuttingDown() {
+					close(chNotify)
+					return
+				}
+				restartContainer(c, chNotify)
+			}(c, deps, notifier)
+			continue
+		}
+
+		group.Add(1)
+		go func(c *container.Container, chNotify chan struct{}) {
+			restartContainer(c, chNotify)
 			group.Done()
 		}(c, notifier)
 	}
@@ -969,7 +1013,7 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 		return nil, err
 	}
 
-	d.volumes, err = volumesservice.NewVolumeService(config.Root, d.PluginStore, rootIDs, d)
+	d.volumes, err = volumesservice.NewVolumeService(config.Root, d.PluginStore, idMapping, d)
 	if err != nil {
 		return nil, err
 	}
@@ -1028,8 +1072,23 @@ func NewDaemon(ctx context.Context, config *config.Config, pluginStore *plugin.S
 	d.execCommands = exec.NewStore()
 	d.idIndex = truncindex.NewTruncIndex([]string{})
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
+	if config.ContainerMetrics.Enabled {
+		ns := metrics.NewNamespace("engine", "daemon", nil)
+		d.containerMetrics = newContainerMetrics(d, ns, config.ContainerMetrics)
+		metrics.Register(ns)
+		go d.containerMetrics.run()
+	}
 
 	d.EventsService = events.New()
+	if config.EventsJournal.Enabled {
+		maxSize, maxAge, err := config.EventsJournal.Limits()
+		if err != nil {
+			return nil, err
+		}
+		if err := d.EventsService.OpenJournal(filepath.Join(config.Root, "events"), maxSize, maxAge); err != nil {
+			return nil, err
+		}
+	}
 	d.root = config.Root
 	d.idMapping = idMapping
 	d.seccompEnabled = sysInfo.Seccomp
@@ -1217,6 +1276,16 @@ func (daemon *Daemon) Shutdown() error {
 		daemon.containerdCli.Close()
 	}
 
+	if daemon.containerMetrics != nil {
+		daemon.containerMetrics.close()
+	}
+
+	if daemon.EventsService != nil {
+		if err := daemon.EventsService.Close(); err != nil {
+			logrus.Errorf("Error closing events journal: %v", err)
+		}
+	}
+
 	return daemon.cleanupMounts()
 }
 
@@ -467,6 +467,10 @@ func verifyPlatformContainerResources(resources *containertypes.Resources, sysIn
 	if resources.KernelMemory > 0 && !kernel.CheckKernelVersion(4, 0, 0) {
 		warnings = append(warnings, "You specified a kernel memory limit on a kernel older than 4.0. Kernel memory limits are experimental on older kernels, it won't work as expected and can cause your system to be unstable.")
 	}
+	if resources.KernelMemoryTCP != 0 && sysInfo.CgroupUnified {
+		warnings = append(warnings, "Kernel memory TCP limit is not supported with cgroup v2. Limitation discarded.")
+		resources.KernelMemoryTCP = 0
+	}
 	if resources.OomKillDisable != nil && !sysInfo.OomKillDisable {
 		// only produce warnings if the setting wasn't to *disable* the OOM Kill; no point
 		// warning the caller if they already wanted the feature to be off
@@ -524,6 +528,11 @@ func verifyPlatformContainerResources(resources *containertypes.Resources, sysIn
 	if resources.CPUQuota > 0 && resources.CPUQuota < 1000 {
 		return warnings, fmt.Errorf("CPU cfs quota can not be less than 1ms (i.e. 1000)")
 	}
+	if (resources.CPURealtimePeriod != 0 || resources.CPURealtimeRuntime != 0) && sysInfo.CgroupUnified {
+		warnings = append(warnings, "CPU real-time scheduler is not supported with cgroup v2. Real-time period and runtime discarded.")
+		resources.CPURealtimePeriod = 0
+		resources.CPURealtimeRuntime = 0
+	}
 	if resources.CPUPercent > 0 {
 		warnings = append(warnings, fmt.Sprintf("%s does not support CPU percent. Percent discarded.", runtime.GOOS))
 		resources.CPUPercent = 0
@@ -1372,6 +1381,9 @@ func (daemon *Daemon) stats(c *container.Container) (*types.StatsJSON, error) {
 	if !c.IsRunning() {
 		return nil, errNotRunning(c.ID)
 	}
+	if sysinfo.IsCgroup2UnifiedMode() {
+		return daemon.cgroup2Stats(c)
+	}
 	cs, err := daemon.containerd.Stats(context.Background(), c.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "container not found") {
@@ -1463,6 +1475,11 @@ func (daemon *Daemon) stats(c *container.Container) (*types.StatsJSON, error) {
 		if s.MemoryStats.Limit > daemon.machineMemory && daemon.machineMemory > 0 {
 			s.MemoryStats.Limit = daemon.machineMemory
 		}
+
+		// the OOM kill counter is only available on recent kernels
+		if kills, err := readOOMKills(c.GetPID()); err == nil {
+			s.MemoryStats.OOMKills = kills
+		}
 	}
 
 	if stats.Pids != nil {
@@ -366,6 +366,46 @@ func TestVerifyPlatformContainerResources(t *testing.T) {
 	}
 }
 
+func TestVerifyPlatformContainerResourcesCgroup2(t *testing.T) {
+	info := sysinfo.SysInfo{CgroupUnified: true}
+	info.MemoryLimit, info.SwapLimit, info.MemoryReservation = true, true, true
+	info.CPUShares, info.CPUCfsPeriod, info.CPUCfsQuota = true, true, true
+	info.PidsLimit = true
+
+	pids, swappiness := int64(100), int64(10)
+	resources := containertypes.Resources{
+		Memory:             linuxMinMemory,
+		MemorySwap:         2 * linuxMinMemory,
+		MemorySwappiness:   &swappiness,
+		KernelMemory:       linuxMinMemory,
+		KernelMemoryTCP:    linuxMinMemory,
+		CPUShares:          512,
+		CPUPeriod:          100000,
+		CPUQuota:           50000,
+		CPURealtimePeriod:  1000000,
+		CPURealtimeRuntime: 950000,
+		PidsLimit:          &pids,
+	}
+	warnings, err := verifyPlatformContainerResources(&resources, &info, false)
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(warnings, 4))
+
+	// the limits which have an equivalent in cgroup v2 are kept, and
+	// applied by the runtime
+	assert.Check(t, is.Equal(resources.Memory, int64(linuxMinMemory)))
+	assert.Check(t, is.Equal(resources.MemorySwap, int64(2*linuxMinMemory)))
+	assert.Check(t, is.Equal(resources.CPUShares, int64(512)))
+	assert.Check(t, is.Equal(resources.CPUQuota, int64(50000)))
+	assert.Check(t, is.Equal(*resources.PidsLimit, pids))
+
+	// the others are discarded
+	assert.Check(t, is.Nil(resources.MemorySwappiness))
+	assert.Check(t, is.Equal(resources.KernelMemory, int64(0)))
+	assert.Check(t, is.Equal(resources.KernelMemoryTCP, int64(0)))
+	assert.Check(t, is.Equal(resources.CPURealtimePeriod, int64(0)))
+	assert.Check(t, is.Equal(resources.CPURealtimeRuntime, int64(0)))
+}
+
 func sysInfo(t *testing.T, opts ...func(*sysinfo.SysInfo)) sysinfo.SysInfo {
 	t.Helper()
 	si := sysinfo.SysInfo{}
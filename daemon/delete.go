@@ -42,6 +42,11 @@ func (daemon *Daemon) ContainerRm(name string, config *types.ContainerRmConfig)
 		return daemon.rmLink(container, name)
 	}
 
+	if dependents := daemon.dependents(container); len(dependents) > 0 {
+		err := fmt.Errorf("container %s is a dependency of %s, remove them first", name, strings.Join(dependents, ", "))
+		return errdefs.Conflict(err)
+	}
+
 	err = daemon.cleanupContainer(container, config.ForceRemove, config.RemoveVolume)
 	containerActions.WithValues("delete").UpdateSince(start)
 
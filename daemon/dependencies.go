@@ -0,0 +1,221 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// dependencyHealthyTimeout is the maximum time to wait for a dependency
+	// to become healthy.
+	dependencyHealthyTimeout = 5 * time.Minute
+	// dependencyPollInterval is the interval at which the health of a
+	// dependency is checked while waiting for it.
+	dependencyPollInterval = 100 * time.Millisecond
+)
+
+// dependency is a container which must meet condition before starting the
+// container depending on it.
+type dependency struct {
+	container *container.Container
+	condition string
+}
+
+func validateDependsOn(dependsOn []containertypes.Dependency) error {
+	for _, dep := range dependsOn {
+		if dep.Container == "" {
+			return errors.New("container name or ID of a dependency cannot be empty")
+		}
+		switch dep.Condition {
+		case "", containertypes.DependencyConditionStarted, containertypes.DependencyConditionHealthy:
+		default:
+			return errors.Errorf("invalid condition %q for dependency %s", dep.Condition, dep.Container)
+		}
+	}
+	return nil
+}
+
+// verifyDependencies checks that the dependencies of a new container exist,
+// and have a healthcheck if the container waits for them to be healthy. The
+// dependencies are stored by ID, so that renaming them does not break them.
+func (daemon *Daemon) verifyDependencies(hostConfig *containertypes.HostConfig) error {
+	for i, dep := range hostConfig.DependsOn {
+		c, err := daemon.GetContainer(dep.Container)
+		if err != nil {
+			return errors.Wrapf(err, "invalid dependency %s", dep.Container)
+		}
+		if dep.Condition == containertypes.DependencyConditionHealthy && getProbe(c) == nil {
+			return errors.Errorf("invalid dependency %s: container has no healthcheck", dep.Container)
+		}
+		hostConfig.DependsOn[i].Container = c.ID
+	}
+	return nil
+}
+
+// dependents returns the sorted names of the containers which depend on c.
+func (daemon *Daemon) dependents(c *container.Container) []string {
+	var names []string
+	for _, other := range daemon.containers.List() {
+		if other.HostConfig == nil {
+			continue
+		}
+		for _, dep := range other.HostConfig.DependsOn {
+			if dep.Container == c.ID {
+				names = append(names, strings.TrimPrefix(other.Name, "/"))
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dependencies returns the containers which c depends on.
+func (daemon *Daemon) dependencies(c *container.Container) ([]dependency, error) {
+	var deps []dependency
+	for _, dep := range c.HostConfig.DependsOn {
+		depContainer, err := daemon.GetContainer(dep.Container)
+		if err != nil {
+			return nil, errors.Wrapf(err, "dependency %s of container %s", dep.Container, strings.TrimPrefix(c.Name, "/"))
+		}
+		condition := dep.Condition
+		if condition == "" {
+			condition = containertypes.DependencyConditionStarted
+		}
+		deps = append(deps, dependency{container: depContainer, condition: condition})
+	}
+	return deps, nil
+}
+
+// dependencyOrder returns the containers which c depends on, directly or
+// not, followed by c, in the order they must be started. An error is
+// returned if a dependency does not exist, or if the dependencies form a
+// cycle.
+func (daemon *Daemon) dependencyOrder(c *container.Container) ([]*container.Container, error) {
+	var (
+		order    []*container.Container
+		visited  = make(map[string]bool)
+		visiting = make(map[string]bool)
+		path     []string
+	)
+
+	var visit func(c *container.Container) error
+	visit = func(c *container.Container) error {
+		name := strings.TrimPrefix(c.Name, "/")
+		if visiting[c.ID] {
+			for i, n := range path {
+				if n == name {
+					path = append(path[i:], name)
+					break
+				}
+			}
+			return errdefs.InvalidParameter(errors.Errorf("dependency cycle between containers: %s", strings.Join(path, " -> ")))
+		}
+		if visited[c.ID] {
+			return nil
+		}
+		visiting[c.ID] = true
+		path = append(path, name)
+
+		deps, err := daemon.dependencies(c)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		for _, dep := range deps {
+			if err := visit(dep.container); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		visiting[c.ID] = false
+		visited[c.ID] = true
+		order = append(order, c)
+		return nil
+	}
+
+	if err := visit(c); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// startDependencies starts the containers which c depends on, directly or
+// not, which are not running, in dependency order. The conditions of the
+// dependencies of each container are met before it is started, and before
+// returning. Waiting for the dependencies stops when ctx is done.
+func (daemon *Daemon) startDependencies(ctx context.Context, c *container.Container) error {
+	order, err := daemon.dependencyOrder(c)
+	if err != nil {
+		return err
+	}
+	for _, current := range order {
+		deps, err := daemon.dependencies(current)
+		if err != nil {
+			return errdefs.InvalidParameter(err)
+		}
+		for _, dep := range deps {
+			if dep.condition != containertypes.DependencyConditionHealthy {
+				continue
+			}
+			if err := daemon.waitForHealthy(ctx, dep.container, dependencyHealthyTimeout); err != nil {
+				return errors.Wrapf(err, "dependency of container %s", strings.TrimPrefix(current.Name, "/"))
+			}
+		}
+		if current == c || current.IsRunning() {
+			continue
+		}
+
+		logrus.Debugf("Starting container %s, which container %s depends on", current.ID, c.ID)
+		if err := daemon.containerStart(current, "", "", true); err != nil {
+			return errors.Wrapf(err, "failed to start dependency %s", strings.TrimPrefix(current.Name, "/"))
+		}
+	}
+	return nil
+}
+
+// waitForHealthy waits for the container to be healthy. An error is
+// returned if it does not become healthy within timeout, or before ctx is
+// done, if it is unhealthy, or if it is not running.
+func (daemon *Daemon) waitForHealthy(ctx context.Context, c *container.Container, timeout time.Duration) error {
+	name := strings.TrimPrefix(c.Name, "/")
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(dependencyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		c.Lock()
+		running, health := c.Running, c.State.Health
+		c.Unlock()
+
+		switch {
+		case !running:
+			return errdefs.Conflict(errors.Errorf("container %s is not running", name))
+		case health == nil:
+			return errdefs.InvalidParameter(errors.Errorf("container %s has no healthcheck", name))
+		}
+		switch health.Status() {
+		case types.Healthy:
+			return nil
+		case types.Unhealthy:
+			return errdefs.Conflict(errors.Errorf("container %s is unhealthy", name))
+		}
+		if time.Now().After(deadline) {
+			return errdefs.Unavailable(errors.Errorf("timeout waiting for container %s to be healthy", name))
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "waiting for container %s to be healthy", name)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/truncindex"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newDependenciesDaemon(t *testing.T, containers ...*container.Container) *Daemon {
+	store := container.NewMemoryStore()
+	index := truncindex.NewTruncIndex([]string{})
+	containersReplica, err := container.NewViewDB()
+	assert.NilError(t, err)
+
+	daemon := &Daemon{
+		containers:        store,
+		containersReplica: containersReplica,
+		idIndex:           index,
+	}
+	for _, c := range containers {
+		store.Add(c.ID, c)
+		index.Add(c.ID)
+		daemon.reserveName(c.ID, c.Name)
+	}
+	return daemon
+}
+
+func newDependentContainer(name string, dependsOn ...string) *container.Container {
+	c := &container.Container{
+		ID:         name + "_id",
+		Name:       "/" + name,
+		HostConfig: &containertypes.HostConfig{},
+		State:      container.NewState(),
+	}
+	for _, dep := range dependsOn {
+		c.HostConfig.DependsOn = append(c.HostConfig.DependsOn, containertypes.Dependency{Container: dep})
+	}
+	return c
+}
+
+func containerNames(containers []*container.Container) string {
+	var names []string
+	for _, c := range containers {
+		names = append(names, strings.TrimPrefix(c.Name, "/"))
+	}
+	return strings.Join(names, ",")
+}
+
+func TestDependencyOrder(t *testing.T) {
+	db := newDependentContainer("db")
+	cache := newDependentContainer("cache")
+	api := newDependentContainer("api", "db", "cache")
+	web := newDependentContainer("web", "api", "db")
+	daemon := newDependenciesDaemon(t, db, cache, api, web)
+
+	order, err := daemon.dependencyOrder(web)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(containerNames(order), "db,cache,api,web"))
+
+	order, err = daemon.dependencyOrder(db)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(containerNames(order), "db"))
+}
+
+func TestDependencyOrderErrors(t *testing.T) {
+	a := newDependentContainer("a", "b")
+	b := newDependentContainer("b", "c")
+	c := newDependentContainer("c", "a")
+	d := newDependentContainer("d", "missing")
+	daemon := newDependenciesDaemon(t, a, b, c, d)
+
+	_, err := daemon.dependencyOrder(a)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	assert.Check(t, is.ErrorContains(err, "dependency cycle between containers: a -> b -> c -> a"))
+
+	_, err = daemon.dependencyOrder(d)
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+	assert.Check(t, is.ErrorContains(err, "dependency missing of container d"))
+}
+
+func TestValidateDependsOn(t *testing.T) {
+	assert.NilError(t, validateDependsOn([]containertypes.Dependency{
+		{Container: "db"},
+		{Container: "cache", Condition: "started"},
+		{Container: "api", Condition: "healthy"},
+	}))
+	assert.Check(t, validateDependsOn([]containertypes.Dependency{{Container: ""}}) != nil)
+	assert.Check(t, validateDependsOn([]containertypes.Dependency{{Container: "db", Condition: "ready"}}) != nil)
+}
+
+func TestWaitForHealthy(t *testing.T) {
+	c := newDependentContainer("db")
+	daemon := newDependenciesDaemon(t, c)
+
+	err := daemon.waitForHealthy(context.Background(), c, time.Second)
+	assert.Check(t, is.ErrorContains(err, "container db is not running"))
+
+	c.Running = true
+	c.State.Health = &container.Health{}
+	c.State.Health.SetStatus(types.Starting)
+	err = daemon.waitForHealthy(context.Background(), c, 0)
+	assert.Check(t, is.ErrorContains(err, "timeout waiting for container db to be healthy"))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		c.State.Health.SetStatus(types.Healthy)
+	}()
+	assert.NilError(t, daemon.waitForHealthy(context.Background(), c, 10*time.Second))
+
+	c.State.Health.SetStatus(types.Unhealthy)
+	err = daemon.waitForHealthy(context.Background(), c, time.Second)
+	assert.Check(t, is.ErrorContains(err, "container db is unhealthy"))
+
+	c.State.Health.SetStatus(types.Starting)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = daemon.waitForHealthy(ctx, c, time.Minute)
+	assert.Check(t, is.ErrorContains(err, "waiting for container db to be healthy: context canceled"))
+}
+
+func TestVerifyDependenciesStoresIDs(t *testing.T) {
+	db := newDependentContainer("db")
+	cache := newDependentContainer("cache")
+	daemon := newDependenciesDaemon(t, db, cache)
+
+	hostConfig := &containertypes.HostConfig{
+		DependsOn: []containertypes.Dependency{{Container: "db"}, {Container: cache.ID}},
+	}
+	assert.NilError(t, daemon.verifyDependencies(hostConfig))
+	assert.Check(t, is.Equal(hostConfig.DependsOn[0].Container, db.ID))
+	assert.Check(t, is.Equal(hostConfig.DependsOn[1].Container, cache.ID))
+
+	err := daemon.verifyDependencies(&containertypes.HostConfig{
+		DependsOn: []containertypes.Dependency{{Container: "missing"}},
+	})
+	assert.Check(t, is.ErrorContains(err, "invalid dependency missing"))
+}
+
+func TestDependents(t *testing.T) {
+	db := newDependentContainer("db")
+	api := newDependentContainer("api", db.ID)
+	web := newDependentContainer("web", "api_id", db.ID)
+	daemon := newDependenciesDaemon(t, db, api, web)
+
+	assert.Check(t, is.DeepEqual(daemon.dependents(db), []string{"api", "web"}))
+	assert.Check(t, is.DeepEqual(daemon.dependents(api), []string{"web"}))
+	assert.Check(t, is.Len(daemon.dependents(web), 0))
+}
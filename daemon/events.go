@@ -104,6 +104,13 @@ func (daemon *Daemon) SubscribeToEvents(since, until time.Time, filter filters.A
 	return daemon.EventsService.SubscribeTopic(since, until, ef)
 }
 
+// SubscribeToEventsWithReplay returns a channel to stream new events from,
+// after passing the past events to replay.
+func (daemon *Daemon) SubscribeToEventsWithReplay(since, until time.Time, filter filters.Args, replay func(events.Message) error) (chan interface{}, error) {
+	ef := daemonevents.NewFilter(filter)
+	return daemon.EventsService.SubscribeTopicWithReplay(since, until, ef, replay)
+}
+
 // UnsubscribeFromEvents stops the event subscription for a client by closing the
 // channel where the daemon sends events to.
 func (daemon *Daemon) UnsubscribeFromEvents(listener chan interface{}) {
@@ -15,9 +15,10 @@ const (
 
 // Events is pubsub channel for events generated by the engine.
 type Events struct {
-	mu     sync.Mutex
-	events []eventtypes.Message
-	pub    *pubsub.Publisher
+	mu      sync.Mutex
+	events  []eventtypes.Message
+	pub     *pubsub.Publisher
+	journal *journal
 }
 
 // New returns new *Events instance
@@ -28,6 +29,32 @@ func New() *Events {
 	}
 }
 
+// OpenJournal enables the on-disk journal of events stored in root, from
+// which events older than the ones kept in memory are replayed. The journal
+// is bounded by maxSize bytes, and by the maxAge of its events if not zero.
+func (e *Events) OpenJournal(root string, maxSize int64, maxAge time.Duration) error {
+	j, err := openJournal(root, maxSize, maxAge)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.journal = j
+	e.mu.Unlock()
+	return nil
+}
+
+// Close closes the journal of events, if any.
+func (e *Events) Close() error {
+	e.mu.Lock()
+	j := e.journal
+	e.journal = nil
+	e.mu.Unlock()
+	if j == nil {
+		return nil
+	}
+	return j.close()
+}
+
 // Subscribe adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
 // of interface{}, so you need type assertion), and a function to call
@@ -48,8 +75,22 @@ func (e *Events) Subscribe() ([]eventtypes.Message, chan interface{}, func()) {
 
 // SubscribeTopic adds new listener to events, returns slice of 256 stored
 // last events, a channel in which you can expect new events (in form
-// of interface{}, so you need type assertion).
+// of interface{}, so you need type assertion). If the journal is enabled
+// and since predates the stored events, the events are read from the journal.
 func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtypes.Message, chan interface{}) {
+	var buffered []eventtypes.Message
+	ch, _ := e.SubscribeTopicWithReplay(since, until, ef, func(ev eventtypes.Message) error {
+		buffered = append(buffered, ev)
+		return nil
+	})
+	return buffered, ch
+}
+
+// SubscribeTopicWithReplay adds new listener to events like SubscribeTopic,
+// but passes the past events to replay instead of returning them, so that
+// the events read from the journal are streamed. The listener is evicted,
+// and the error returned, if replay returns an error.
+func (e *Events) SubscribeTopicWithReplay(since, until time.Time, ef *Filter, replay func(eventtypes.Message) error) (chan interface{}, error) {
 	eventSubscribers.Inc()
 	e.mu.Lock()
 
@@ -58,7 +99,22 @@ func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtype
 		topic = func(m interface{}) bool { return ef.Include(m.(eventtypes.Message)) }
 	}
 
-	buffered := e.loadBufferedEvents(since, until, topic)
+	var (
+		buffered    = e.loadBufferedEvents(since, until, topic)
+		fromJournal = e.sinceBeforeBuffer(since)
+		j           = e.journal
+		queued      uint64
+		bufferStart int64
+	)
+	if fromJournal {
+		// The events older than the ones in memory are read from the
+		// journal, once the events queued to it so far are written. The
+		// following events are sent to the subscription.
+		queued = j.queuedCount()
+		if len(e.events) > 0 {
+			bufferStart = e.events[0].TimeNano
+		}
+	}
 
 	var ch chan interface{}
 	if topic != nil {
@@ -69,7 +125,42 @@ func (e *Events) SubscribeTopic(since, until time.Time, ef *Filter) ([]eventtype
 	}
 
 	e.mu.Unlock()
-	return buffered, ch
+
+	err := func() error {
+		if fromJournal {
+			// The journal is read up to the events kept in memory, which
+			// also have the events dropped from the journal.
+			err := j.read(j.segmentsAfter(queued), since, until, topic, func(ev eventtypes.Message) error {
+				if bufferStart != 0 && ev.TimeNano >= bufferStart {
+					return nil
+				}
+				return replay(ev)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		for _, ev := range buffered {
+			if err := replay(ev); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		e.Evict(ch)
+		return nil, err
+	}
+	return ch, nil
+}
+
+// sinceBeforeBuffer returns whether events since the given time must be
+// replayed from the journal, because they predate the events kept in memory.
+func (e *Events) sinceBeforeBuffer(since time.Time) bool {
+	if e.journal == nil || since.IsZero() {
+		return false
+	}
+	return len(e.events) == 0 || since.UnixNano() < e.events[0].TimeNano
 }
 
 // Evict evicts listener from pubsub
@@ -117,6 +208,9 @@ func (e *Events) PublishMessage(jm eventtypes.Message) {
 	} else {
 		e.events = append(e.events, jm)
 	}
+	if e.journal != nil {
+		e.journal.enqueue(jm)
+	}
 	e.mu.Unlock()
 	e.pub.Publish(jm)
 }
@@ -0,0 +1,339 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	eventtypes "github.com/docker/docker/api/types/events"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// journalSegments is the number of segment files the journal size is
+	// split into. The oldest segment is removed when the journal grows
+	// larger than its maximum size.
+	journalSegments = 4
+	// journalPruneInterval is the interval at which segments older than the
+	// maximum age are removed.
+	journalPruneInterval = time.Minute
+	// maxJournalLineSize is the maximum size of an event in the journal.
+	maxJournalLineSize = 1024 * 1024
+
+	// journalQueueSize is the number of events queued to be written to the
+	// journal. Events are dropped from the journal when the queue is full,
+	// and are only replayed while they are kept in memory.
+	journalQueueSize = bufferSize
+
+	segmentPrefix = "events-"
+	segmentSuffix = ".log"
+)
+
+// journal is an on-disk log of events, made of segment files holding one
+// JSON-encoded event per line. The journal is bounded by size and by the age
+// of its events. Events are queued, and written by a goroutine so that
+// publishing events doesn't wait for the disk.
+type journal struct {
+	// dropped is the number of events dropped from the journal because the
+	// queue was full, and queued the number of events queued so far. They
+	// are first in the struct for the alignment required by atomic
+	// operations.
+	dropped uint64
+	queued  uint64
+
+	root        string
+	maxSize     int64
+	maxAge      time.Duration
+	segmentSize int64
+
+	mu        sync.Mutex
+	written   sync.Cond // signaled when queued events are written, or the journal is closed
+	nwritten  uint64    // number of queued events written, or failed to be
+	closed    bool
+	f         *os.File
+	size      int64 // size of the current segment
+	lastPrune time.Time
+
+	queue chan eventtypes.Message
+	done  chan struct{}
+}
+
+// journalSegment is a segment file, and the size of it which was written
+// when it was listed.
+type journalSegment struct {
+	path string
+	size int64
+}
+
+func openJournal(root string, maxSize int64, maxAge time.Duration) (*journal, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, errors.Wrap(err, "error creating events journal directory")
+	}
+	j := &journal{
+		root:        root,
+		maxSize:     maxSize,
+		maxAge:      maxAge,
+		segmentSize: maxSize / journalSegments,
+		queue:       make(chan eventtypes.Message, journalQueueSize),
+		done:        make(chan struct{}),
+	}
+	j.written.L = &j.mu
+
+	segments, err := j.listSegments()
+	if err != nil {
+		return nil, err
+	}
+	if n := len(segments); n > 0 && segments[n-1].size < j.segmentSize {
+		if err := j.openSegment(segments[n-1].path); err != nil {
+			return nil, err
+		}
+	} else if err := j.rotate(time.Now()); err != nil {
+		return nil, err
+	}
+	j.prune(time.Now())
+	go j.run()
+	return j, nil
+}
+
+// run writes the queued events to the journal until the queue is closed.
+func (j *journal) run() {
+	defer close(j.done)
+	for ev := range j.queue {
+		if err := j.write(ev); err != nil {
+			logrus.WithError(err).Warn("Error writing event to the events journal")
+		}
+		j.mu.Lock()
+		j.nwritten++
+		j.written.Broadcast()
+		j.mu.Unlock()
+
+		if n := atomic.SwapUint64(&j.dropped, 0); n > 0 {
+			logrus.Warnf("Dropped %d events from the events journal as its queue was full", n)
+		}
+	}
+}
+
+// enqueue queues the event to be written to the journal, or drops it if the
+// queue is full. It must not be called after close, nor concurrently.
+func (j *journal) enqueue(ev eventtypes.Message) {
+	select {
+	case j.queue <- ev:
+		atomic.AddUint64(&j.queued, 1)
+	default:
+		atomic.AddUint64(&j.dropped, 1)
+	}
+}
+
+// queuedCount returns the number of events queued so far, to be passed to
+// segmentsAfter. It must not be called concurrently with enqueue.
+func (j *journal) queuedCount() uint64 {
+	return atomic.LoadUint64(&j.queued)
+}
+
+// segmentsAfter waits until the first n queued events are written, and
+// returns the segments of the journal, or nil if they cannot be listed.
+func (j *journal) segmentsAfter(n uint64) []journalSegment {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for j.nwritten < n && !j.closed {
+		j.written.Wait()
+	}
+	segments, err := j.listSegments()
+	if err != nil {
+		logrus.WithError(err).Warn("Error reading events journal")
+		return nil
+	}
+	return segments
+}
+
+// listSegments returns the segments of the journal, from the oldest to the
+// newest.
+func (j *journal) listSegments() ([]journalSegment, error) {
+	files, err := ioutil.ReadDir(j.root)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading events journal directory")
+	}
+	var segments []journalSegment
+	for _, fi := range files {
+		if fi.IsDir() || !strings.HasPrefix(fi.Name(), segmentPrefix) || !strings.HasSuffix(fi.Name(), segmentSuffix) {
+			continue
+		}
+		segments = append(segments, journalSegment{path: filepath.Join(j.root, fi.Name()), size: fi.Size()})
+	}
+	// segment names hold their zero-padded creation time
+	sort.Slice(segments, func(i, k int) bool { return segments[i].path < segments[k].path })
+	return segments, nil
+}
+
+func (j *journal) openSegment(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return errors.Wrap(err, "error opening events journal")
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrap(err, "error opening events journal")
+	}
+	if j.f != nil {
+		j.f.Close()
+	}
+	j.f = f
+	j.size = fi.Size()
+	return nil
+}
+
+// rotate starts a new segment.
+func (j *journal) rotate(now time.Time) error {
+	name := fmt.Sprintf("%s%020d%s", segmentPrefix, now.UnixNano(), segmentSuffix)
+	return j.openSegment(filepath.Join(j.root, name))
+}
+
+// write appends the event to the journal.
+func (j *journal) write(ev eventtypes.Message) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.f == nil {
+		return errors.New("events journal is closed")
+	}
+
+	now := time.Now()
+	if j.size > 0 && j.size+int64(len(b)) > j.segmentSize {
+		if err := j.rotate(now); err != nil {
+			return err
+		}
+		j.prune(now)
+	} else if now.Sub(j.lastPrune) > journalPruneInterval {
+		j.prune(now)
+	}
+
+	n, err := j.f.Write(b)
+	j.size += int64(n)
+	return err
+}
+
+// prune removes the oldest segments while the journal is larger than its
+// maximum size, and the segments whose events are all older than its
+// maximum age. The current segment is never removed.
+func (j *journal) prune(now time.Time) {
+	j.lastPrune = now
+
+	segments, err := j.listSegments()
+	if err != nil {
+		logrus.WithError(err).Warn("Error pruning events journal")
+		return
+	}
+	var total int64
+	for _, s := range segments {
+		total += s.size
+	}
+	for _, s := range segments {
+		if s.path == j.f.Name() {
+			break
+		}
+		if total <= j.maxSize && (j.maxAge == 0 || !j.olderThan(s.path, now.Add(-j.maxAge))) {
+			break
+		}
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			logrus.WithError(err).WithField("file", s.path).Warn("Error removing events journal segment")
+			return
+		}
+		total -= s.size
+	}
+}
+
+// olderThan returns whether the last event in the segment is older than t.
+func (j *journal) olderThan(path string, t time.Time) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.ModTime().Before(t)
+}
+
+// segments returns the segments of the journal, with the size written so far.
+func (j *journal) segments() ([]journalSegment, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.listSegments()
+}
+
+// read streams to fn the events of the segments which were emitted between
+// since and until, and are accepted by topic if it's not nil. A zero until
+// does not bound the events. Reading stops at the first error returned by fn.
+func (j *journal) read(segments []journalSegment, since, until time.Time, topic func(interface{}) bool, fn func(eventtypes.Message) error) error {
+	sinceNanoUnix := since.UnixNano()
+	var untilNanoUnix int64
+	if !until.IsZero() {
+		untilNanoUnix = until.UnixNano()
+	}
+
+	for _, s := range segments {
+		if j.olderThan(s.path, since) {
+			continue
+		}
+		f, err := os.Open(s.path)
+		if err != nil {
+			// the segment may have been pruned since it was listed
+			if !os.IsNotExist(err) {
+				logrus.WithError(err).WithField("file", s.path).Warn("Error reading events journal")
+			}
+			continue
+		}
+
+		scanner := bufio.NewScanner(io.LimitReader(f, s.size))
+		scanner.Buffer(make([]byte, 0, 64*1024), maxJournalLineSize)
+		for scanner.Scan() {
+			var ev eventtypes.Message
+			if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+				// skip events truncated by a crash
+				continue
+			}
+			if ev.TimeNano < sinceNanoUnix || (untilNanoUnix > 0 && ev.TimeNano > untilNanoUnix) {
+				continue
+			}
+			if topic == nil || topic(ev) {
+				if err := fn(ev); err != nil {
+					f.Close()
+					return err
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			logrus.WithError(err).WithField("file", s.path).Warn("Error reading events journal")
+		}
+		f.Close()
+	}
+	return nil
+}
+
+// close writes the queued events and closes the journal.
+func (j *journal) close() error {
+	close(j.queue)
+	<-j.done
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.closed = true
+	j.written.Broadcast()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
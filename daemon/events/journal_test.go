@@ -0,0 +1,250 @@
+package events // import "github.com/docker/docker/daemon/events"
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+func publishEvents(e *Events, start time.Time, n int) {
+	for i := 0; i < n; i++ {
+		ts := start.Add(time.Duration(i) * time.Millisecond)
+		typ := events.ContainerEventType
+		if i%2 == 1 {
+			typ = events.NetworkEventType
+		}
+		e.PublishMessage(events.Message{
+			Action:   fmt.Sprintf("action_%d", i),
+			Type:     typ,
+			Actor:    events.Actor{ID: fmt.Sprintf("id_%d", i)},
+			Time:     ts.Unix(),
+			TimeNano: ts.UnixNano(),
+		})
+	}
+}
+
+func TestJournalReplay(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	e := New()
+	if err := e.OpenJournal(root, 1024*1024, 0); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-time.Hour)
+	publishEvents(e, start, eventsLimit+44)
+
+	// Events older than the ones kept in memory are read from the journal.
+	buffered, l := e.SubscribeTopic(start, time.Time{}, nil)
+	e.Evict(l)
+	if len(buffered) != eventsLimit+44 {
+		t.Fatalf("expected %d events, got %d", eventsLimit+44, len(buffered))
+	}
+	if buffered[0].Action != "action_0" || buffered[len(buffered)-1].Action != fmt.Sprintf("action_%d", eventsLimit+43) {
+		t.Fatalf("unexpected events %v ... %v", buffered[0], buffered[len(buffered)-1])
+	}
+
+	// Filters and until are applied to the events from the journal.
+	ef := NewFilter(filters.NewArgs(filters.Arg("type", events.NetworkEventType)))
+	buffered, l = e.SubscribeTopic(start, start.Add(9*time.Millisecond), ef)
+	e.Evict(l)
+	if len(buffered) != 5 {
+		t.Fatalf("expected 5 events, got %d: %v", len(buffered), buffered)
+	}
+	for _, ev := range buffered {
+		if ev.Type != events.NetworkEventType {
+			t.Fatalf("unexpected event %v", ev)
+		}
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Events are replayed from the journal after a restart.
+	e = New()
+	if err := e.OpenJournal(root, 1024*1024, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	publishEvents(e, time.Now(), 1)
+
+	buffered, l = e.SubscribeTopic(start.Add(eventsLimit*time.Millisecond), time.Time{}, nil)
+	e.Evict(l)
+	if len(buffered) != 45 {
+		t.Fatalf("expected 45 events, got %d", len(buffered))
+	}
+
+	// Replaying stops at the first error, and the listener is evicted.
+	errReplay := errors.New("replay error")
+	var n int
+	_, err = e.SubscribeTopicWithReplay(start, time.Time{}, nil, func(events.Message) error {
+		if n++; n == 10 {
+			return errReplay
+		}
+		return nil
+	})
+	if err != errReplay {
+		t.Fatalf("expected %v, got %v", errReplay, err)
+	}
+	if n != 10 {
+		t.Fatalf("expected replay to stop after 10 events, got %d", n)
+	}
+	if count := e.SubscribersCount(); count != 0 {
+		t.Fatalf("expected the listener to be evicted, got %d listeners", count)
+	}
+}
+
+func TestJournalReplayWriterBlocked(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	e := New()
+	if err := e.OpenJournal(root, 1024*1024, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// Block the writer of the journal, so that its queue fills up and the
+	// last events are dropped from it.
+	e.journal.mu.Lock()
+	start := time.Now().Add(-time.Hour)
+	n := journalQueueSize + 10
+	publishEvents(e, start, n)
+
+	type result struct {
+		events []events.Message
+		err    error
+	}
+	replayed := make(chan result, 1)
+	go func() {
+		var out []events.Message
+		l, err := e.SubscribeTopicWithReplay(start, time.Time{}, nil, func(ev events.Message) error {
+			out = append(out, ev)
+			return nil
+		})
+		if err == nil {
+			e.Evict(l)
+		}
+		replayed <- result{out, err}
+	}()
+	for e.SubscribersCount() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Publishing events does not wait for the replay.
+	published := make(chan struct{})
+	go func() {
+		publishEvents(e, time.Now(), 1)
+		close(published)
+	}()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing an event was blocked by the replay")
+	}
+	e.journal.mu.Unlock()
+
+	// The events dropped from the journal are replayed from memory.
+	res := <-replayed
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	if len(res.events) != n {
+		t.Fatalf("expected %d events, got %d", n, len(res.events))
+	}
+	for i, ev := range res.events {
+		if expected := fmt.Sprintf("action_%d", i); ev.Action != expected {
+			t.Fatalf("expected event %s, got %v", expected, ev)
+		}
+	}
+}
+
+func TestJournalPruneSize(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	const maxSize = 16 * 1024
+	j, err := openJournal(root, maxSize, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.close()
+
+	start := time.Now()
+	for i := 0; i < 1000; i++ {
+		ts := start.Add(time.Duration(i) * time.Millisecond)
+		if err := j.write(events.Message{Action: fmt.Sprintf("action_%d", i), TimeNano: ts.UnixNano()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	segments, err := j.segments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var size int64
+	for _, s := range segments {
+		size += s.size
+	}
+	if size > maxSize+maxSize/journalSegments {
+		t.Fatalf("expected the journal to be pruned to %d bytes, got %d", maxSize, size)
+	}
+
+	var out []events.Message
+	if err := j.read(segments, start, time.Time{}, nil, func(ev events.Message) error {
+		out = append(out, ev)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(out) == 0 || len(out) == 1000 {
+		t.Fatalf("expected the oldest events to be pruned, got %d events", len(out))
+	}
+	if last := out[len(out)-1]; last.Action != "action_999" {
+		t.Fatalf("expected the newest event to be kept, got %v", last)
+	}
+}
+
+func TestJournalPruneAge(t *testing.T) {
+	root, err := ioutil.TempDir("", "events-journal-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	j, err := openJournal(root, 1024*1024, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.close()
+	if err := j.write(events.Message{Action: "old", TimeNano: time.Now().Add(-2 * time.Hour).UnixNano()}); err != nil {
+		t.Fatal(err)
+	}
+	old := j.f.Name()
+	if err := j.rotate(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(old, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	j.prune(time.Now())
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("expected the old segment to be removed, got %v", err)
+	}
+}
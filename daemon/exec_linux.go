@@ -56,6 +56,10 @@ func (daemon *Daemon) execSetPlatformOpt(c *container.Container, ec *exec.Config
 		}
 		p.ApparmorProfile = appArmorProfile
 	}
+	// the OOM score adjustment and ulimits of the container may have been
+	// updated since it started
+	oomScoreAdj := c.HostConfig.OomScoreAdj
+	p.OOMScoreAdj = &oomScoreAdj
 	s := &specs.Spec{Process: p}
 	return WithRlimits(daemon, c)(context.Background(), nil, nil, s)
 }
@@ -3,13 +3,16 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"fmt"
 	"io"
+	"os"
 	"runtime"
 
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/system"
+	"github.com/pkg/errors"
 )
 
 // ContainerExport writes the contents of the container to the given
@@ -47,6 +50,123 @@ func (daemon *Daemon) ContainerExport(name string, out io.Writer) error {
 	return nil
 }
 
+// ContainerExportRWLayer returns the changes of the RW layer of the container
+// to its image as a tar stream, along with the ID of the image. It's up to
+// the caller to close the stream. An error is returned if the container
+// cannot be found.
+func (daemon *Daemon) ContainerExportRWLayer(name string) (io.ReadCloser, string, error) {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if runtime.GOOS == "windows" && container.OS == "windows" {
+		return nil, "", fmt.Errorf("the daemon on this operating system does not support exporting Windows containers")
+	}
+
+	if container.IsDead() {
+		err := fmt.Errorf("You cannot export container %s which is Dead", container.ID)
+		return nil, "", errdefs.Conflict(err)
+	}
+
+	if container.IsRemovalInProgress() {
+		err := fmt.Errorf("You cannot export container %s which is being removed", container.ID)
+		return nil, "", errdefs.Conflict(err)
+	}
+
+	data, err := daemon.imageService.ExportContainerRWLayer(container.ID, container.OS, container.GetMountLabel())
+	if err != nil {
+		return nil, "", fmt.Errorf("Error exporting container %s: %v", name, err)
+	}
+	daemon.LogContainerEventWithAttributes(container, "export", map[string]string{
+		"layer": "rw",
+	})
+	return data, container.ImageID.String(), nil
+}
+
+// ContainerImportRWLayer applies the changes of the RW layer exported from
+// another container created from the same image, read from the given reader,
+// to the RW layer of the container. If imageID is set, it is the ID of the
+// image of the container the layer was exported from, and an error is
+// returned if the container was created from another image. An error is
+// also returned if the container cannot be found, or is running.
+func (daemon *Daemon) ContainerImportRWLayer(name, imageID string, in io.Reader) error {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" && container.OS == "windows" {
+		return fmt.Errorf("the daemon on this operating system does not support importing into Windows containers")
+	}
+
+	if imageID != "" && image.ID(imageID) != container.ImageID {
+		err := fmt.Errorf("You cannot import a layer exported from a container of image %s into container %s of image %s", imageID, container.ID, container.ImageID)
+		return errdefs.InvalidParameter(err)
+	}
+
+	container.Lock()
+	err = checkImportRWLayer(container)
+	container.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// The changes are extracted to a staging directory without holding the
+	// container lock, as reading them may take as long as the client takes
+	// to send them, and are only applied to the RW layer once complete.
+	staging, err := ioutils.TempDir("", "docker-import-")
+	if err != nil {
+		return fmt.Errorf("Error importing into container %s: %v", name, err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := archive.Untar(in, staging, &archive.TarOptions{Compression: archive.Uncompressed}); err != nil {
+		return errdefs.InvalidParameter(errors.Wrapf(err, "Error importing into container %s", name))
+	}
+
+	container.Lock()
+	defer container.Unlock()
+
+	if err := checkImportRWLayer(container); err != nil {
+		return err
+	}
+
+	changes, err := archive.Tar(staging, archive.Uncompressed)
+	if err != nil {
+		return fmt.Errorf("Error importing into container %s: %v", name, err)
+	}
+	defer changes.Close()
+
+	if _, err := daemon.imageService.ImportContainerRWLayer(container.ID, container.OS, changes); err != nil {
+		return fmt.Errorf("Error importing into container %s: %v", name, err)
+	}
+	daemon.LogContainerEventWithAttributes(container, "import", map[string]string{
+		"layer": "rw",
+	})
+	return nil
+}
+
+// checkImportRWLayer returns an error if the changes of a RW layer cannot be
+// imported into the container. The container must be locked.
+func checkImportRWLayer(container *container.Container) error {
+	if container.Running {
+		err := fmt.Errorf("You cannot import into container %s which is running", container.ID)
+		return errdefs.Conflict(err)
+	}
+
+	if container.Dead {
+		err := fmt.Errorf("You cannot import into container %s which is Dead", container.ID)
+		return errdefs.Conflict(err)
+	}
+
+	if container.RemovalInProgress {
+		err := fmt.Errorf("You cannot import into container %s which is being removed", container.ID)
+		return errdefs.Conflict(err)
+	}
+	return nil
+}
+
 func (daemon *Daemon) containerExport(container *container.Container) (arch io.ReadCloser, err error) {
 	if !system.IsOSSupported(container.OS) {
 		return nil, fmt.Errorf("cannot export %s: %s ", container.ID, system.ErrNotSupportedOperatingSystem)
@@ -217,21 +217,46 @@ func (q *Control) GetQuota(targetPath string, quota *Quota) error {
 	//
 	// get the quota limit for the container's project id
 	//
+	d, err := getProjectQuota(q.backingFsBlockDev, projectID)
+	if err != nil {
+		return err
+	}
+	quota.Size = uint64(d.d_blk_hardlimit) * 512
+
+	return nil
+}
+
+// GetUsage - get the number of bytes used by a directory that was configured with SetQuota
+func (q *Control) GetUsage(targetPath string) (uint64, error) {
+	q.RLock()
+	projectID, ok := q.quotas[targetPath]
+	q.RUnlock()
+	if !ok {
+		return 0, errors.Errorf("quota not found for path: %s", targetPath)
+	}
+
+	d, err := getProjectQuota(q.backingFsBlockDev, projectID)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(d.d_bcount) * 512, nil
+}
+
+// getProjectQuota - get the quota limits and usage for project id on xfs block device
+func getProjectQuota(backingFsBlockDev string, projectID uint32) (C.fs_disk_quota_t, error) {
 	var d C.fs_disk_quota_t
 
-	var cs = C.CString(q.backingFsBlockDev)
+	var cs = C.CString(backingFsBlockDev)
 	defer C.free(unsafe.Pointer(cs))
 
 	_, _, errno := unix.Syscall6(unix.SYS_QUOTACTL, C.Q_XGETPQUOTA,
 		uintptr(unsafe.Pointer(cs)), uintptr(C.__u32(projectID)),
 		uintptr(unsafe.Pointer(&d)), 0, 0)
 	if errno != 0 {
-		return errors.Wrapf(errno, "Failed to get quota limit for projid %d on %s",
-			projectID, q.backingFsBlockDev)
+		return d, errors.Wrapf(errno, "Failed to get quota limit for projid %d on %s",
+			projectID, backingFsBlockDev)
 	}
-	quota.Size = uint64(d.d_blk_hardlimit) * 512
-
-	return nil
+	return d, nil
 }
 
 // getProjectID - get the project id of path on xfs
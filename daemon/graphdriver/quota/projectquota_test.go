@@ -149,4 +149,17 @@ func testRetrieveQuota(t *testing.T, ctrl *Control, homeDir, testDir, testSubDir
 	var q Quota
 	assert.NilError(t, ctrl.GetQuota(testSubDir, &q))
 	assert.Check(t, is.Equal(uint64(testQuotaSize), q.Size))
+
+	// Validate that we can retrieve the usage
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(testSubDir, "usage"), make([]byte, testQuotaSize/2), 0644))
+	usage, err := ctrl.GetUsage(testSubDir)
+	assert.NilError(t, err)
+	assert.Check(t, usage >= uint64(testQuotaSize/2), usage)
+
+	// Validate that the quota can be retrieved after moving the directory
+	movedDir := testSubDir + "-moved"
+	assert.NilError(t, os.Rename(testSubDir, movedDir))
+	ctrl.MoveQuota(testSubDir, movedDir)
+	assert.NilError(t, ctrl.GetQuota(movedDir, &q))
+	assert.Check(t, is.Equal(uint64(testQuotaSize), q.Size))
 }
@@ -16,3 +16,8 @@ func (q *Control) SetQuota(targetPath string, quota Quota) error {
 func (q *Control) GetQuota(targetPath string, quota *Quota) error {
 	return ErrQuotaNotSupported
 }
+
+// GetUsage - get the number of bytes used by a directory that was configured with SetQuota
+func (q *Control) GetUsage(targetPath string) (uint64, error) {
+	return 0, ErrQuotaNotSupported
+}
@@ -17,3 +17,15 @@ type Control struct {
 	nextProjectID     uint32
 	quotas            map[string]uint32
 }
+
+// MoveQuota - update the bookkeeping of a directory that was configured with
+// SetQuota after it has been renamed. The project id is stored with the
+// directory itself, so the quota limits are retained.
+func (q *Control) MoveQuota(oldPath, newPath string) {
+	q.Lock()
+	defer q.Unlock()
+	if projectID, ok := q.quotas[oldPath]; ok {
+		delete(q.quotas, oldPath)
+		q.quotas[newPath] = projectID
+	}
+}
@@ -3,16 +3,27 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/docker/docker/api/types"
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/exec"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
@@ -43,7 +54,8 @@ const (
 const (
 	// Exit status codes that can be returned by the probe command.
 
-	exitStatusHealthy = 0 // Container is healthy
+	exitStatusHealthy   = 0 // Container is healthy
+	exitStatusUnhealthy = 1 // Container is unhealthy
 )
 
 // probe implementations know how to run a particular type of probe.
@@ -55,6 +67,7 @@ type probe interface {
 
 // cmdProbe implements the "CMD" probe type.
 type cmdProbe struct {
+	test []string
 	// Run the command with the system's default shell instead of execing it directly.
 	shell bool
 }
@@ -62,7 +75,7 @@ type cmdProbe struct {
 // exec the healthcheck command in the container.
 // Returns the exit code and probe output (if any)
 func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
-	cmdSlice := strslice.StrSlice(cntr.Config.Healthcheck.Test)[1:]
+	cmdSlice := strslice.StrSlice(p.test)[1:]
 	if p.shell {
 		cmdSlice = append(getShell(cntr), cmdSlice...)
 	}
@@ -113,6 +126,157 @@ func (p *cmdProbe) run(ctx context.Context, d *Daemon, cntr *container.Container
 	}, nil
 }
 
+// httpProbe implements the "HTTP" and "HTTP-GET" probe types.
+type httpProbe struct {
+	test []string
+}
+
+// Send an HTTP request to the container, from its network namespace. The
+// container is healthy if the response has a 2xx or 3xx status code.
+func (p *httpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	method, u, err := parseHTTPProbe(p.test)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return d.dialInContainer(ctx, cntr, network, addr)
+		},
+		// The probe checks that the endpoint is available, not its identity.
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true}, // nolint: gosec
+		DisableKeepAlives: true,
+	}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", "Docker-Healthcheck/"+dockerversion.Version)
+
+	start := time.Now()
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	defer resp.Body.Close()
+	latency := time.Since(start)
+
+	output := &limitedBuffer{}
+	io.Copy(output, io.LimitReader(resp.Body, maxOutputLen+1))
+
+	exitCode := exitStatusUnhealthy
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusBadRequest {
+		exitCode = exitStatusHealthy
+	}
+	return &types.HealthcheckResult{
+		End:        time.Now(),
+		ExitCode:   exitCode,
+		Output:     output.String(),
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+	}, nil
+}
+
+// tcpProbe implements the "TCP" probe type.
+type tcpProbe struct {
+	test []string
+}
+
+// Connect to the container, from its network namespace. The container is
+// healthy if the connection is established.
+func (p *tcpProbe) run(ctx context.Context, d *Daemon, cntr *container.Container) (*types.HealthcheckResult, error) {
+	addr, err := parseTCPProbe(p.test)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	conn, err := d.dialInContainer(ctx, cntr, "tcp", addr)
+	if err != nil {
+		return &types.HealthcheckResult{
+			End:      time.Now(),
+			ExitCode: exitStatusUnhealthy,
+			Output:   err.Error(),
+		}, nil
+	}
+	latency := time.Since(start)
+	conn.Close()
+
+	return &types.HealthcheckResult{
+		End:      time.Now(),
+		ExitCode: exitStatusHealthy,
+		Output:   fmt.Sprintf("Connected to %s", addr),
+		Latency:  latency,
+	}, nil
+}
+
+// parseHTTPProbe returns the method and URL of an "HTTP" ({"HTTP", method,
+// url}) or "HTTP-GET" ({"HTTP-GET", url}) healthcheck test.
+func parseHTTPProbe(test []string) (string, *url.URL, error) {
+	method, rawURL := http.MethodGet, ""
+	if test[0] == "HTTP-GET" {
+		if len(test) != 2 {
+			return "", nil, errors.New("invalid HTTP-GET healthcheck: expected a URL")
+		}
+		rawURL = test[1]
+	} else {
+		if len(test) != 3 {
+			return "", nil, errors.New("invalid HTTP healthcheck: expected a method and a URL")
+		}
+		method, rawURL = strings.ToUpper(test[1]), test[2]
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "invalid %s healthcheck URL", test[0])
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", nil, errors.Errorf("invalid %s healthcheck URL %q: must be an http:// or https:// URL", test[0], rawURL)
+	}
+	return method, u, nil
+}
+
+// parseTCPProbe returns the address of a "TCP" ({"TCP", address}) healthcheck
+// test. The address is a host:port pair, or a port on the loopback interface.
+func parseTCPProbe(test []string) (string, error) {
+	if len(test) != 2 {
+		return "", errors.New("invalid TCP healthcheck: expected an address")
+	}
+	host, port, err := net.SplitHostPort(test[1])
+	if err != nil {
+		host, port = "", test[1]
+	}
+	if p, err := strconv.ParseUint(port, 10, 16); err != nil || p == 0 {
+		return "", errors.Errorf("invalid TCP healthcheck address %q: must be a host:port pair or a port", test[1])
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// validateHealthcheckTest validates the test of network healthchecks, which
+// are run by the daemon.
+func validateHealthcheckTest(test []string) error {
+	if len(test) == 0 {
+		return nil
+	}
+	switch test[0] {
+	case "HTTP", "HTTP-GET":
+		_, _, err := parseHTTPProbe(test)
+		return err
+	case "TCP":
+		_, err := parseTCPProbe(test)
+		return err
+	}
+	return nil
+}
+
 // Update the container's Status.Health struct based on the latest probe's result.
 func handleProbeResult(d *Daemon, c *container.Container, result *types.HealthcheckResult, done chan struct{}) {
 	c.Lock()
@@ -178,20 +342,98 @@ func handleProbeResult(d *Daemon, c *container.Container, result *types.Healthch
 	current := h.Status()
 	if oldStatus != current {
 		d.LogContainerEvent(c, "health_status: "+current)
+
+		if current == types.Unhealthy {
+			switch action := c.Config.Healthcheck.OnUnhealthy; action {
+			case containertypes.UnhealthyActionRestart, containertypes.UnhealthyActionStop:
+				// c is locked, and stopping the container stops its health monitor
+				go d.handleUnhealthy(c, action)
+			}
+		}
 	}
 }
 
+// handleUnhealthy takes the action configured for the container, which has
+// become unhealthy.
+func (d *Daemon) handleUnhealthy(c *container.Container, action string) {
+	c.Lock()
+	if !c.Running || c.Paused || c.Restarting || c.RemovalInProgress || c.UnhealthyRestart {
+		c.Unlock()
+		return
+	}
+	if action == containertypes.UnhealthyActionRestart {
+		// The monitor restarts the container once it exited.
+		c.UnhealthyRestart = true
+	}
+	c.Unlock()
+
+	d.LogContainerEvent(c, "health_action: "+action)
+	logrus.WithField("container", c.ID).Infof("Container is unhealthy, %s action requested", action)
+
+	if action == containertypes.UnhealthyActionRestart {
+		if err := d.stopForRestart(c); err != nil {
+			logrus.WithError(err).WithField("container", c.ID).Errorf("Failed to %s unhealthy container", action)
+			// The container is not restarted by the monitor, so that later
+			// exits follow its restart policy, and it can be restarted the
+			// next time it becomes unhealthy.
+			c.Lock()
+			c.UnhealthyRestart = false
+			c.Unlock()
+		}
+		return
+	}
+	if err := d.containerStop(c, c.StopTimeout()); err != nil {
+		logrus.WithError(err).WithField("container", c.ID).Errorf("Failed to %s unhealthy container", action)
+	}
+}
+
+// stopForRestart sends the stop signal to the container, and kills it if it
+// does not exit within its stop timeout. Unlike containerStop, the restart
+// manager of the container is not canceled, so that it can be restarted.
+func (d *Daemon) stopForRestart(c *container.Container) error {
+	ctx := context.Background()
+	if seconds := c.StopTimeout(); seconds >= 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds)*time.Second)
+		defer cancel()
+	}
+	wait := c.Wait(ctx, container.WaitConditionNotRunning)
+
+	if err := d.kill(c, c.StopSignal()); err != nil && !errdefs.IsNotFound(err) {
+		return err
+	}
+	if status := <-wait; status.Err() != nil {
+		logrus.Infof("Unhealthy container %v failed to exit within %d seconds of signal %d - using the force", c.ID, c.StopTimeout(), c.StopSignal())
+		if err := d.kill(c, int(syscall.SIGKILL)); err != nil && !errdefs.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
 // Run the container's monitoring thread until notified via "stop".
 // There is never more than one monitor thread running per container at a time.
-func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe) {
+// Until the container is healthy for the first time, the startup probe is run,
+// if any, at the start interval.
+func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe, startupProbe probe) {
 	probeTimeout := timeoutWithDefault(c.Config.Healthcheck.Timeout, defaultProbeTimeout)
 	probeInterval := timeoutWithDefault(c.Config.Healthcheck.Interval, defaultProbeInterval)
+	startInterval := timeoutWithDefault(c.Config.Healthcheck.StartInterval, probeInterval)
+	if startupProbe == nil {
+		startupProbe = probe
+	}
 
 	intervalTimer := time.NewTimer(probeInterval)
 	defer intervalTimer.Stop()
 
 	for {
-		intervalTimer.Reset(probeInterval)
+		interval, p := probeInterval, probe
+		c.Lock()
+		if c.State.Health.Status() == types.Starting {
+			interval, p = startInterval, startupProbe
+		}
+		c.Unlock()
+		intervalTimer.Reset(interval)
 
 		select {
 		case <-stop:
@@ -204,7 +446,7 @@ func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe)
 			results := make(chan *types.HealthcheckResult, 1)
 			go func() {
 				healthChecksCounter.Inc()
-				result, err := probe.run(ctx, d, c)
+				result, err := p.run(ctx, d, c)
 				if err != nil {
 					healthChecksFailedCounter.Inc()
 					logrus.Warnf("Health check for container %s error: %v", c.ID, err)
@@ -254,18 +496,39 @@ func monitor(d *Daemon, c *container.Container, stop chan struct{}, probe probe)
 // Nil will be returned if no healthcheck was configured or NONE was set.
 func getProbe(c *container.Container) probe {
 	config := c.Config.Healthcheck
-	if config == nil || len(config.Test) == 0 {
+	if config == nil {
+		return nil
+	}
+	return newProbe(c, config.Test)
+}
+
+// Get the probe implementation for the container's startup healthcheck. Nil
+// will be returned if no startup test was configured.
+func getStartupProbe(c *container.Container) probe {
+	config := c.Config.Healthcheck
+	if config == nil {
+		return nil
+	}
+	return newProbe(c, config.StartupTest)
+}
+
+func newProbe(c *container.Container, test []string) probe {
+	if len(test) == 0 {
 		return nil
 	}
-	switch config.Test[0] {
+	switch test[0] {
 	case "CMD":
-		return &cmdProbe{shell: false}
+		return &cmdProbe{test: test, shell: false}
 	case "CMD-SHELL":
-		return &cmdProbe{shell: true}
+		return &cmdProbe{test: test, shell: true}
+	case "HTTP", "HTTP-GET":
+		return &httpProbe{test: test}
+	case "TCP":
+		return &tcpProbe{test: test}
 	case "NONE":
 		return nil
 	default:
-		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD') in container %s", config.Test[0], c.ID)
+		logrus.Warnf("Unknown healthcheck type '%s' (expected 'CMD', 'HTTP', 'HTTP-GET' or 'TCP') in container %s", test[0], c.ID)
 		return nil
 	}
 }
@@ -283,7 +546,7 @@ func (d *Daemon) updateHealthMonitor(c *container.Container) {
 	wantRunning := c.Running && !c.Paused && probe != nil
 	if wantRunning {
 		if stop := h.OpenMonitorChannel(); stop != nil {
-			go monitor(d, c, stop, probe)
+			go monitor(d, c, stop, probe, getStartupProbe(c))
 		}
 	} else {
 		h.CloseMonitorChannel()
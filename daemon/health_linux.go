@@ -0,0 +1,102 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+
+	"github.com/docker/docker/container"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netns"
+)
+
+// dialInContainer connects to the address from the network namespace of the
+// container. Host names are resolved by the daemon.
+func (daemon *Daemon) dialInContainer(ctx context.Context, c *container.Container, network, addr string) (net.Conn, error) {
+	nsPath, err := daemon.netNSPath(c)
+	if err != nil {
+		return nil, err
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		var conn net.Conn
+		conn, err = dialInNetNS(ctx, nsPath, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// netNSPath returns the path of the network namespace of the container.
+func (daemon *Daemon) netNSPath(c *container.Container) (string, error) {
+	if c.NetworkSettings != nil && c.NetworkSettings.SandboxKey != "" {
+		return c.NetworkSettings.SandboxKey, nil
+	}
+	// Containers joining the network namespace of another container have
+	// no sandbox of their own.
+	pid := c.State.GetPID()
+	if pid == 0 {
+		return "", errors.Errorf("container %s is not running", c.ID)
+	}
+	return fmt.Sprintf("/proc/%d/ns/net", pid), nil
+}
+
+// dialInNetNS connects to the address from the network namespace at nsPath.
+// The socket is created from a dedicated OS thread switched to the namespace,
+// which is terminated if it cannot be switched back.
+func dialInNetNS(ctx context.Context, nsPath, network, addr string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, 1)
+
+	go func() {
+		runtime.LockOSThread()
+
+		origNS, err := netns.Get()
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: errors.Wrap(err, "failed to get the current network namespace")}
+			return
+		}
+		defer origNS.Close()
+
+		targetNS, err := netns.GetFromPath(nsPath)
+		if err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: errors.Wrapf(err, "failed to get the network namespace %s", nsPath)}
+			return
+		}
+		defer targetNS.Close()
+
+		if err := netns.Set(targetNS); err != nil {
+			runtime.UnlockOSThread()
+			results <- result{err: errors.Wrapf(err, "failed to switch to the network namespace %s", nsPath)}
+			return
+		}
+
+		conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+
+		if err := netns.Set(origNS); err != nil {
+			// leave the thread locked, so that it is terminated
+			logrus.WithError(err).Error("Failed to switch back to the network namespace of the daemon")
+		} else {
+			runtime.UnlockOSThread()
+		}
+		results <- result{conn: conn, err: err}
+	}()
+
+	r := <-results
+	return r.conn, r.err
+}
@@ -0,0 +1,43 @@
+// +build linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/events"
+	"gotest.tools/assert"
+)
+
+type signalFailingMockContainerdClient struct {
+	MockContainerdClient
+}
+
+func (c *signalFailingMockContainerdClient) SignalProcess(ctx context.Context, containerID, processID string, signal int) error {
+	return errors.New("signal failed")
+}
+
+// TestHandleUnhealthyStopFailed verifies that the container is not marked to
+// be restarted as unhealthy when stopping it failed.
+func TestHandleUnhealthyStopFailed(t *testing.T) {
+	d := &Daemon{
+		EventsService: events.New(),
+		containerd:    &signalFailingMockContainerdClient{},
+	}
+	c := &container.Container{
+		ID:   "container_id",
+		Name: "container_name",
+		Config: &containertypes.Config{
+			Image:       "image_name",
+			Healthcheck: &containertypes.HealthConfig{OnUnhealthy: containertypes.UnhealthyActionRestart},
+		},
+		State: &container.State{Running: true},
+	}
+
+	d.handleUnhealthy(c, containertypes.UnhealthyActionRestart)
+	assert.Check(t, !c.UnhealthyRestart)
+}
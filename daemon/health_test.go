@@ -1,6 +1,7 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -152,3 +153,132 @@ func TestHealthStates(t *testing.T) {
 		t.Errorf("Expecting FailingStreak=0, but got %d\n", c.State.Health.FailingStreak)
 	}
 }
+
+func TestParseNetworkProbes(t *testing.T) {
+	method, u, err := parseHTTPProbe([]string{"HTTP-GET", "http://localhost:8080/health"})
+	if err != nil || method != "GET" || u.String() != "http://localhost:8080/health" {
+		t.Fatalf("unexpected HTTP-GET probe: %s %v, %v", method, u, err)
+	}
+	method, u, err = parseHTTPProbe([]string{"HTTP", "head", "https://127.0.0.1/"})
+	if err != nil || method != "HEAD" || u.String() != "https://127.0.0.1/" {
+		t.Fatalf("unexpected HTTP probe: %s %v, %v", method, u, err)
+	}
+
+	for test, expected := range map[string]string{
+		"localhost:5432": "localhost:5432",
+		"10.0.0.1:53":    "10.0.0.1:53",
+		":6379":          "localhost:6379",
+		"6379":           "localhost:6379",
+	} {
+		addr, err := parseTCPProbe([]string{"TCP", test})
+		if err != nil || addr != expected {
+			t.Fatalf("expected TCP probe address %s for %s, got %s, %v", expected, test, addr, err)
+		}
+	}
+
+	for _, test := range [][]string{
+		{"HTTP-GET"},
+		{"HTTP-GET", "localhost:8080/health"},
+		{"HTTP-GET", "ftp://localhost/"},
+		{"HTTP", "http://localhost/"},
+		{"TCP"},
+		{"TCP", "localhost"},
+		{"TCP", "localhost:0"},
+		{"TCP", "localhost:5432", "extra"},
+	} {
+		if err := validateHealthcheckTest(test); err == nil {
+			t.Fatalf("expected an error for %v", test)
+		}
+	}
+	if err := validateHealthcheckTest([]string{"CMD-SHELL", "exit 0"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type fakeProbe struct {
+	runs chan string
+	name string
+}
+
+func (p *fakeProbe) run(context.Context, *Daemon, *container.Container) (*types.HealthcheckResult, error) {
+	p.runs <- p.name
+	return &types.HealthcheckResult{End: time.Now(), ExitCode: exitStatusHealthy}, nil
+}
+
+func TestStartupProbe(t *testing.T) {
+	c := &container.Container{
+		ID:   "container_id",
+		Name: "container_name",
+		Config: &containertypes.Config{
+			Image: "image_name",
+			Healthcheck: &containertypes.HealthConfig{
+				Interval:      100 * time.Millisecond,
+				StartInterval: time.Millisecond,
+			},
+		},
+	}
+	reset(c)
+
+	store, err := container.NewViewDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	daemon := &Daemon{
+		EventsService:     events.New(),
+		containersReplica: store,
+	}
+
+	runs := make(chan string, 10)
+	stop := make(chan struct{})
+	go monitor(daemon, c, stop, &fakeProbe{runs, "probe"}, &fakeProbe{runs, "startup"})
+	defer close(stop)
+
+	// The startup probe runs at the start interval until the container is
+	// healthy, then the probe runs at the interval.
+	for _, expected := range []string{"startup", "probe"} {
+		start := time.Now()
+		select {
+		case name := <-runs:
+			if name != expected {
+				t.Fatalf("expected the %s probe to run, got %s", expected, name)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout waiting for the %s probe", expected)
+		}
+		if expected == "probe" && time.Since(start) < 50*time.Millisecond {
+			t.Fatalf("expected the probe to run at the interval, ran after %s", time.Since(start))
+		}
+	}
+	if status := c.State.Health.Status(); status != types.Healthy {
+		t.Fatalf("expected healthy, got %s", status)
+	}
+}
+
+func TestValidateUnhealthyAction(t *testing.T) {
+	for _, action := range []string{"", "none", "restart", "stop"} {
+		if err := validateHealthCheck(&containertypes.HealthConfig{OnUnhealthy: action}); err != nil {
+			t.Fatalf("unexpected error for %q: %v", action, err)
+		}
+	}
+	if err := validateHealthCheck(&containertypes.HealthConfig{OnUnhealthy: "kill"}); err == nil {
+		t.Fatal("expected an error for an invalid action")
+	}
+}
+
+func TestValidateStartupHealthcheck(t *testing.T) {
+	if err := validateHealthCheck(&containertypes.HealthConfig{
+		StartupTest:   []string{"HTTP-GET", "http://localhost/ready"},
+		StartInterval: time.Second,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, config := range []*containertypes.HealthConfig{
+		{StartupTest: []string{"NONE"}},
+		{StartupTest: []string{"TCP"}},
+		{StartInterval: time.Microsecond},
+	} {
+		if err := validateHealthCheck(config); err == nil {
+			t.Fatalf("expected an error for %+v", config)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"context"
+	"net"
+	"runtime"
+
+	"github.com/docker/docker/container"
+	"github.com/pkg/errors"
+)
+
+func (daemon *Daemon) dialInContainer(ctx context.Context, c *container.Container, network, addr string) (net.Conn, error) {
+	return nil, errors.Errorf("HTTP and TCP healthchecks are not supported on %s", runtime.GOOS)
+}
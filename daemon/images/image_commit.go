@@ -1,15 +1,21 @@
 package images // import "github.com/docker/docker/daemon/images"
 
 import (
+	"archive/tar"
 	"encoding/json"
 	"io"
+	"path"
+	"strings"
 
 	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/fileutils"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/system"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // CommitImage creates a new image from a commit config
@@ -18,10 +24,17 @@ func (i *ImageService) CommitImage(c backend.CommitConfig) (image.ID, error) {
 	if !ok {
 		return "", system.ErrNotSupportedOperatingSystem
 	}
+	excluded, err := newExcludeMatcher(c.Exclude)
+	if err != nil {
+		return "", errdefs.InvalidParameter(err)
+	}
 	rwTar, err := exportContainerRw(layerStore, c.ContainerID, c.ContainerMountLabel)
 	if err != nil {
 		return "", err
 	}
+	if len(c.Exclude) > 0 {
+		rwTar = excludeFromTar(rwTar, excluded)
+	}
 	defer func() {
 		if rwTar != nil {
 			rwTar.Close()
@@ -63,14 +76,103 @@ func (i *ImageService) CommitImage(c backend.CommitConfig) (image.ID, error) {
 		return "", err
 	}
 
-	if c.ParentImageID != "" {
+	if c.Squash {
+		if id, err = i.squashCommittedImage(id); err != nil {
+			return "", err
+		}
+	} else if c.ParentImageID != "" {
 		if err := i.imageStore.SetParent(id, image.ID(c.ParentImageID)); err != nil {
 			return "", err
 		}
 	}
+
+	// The annotations are kept out of the image config, so that they do not
+	// change the ID of the image, and are not pushed with it.
+	if len(c.Annotations) > 0 {
+		if err := i.imageStore.SetAnnotations(id, c.Annotations); err != nil {
+			return "", err
+		}
+	}
 	return id, nil
 }
 
+// squashCommittedImage squashes all the layers of the committed image,
+// including the layers of its parent image, into a single layer, and deletes
+// the committed image. The squashed image has no parent, as it does not
+// share any layer with it.
+func (i *ImageService) squashCommittedImage(id image.ID) (image.ID, error) {
+	squashed, err := i.SquashImage(id.String(), "")
+	if _, err := i.imageStore.Delete(id); err != nil {
+		logrus.WithError(err).WithField("image", id).Warn("Failed to delete the image committed before squashing it")
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "error squashing the committed image")
+	}
+	return image.ID(squashed), nil
+}
+
+// newExcludeMatcher returns a matcher of the paths of a container to exclude
+// from a commit, where the patterns are absolute paths in the container
+// which may contain "**" to match any number of directories.
+func newExcludeMatcher(patterns []string) (*fileutils.PatternMatcher, error) {
+	relative := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		negated := strings.HasPrefix(p, "!")
+		p = strings.TrimLeft(strings.TrimPrefix(p, "!"), "/")
+		if negated {
+			p = "!" + p
+		}
+		relative = append(relative, p)
+	}
+	return fileutils.NewPatternMatcher(relative)
+}
+
+// excludeFromTar returns a tar stream with the entries of the tar stream
+// whose paths do not match the excluded patterns. The hard links to
+// excluded files are excluded as well.
+func excludeFromTar(in io.ReadCloser, excluded *fileutils.PatternMatcher) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		tr := tar.NewReader(in)
+		tw := tar.NewWriter(pw)
+		err := func() error {
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					return tw.Close()
+				}
+				if err != nil {
+					return err
+				}
+				skip, err := excluded.Matches(path.Clean(hdr.Name))
+				if err != nil {
+					return err
+				}
+				if !skip && hdr.Typeflag == tar.TypeLink {
+					if skip, err = excluded.Matches(path.Clean(hdr.Linkname)); err != nil {
+						return err
+					}
+				}
+				if skip {
+					continue
+				}
+				if err := tw.WriteHeader(hdr); err != nil {
+					return err
+				}
+				if _, err := io.Copy(tw, tr); err != nil {
+					return err
+				}
+			}
+		}()
+		pw.CloseWithError(err)
+	}()
+	return ioutils.NewReadCloserWrapper(pr, func() error {
+		pr.Close()
+		return in.Close()
+	})
+}
+
 func exportContainerRw(layerStore layer.Store, id, mountLabel string) (arch io.ReadCloser, err error) {
 	rwlayer, err := layerStore.GetRWLayer(id)
 	if err != nil {
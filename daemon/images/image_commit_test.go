@@ -0,0 +1,65 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestExcludeFromTar(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range []*tar.Header{
+		{Name: "tmp/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "tmp/junk", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+		{Name: "tmp/sub/junk", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+		{Name: "var/cache/apt/pkgcache.bin", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+		{Name: "var/cache/.wh.old", Typeflag: tar.TypeReg, Mode: 0600},
+		{Name: "etc/app.conf", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+		{Name: "etc/junk-link", Typeflag: tar.TypeLink, Linkname: "tmp/junk"},
+		{Name: "etc/keep-link", Typeflag: tar.TypeLink, Linkname: "etc/app.conf"},
+		{Name: "tmp/keep", Typeflag: tar.TypeReg, Mode: 0644, Size: 4},
+	} {
+		assert.NilError(t, tw.WriteHeader(hdr))
+		if hdr.Size > 0 {
+			_, err := tw.Write([]byte("data"))
+			assert.NilError(t, err)
+		}
+	}
+	assert.NilError(t, tw.Close())
+
+	excluded, err := newExcludeMatcher([]string{"/tmp/**", "/var/cache/**", "!/tmp/keep"})
+	assert.NilError(t, err)
+	out := excludeFromTar(ioutil.NopCloser(&buf), excluded)
+	defer out.Close()
+
+	var names []string
+	tr := tar.NewReader(out)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		names = append(names, hdr.Name)
+		if hdr.Size > 0 {
+			content, err := ioutil.ReadAll(tr)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(string(content), "data"))
+		}
+	}
+	assert.Check(t, is.DeepEqual(names, []string{"tmp/", "etc/app.conf", "etc/keep-link", "tmp/keep"}))
+}
+
+func TestNewExcludeMatcherInvalid(t *testing.T) {
+	_, err := newExcludeMatcher([]string{"/tmp/[-]"})
+	assert.Check(t, is.ErrorContains(err, "syntax error in pattern"))
+
+	_, err = newExcludeMatcher([]string{"!"})
+	assert.Check(t, is.ErrorContains(err, "illegal exclusion pattern"))
+}
@@ -63,6 +63,11 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 		return nil, err
 	}
 
+	annotations, err := i.imageStore.GetAnnotations(img.ID())
+	if err != nil {
+		return nil, err
+	}
+
 	imageInspect := &types.ImageInspect{
 		ID:              img.ID().String(),
 		RepoTags:        repoTags,
@@ -78,6 +83,7 @@ func (i *ImageService) LookupImage(name string) (*types.ImageInspect, error) {
 		Architecture:    img.Architecture,
 		Os:              img.OperatingSystem(),
 		OsVersion:       img.OSVersion,
+		Annotations:     annotations,
 		Size:            size,
 		VirtualSize:     size, // TODO: field unused, deprecate
 		RootFS:          rootFSToAPIType(img.RootFS),
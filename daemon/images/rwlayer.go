@@ -0,0 +1,34 @@
+package images // import "github.com/docker/docker/daemon/images"
+
+import (
+	"io"
+
+	"github.com/docker/docker/pkg/system"
+)
+
+// ExportContainerRWLayer returns a tar stream of the changes of the RW layer
+// of a container to its image, where the deleted files are whiteout files
+// as defined by the OCI image layer specification.
+func (i *ImageService) ExportContainerRWLayer(containerID, os, mountLabel string) (io.ReadCloser, error) {
+	layerStore, ok := i.layerStores[os]
+	if !ok {
+		return nil, system.ErrNotSupportedOperatingSystem
+	}
+	return exportContainerRw(layerStore, containerID, mountLabel)
+}
+
+// ImportContainerRWLayer applies a tar stream of changes, such as one exported
+// from the RW layer of a container created from the same image, to the RW
+// layer of a container, and returns the size of the applied changes.
+func (i *ImageService) ImportContainerRWLayer(containerID, os string, changes io.Reader) (int64, error) {
+	layerStore, ok := i.layerStores[os]
+	if !ok {
+		return 0, system.ErrNotSupportedOperatingSystem
+	}
+	rwlayer, err := layerStore.GetRWLayer(containerID)
+	if err != nil {
+		return 0, err
+	}
+	defer layerStore.ReleaseRWLayer(rwlayer)
+	return rwlayer.ApplyDiff(changes)
+}
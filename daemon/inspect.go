@@ -159,6 +159,8 @@ func (daemon *Daemon) getInspectData(container *container.Container) (*types.Con
 		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
 		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
 		Health:     containerHealth,
+
+		RestartDelay: container.State.RestartDelay,
 	}
 
 	contJSONBase := &types.ContainerJSONBase{
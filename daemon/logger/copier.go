@@ -30,6 +30,7 @@ type Copier struct {
 	copyJobs  sync.WaitGroup
 	closeOnce sync.Once
 	closed    chan struct{}
+	multiline *MultilineConfig
 }
 
 // NewCopier creates a new Copier
@@ -41,6 +42,14 @@ func NewCopier(srcs map[string]io.Reader, dst Logger) *Copier {
 	}
 }
 
+// NewMultilineCopier creates a new Copier which merges the lines of multi-line
+// log entries into a single message.
+func NewMultilineCopier(srcs map[string]io.Reader, dst Logger, cfg MultilineConfig) *Copier {
+	c := NewCopier(srcs, dst)
+	c.multiline = &cfg
+	return c
+}
+
 // Run starts logs copying
 func (c *Copier) Run() {
 	for src, w := range c.srcs {
@@ -58,6 +67,13 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	}
 	buf := make([]byte, bufSize)
 
+	log := c.logMessage
+	if c.multiline != nil {
+		aggregator := newMultilineAggregator(*c.multiline, bufSize, c.logMessage)
+		defer aggregator.close()
+		log = aggregator.add
+	}
+
 	n := 0
 	eof := false
 	var partialid string
@@ -120,10 +136,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 						msg.Timestamp = partialTS
 					}
 
-					if logErr := c.dst.Log(msg); logErr != nil {
-						logWritesFailedCount.Inc(1)
-						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
-					}
+					log(msg)
 				}
 				p += q + 1
 			}
@@ -153,10 +166,7 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 					ordinal++
 					hasMorePartial = true
 
-					if logErr := c.dst.Log(msg); logErr != nil {
-						logWritesFailedCount.Inc(1)
-						logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
-					}
+					log(msg)
 					p = 0
 					n = 0
 				}
@@ -173,6 +183,13 @@ func (c *Copier) copySrc(name string, src io.Reader) {
 	}
 }
 
+func (c *Copier) logMessage(msg *Message) {
+	if logErr := c.dst.Log(msg); logErr != nil {
+		logWritesFailedCount.Inc(1)
+		logrus.Errorf("Failed to log msg %q for logger %s: %s", msg.Line, c.dst.Name(), logErr)
+	}
+}
+
 // Wait waits until all copying is done
 func (c *Copier) Wait() {
 	c.copyJobs.Wait()
@@ -117,8 +117,13 @@ func GetLogDriver(name string) (Creator, error) {
 }
 
 var builtInLogOpts = map[string]bool{
-	"mode":            true,
-	"max-buffer-size": true,
+	"mode":              true,
+	"max-buffer-size":   true,
+	rateLimitOpt:        true,
+	rateLimitBurstOpt:   true,
+	rateLimitPolicyOpt:  true,
+	"multiline-pattern": true,
+	"multiline-timeout": true,
 }
 
 // ValidateLogOpts checks the options for the given log driver. The
@@ -143,6 +148,14 @@ func ValidateLogOpts(name string, cfg map[string]string) error {
 		}
 	}
 
+	if _, err := ParseRateLimitConfig(cfg); err != nil {
+		return err
+	}
+
+	if _, err := ParseMultilineConfig(cfg); err != nil {
+		return err
+	}
+
 	if !factory.driverRegistered(name) {
 		return fmt.Errorf("logger: no log driver named '%s' is registered", name)
 	}
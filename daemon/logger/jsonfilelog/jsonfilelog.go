@@ -65,15 +65,15 @@ func New(info logger.Info) (logger.Logger, error) {
 		}
 	}
 
-	var compress bool
+	compression := loggerutils.CompressionNone
 	if compressString, ok := info.Config["compress"]; ok {
 		var err error
-		compress, err = strconv.ParseBool(compressString)
+		compression, err = loggerutils.ParseCompression(compressString)
 		if err != nil {
 			return nil, err
 		}
-		if compress && (maxFiles == 1 || capval == -1) {
-			return nil, fmt.Errorf("compress cannot be true when max-file is less than 2 or max-size is not set")
+		if compression != loggerutils.CompressionNone && (maxFiles == 1 || capval == -1) {
+			return nil, fmt.Errorf("compress cannot be enabled when max-file is less than 2 or max-size is not set")
 		}
 	}
 
@@ -110,7 +110,7 @@ func New(info logger.Info) (logger.Logger, error) {
 		return b, nil
 	}
 
-	writer, err := loggerutils.NewLogFile(info.LogPath, capval, maxFiles, compress, marshalFunc, decodeFunc, 0640, getTailReader)
+	writer, err := loggerutils.NewLogFile(info.LogPath, capval, maxFiles, compression, marshalFunc, decodeFunc, 0640, getTailReader)
 	if err != nil {
 		return nil, err
 	}
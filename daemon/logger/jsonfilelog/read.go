@@ -24,6 +24,12 @@ func (l *JSONFileLogger) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
 	return logWatcher
 }
 
+// SupportsReadFilters implements the logger's FilteringLogReader interface,
+// as the log file applies the filters of the read config.
+func (l *JSONFileLogger) SupportsReadFilters() bool {
+	return true
+}
+
 func (l *JSONFileLogger) readLogs(watcher *logger.LogWatcher, config logger.ReadConfig) {
 	defer close(watcher.Msg)
 
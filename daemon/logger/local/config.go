@@ -1,21 +1,41 @@
 package local
 
 import (
+	"github.com/docker/docker/daemon/logger/loggerutils"
 	"github.com/pkg/errors"
 )
 
 // CreateConfig is used to configure new instances of driver
 type CreateConfig struct {
+	// DisableCompression disables the compression of rotated log files,
+	// whatever the value of Compression.
+	//
+	// Deprecated: set Compression to loggerutils.CompressionNone instead.
 	DisableCompression bool
-	MaxFileSize        int64
-	MaxFileCount       int
+	// Compression is the codec used to compress rotated log files. The
+	// default, gzip, is used if empty.
+	Compression  loggerutils.Compression
+	MaxFileSize  int64
+	MaxFileCount int
 }
 
 func newDefaultConfig() *CreateConfig {
 	return &CreateConfig{
-		MaxFileSize:        defaultMaxFileSize,
-		MaxFileCount:       defaultMaxFileCount,
-		DisableCompression: !defaultCompressLogs,
+		MaxFileSize:  defaultMaxFileSize,
+		MaxFileCount: defaultMaxFileCount,
+		Compression:  defaultCompression,
+	}
+}
+
+// compression returns the codec used to compress rotated log files.
+func (cfg *CreateConfig) compression() loggerutils.Compression {
+	switch {
+	case cfg.DisableCompression:
+		return loggerutils.CompressionNone
+	case cfg.Compression == "":
+		return defaultCompression
+	default:
+		return cfg.Compression
 	}
 }
 
@@ -27,7 +47,7 @@ func validateConfig(cfg *CreateConfig) error {
 		return errors.New("max file count cannot be less than 0")
 	}
 
-	if !cfg.DisableCompression {
+	if cfg.compression() != loggerutils.CompressionNone {
 		if cfg.MaxFileCount <= 1 {
 			return errors.New("compression cannot be enabled when max file count is 1")
 		}
@@ -27,7 +27,7 @@ const (
 
 	defaultMaxFileSize  int64 = 20 * 1024 * 1024
 	defaultMaxFileCount       = 5
-	defaultCompressLogs       = true
+	defaultCompression        = loggerutils.CompressionGzip
 )
 
 // LogOptKeys are the keys names used for log opts passed in to initialize the driver.
@@ -88,11 +88,11 @@ func New(info logger.Info) (logger.Logger, error) {
 	}
 
 	if userCompress, ok := info.Config["compress"]; ok {
-		compressLogs, err := strconv.ParseBool(userCompress)
+		var err error
+		cfg.Compression, err = loggerutils.ParseCompression(userCompress)
 		if err != nil {
 			return nil, errdefs.InvalidParameter(errors.Wrap(err, "error reading compress log option"))
 		}
-		cfg.DisableCompression = !compressLogs
 	}
 	return newDriver(info.LogPath, cfg)
 }
@@ -140,7 +140,7 @@ func newDriver(logPath string, cfg *CreateConfig) (logger.Logger, error) {
 		return nil, errdefs.InvalidParameter(err)
 	}
 
-	lf, err := loggerutils.NewLogFile(logPath, cfg.MaxFileSize, cfg.MaxFileCount, !cfg.DisableCompression, makeMarshaller(), decodeFunc, 0640, getTailReader)
+	lf, err := loggerutils.NewLogFile(logPath, cfg.MaxFileSize, cfg.MaxFileCount, cfg.compression(), makeMarshaller(), decodeFunc, 0640, getTailReader)
 	if err != nil {
 		return nil, err
 	}
@@ -19,6 +19,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/api/types/plugins/logdriver"
 	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
 	protoio "github.com/gogo/protobuf/io"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
@@ -83,6 +84,16 @@ func TestWriteLog(t *testing.T) {
 	assert.Check(t, is.DeepEqual(testProto, proto), "expected:\n%+v\ngot:\n%+v", testProto, proto)
 }
 
+func TestCreateConfigCompression(t *testing.T) {
+	assert.Check(t, is.Equal(newDefaultConfig().compression(), loggerutils.CompressionGzip))
+	assert.Check(t, is.Equal((&CreateConfig{}).compression(), loggerutils.CompressionGzip))
+	assert.Check(t, is.Equal((&CreateConfig{Compression: loggerutils.CompressionZstd}).compression(), loggerutils.CompressionZstd))
+	assert.Check(t, is.Equal((&CreateConfig{DisableCompression: true, Compression: loggerutils.CompressionZstd}).compression(), loggerutils.CompressionNone))
+
+	assert.Check(t, validateConfig(&CreateConfig{MaxFileCount: 1}) != nil)
+	assert.Check(t, validateConfig(&CreateConfig{MaxFileCount: 1, DisableCompression: true}))
+}
+
 func TestReadLog(t *testing.T) {
 	t.Parallel()
 
@@ -21,6 +21,12 @@ func (d *driver) ReadLogs(config logger.ReadConfig) *logger.LogWatcher {
 	return logWatcher
 }
 
+// SupportsReadFilters implements the logger's FilteringLogReader interface,
+// as the log file applies the filters of the read config.
+func (d *driver) SupportsReadFilters() bool {
+	return true
+}
+
 func (d *driver) readLogs(watcher *logger.LogWatcher, config logger.ReadConfig) {
 	defer close(watcher.Msg)
 
@@ -8,6 +8,7 @@
 package logger // import "github.com/docker/docker/daemon/logger"
 
 import (
+	"regexp"
 	"sync"
 	"time"
 
@@ -91,6 +92,18 @@ type ReadConfig struct {
 	Until  time.Time
 	Tail   int
 	Follow bool
+
+	// Sources restricts the messages read to those with one of the given
+	// sources ("stdout" or "stderr"). Messages from all sources are read
+	// if empty.
+	Sources []string `json:"-"`
+	// Attrs restricts the messages read to those having all of the given
+	// attributes. Only applied by readers implementing FilteringLogReader.
+	Attrs map[string]string `json:"-"`
+	// Grep restricts the messages read to those with a line matching the
+	// regular expression. Only applied by readers implementing
+	// FilteringLogReader.
+	Grep *regexp.Regexp `json:"-"`
 }
 
 // LogReader is the interface for reading log messages for loggers that support reading.
@@ -99,6 +112,22 @@ type LogReader interface {
 	ReadLogs(ReadConfig) *LogWatcher
 }
 
+// FilteringLogReader is implemented by log readers which may apply the Attrs
+// and Grep filters of the ReadConfig.
+type FilteringLogReader interface {
+	LogReader
+	// SupportsReadFilters returns whether the Attrs and Grep filters of the
+	// ReadConfig are applied when reading logs.
+	SupportsReadFilters() bool
+}
+
+// SupportsReadFilters returns whether the reader applies the Attrs and Grep
+// filters of the ReadConfig.
+func SupportsReadFilters(r LogReader) bool {
+	fr, ok := r.(FilteringLogReader)
+	return ok && fr.SupportsReadFilters()
+}
+
 // LogWatcher is used when consuming logs read from the LogReader interface.
 type LogWatcher struct {
 	// For sending log messages to a reader.
@@ -0,0 +1,241 @@
+package loggerutils // import "github.com/docker/docker/daemon/logger/loggerutils"
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/docker/docker/pkg/pools"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Compression is the codec used to compress rotated log files.
+type Compression string
+
+const (
+	// CompressionNone leaves rotated log files uncompressed.
+	CompressionNone Compression = "none"
+	// CompressionGzip compresses rotated log files with gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses rotated log files with zstd.
+	CompressionZstd Compression = "zstd"
+)
+
+// metaFileSuffix is the suffix of the sidecar file holding the metadata of a
+// compressed log file.
+const metaFileSuffix = ".meta"
+
+// ParseCompression parses the value of the "compress" log option. Boolean
+// values are accepted for backward compatibility, "true" selecting gzip.
+func ParseCompression(s string) (Compression, error) {
+	switch c := Compression(s); c {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return c, nil
+	}
+	compress, err := strconv.ParseBool(s)
+	if err != nil {
+		return "", errors.Errorf("invalid value for compress: %q: must be one of gzip, zstd, or none", s)
+	}
+	if compress {
+		return CompressionGzip, nil
+	}
+	return CompressionNone, nil
+}
+
+// rotateFileMetadata is the metadata of a compressed log file, stored in a
+// sidecar file next to it.
+type rotateFileMetadata struct {
+	LastTime time.Time `json:"lastTime,omitempty"`
+}
+
+type codec struct {
+	compression Compression
+	ext         string
+	newWriter   func(w io.Writer, meta []byte) (io.WriteCloser, error)
+	newReader   func(r io.Reader) (io.ReadCloser, error)
+}
+
+// codecs are the supported compression codecs, in the order in which
+// rotated files are looked up when reading.
+var codecs = []codec{
+	{
+		compression: CompressionGzip,
+		ext:         ".gz",
+		newWriter: func(w io.Writer, meta []byte) (io.WriteCloser, error) {
+			zw := gzip.NewWriter(w)
+			// The metadata is also kept in the gzip header, for daemons
+			// which do not know about the sidecar file.
+			zw.Header.Extra = meta
+			return zw, nil
+		},
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+	},
+	{
+		compression: CompressionZstd,
+		ext:         ".zst",
+		newWriter: func(w io.Writer, _ []byte) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		},
+		newReader: func(r io.Reader) (io.ReadCloser, error) {
+			zr, err := zstd.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return zr.IOReadCloser(), nil
+		},
+	},
+}
+
+func getCodec(compression Compression) (codec, bool) {
+	for _, c := range codecs {
+		if c.compression == compression {
+			return c, true
+		}
+	}
+	return codec{}, false
+}
+
+// rotatedFileSuffixes returns the suffixes of all the files which may make up
+// a rotated log file, whatever codec it was compressed with.
+func rotatedFileSuffixes() []string {
+	suffixes := []string{"", metaFileSuffix}
+	for _, c := range codecs {
+		suffixes = append(suffixes, c.ext)
+	}
+	return suffixes
+}
+
+func compressFile(fileName string, compression Compression, lastTimestamp time.Time) {
+	c, ok := getCodec(compression)
+	if !ok {
+		return
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		logrus.Errorf("Failed to open log file: %v", err)
+		return
+	}
+	defer func() {
+		file.Close()
+		err := os.Remove(fileName)
+		if err != nil {
+			logrus.Errorf("Failed to remove source log file: %v", err)
+		}
+	}()
+
+	outFileName := fileName + c.ext
+	outFile, err := os.OpenFile(outFileName, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0640)
+	if err != nil {
+		logrus.Errorf("Failed to open or create %s log file: %v", compression, err)
+		return
+	}
+	defer func() {
+		outFile.Close()
+		if err != nil {
+			os.Remove(outFileName)
+		}
+	}()
+
+	// Store the last log entry timestamp, so that readers can skip this
+	// file without decompressing it.
+	meta, mErr := json.Marshal(&rotateFileMetadata{LastTime: lastTimestamp})
+	if mErr != nil {
+		// Here log the error only and don't return since this is just an optimization.
+		logrus.Warningf("Failed to marshal log file metadata as JSON: %v", mErr)
+		meta = nil
+	}
+
+	compressWriter, err := c.newWriter(outFile, meta)
+	if err != nil {
+		logrus.WithError(err).WithField("module", "container.logs").WithField("file", fileName).Error("Error compressing log file")
+		return
+	}
+
+	_, err = pools.Copy(compressWriter, file)
+	if err == nil {
+		err = compressWriter.Close()
+	} else {
+		compressWriter.Close()
+	}
+	if err != nil {
+		logrus.WithError(err).WithField("module", "container.logs").WithField("file", fileName).Error("Error compressing log file")
+		return
+	}
+
+	if meta != nil {
+		if err := ioutils.AtomicWriteFile(fileName+metaFileSuffix, meta, 0640); err != nil {
+			logrus.WithError(err).WithField("file", fileName).Warn("Failed to write log file metadata")
+		}
+	}
+}
+
+// readRotateFileMetadata reads the metadata of a compressed log file from its
+// sidecar file.
+func readRotateFileMetadata(fileName string) (*rotateFileMetadata, error) {
+	b, err := ioutil.ReadFile(fileName + metaFileSuffix)
+	if err != nil {
+		return nil, err
+	}
+	meta := &rotateFileMetadata{}
+	if err := json.Unmarshal(b, meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// decompressfile decompresses fileName, the rotated log file baseName
+// compressed with c, to destFileName. It returns a nil file if the last log
+// entry in the compressed file is older than since.
+func decompressfile(baseName, fileName, destFileName string, c codec, since time.Time) (*os.File, error) {
+	cf, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening file for decompression")
+	}
+	defer cf.Close()
+
+	rc, err := c.newReader(cf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error making %s reader for compressed log file", c.compression)
+	}
+	defer rc.Close()
+
+	meta, err := readRotateFileMetadata(baseName)
+	if err != nil {
+		// Files compressed before the sidecar file was introduced have the
+		// metadata in the gzip header only.
+		if zr, ok := rc.(*gzip.Reader); ok {
+			meta = &rotateFileMetadata{}
+			err = json.Unmarshal(zr.Header.Extra, meta)
+		}
+	}
+	if err == nil && meta.LastTime.Before(since) {
+		return nil, nil
+	}
+
+	rs, err := os.OpenFile(destFileName, os.O_CREATE|os.O_RDWR, 0640)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating file for copying decompressed log stream")
+	}
+
+	_, err = pools.Copy(rs, rc)
+	if err != nil {
+		rs.Close()
+		rErr := os.Remove(rs.Name())
+		if rErr != nil && !os.IsNotExist(rErr) {
+			logrus.Errorf("Failed to remove logfile: %v", rErr)
+		}
+		return nil, errors.Wrap(err, "error while copying decompressed log stream to file")
+	}
+
+	return rs, nil
+}
@@ -1,9 +1,7 @@
 package loggerutils // import "github.com/docker/docker/daemon/logger/loggerutils"
 
 import (
-	"compress/gzip"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -15,7 +13,6 @@ import (
 
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/pkg/filenotify"
-	"github.com/docker/docker/pkg/pools"
 	"github.com/docker/docker/pkg/pubsub"
 	"github.com/fsnotify/fsnotify"
 	"github.com/pkg/errors"
@@ -24,11 +21,6 @@ import (
 
 const tmpLogfileSuffix = ".tmp"
 
-// rotateFileMetadata is a metadata of the gzip header of the compressed log file
-type rotateFileMetadata struct {
-	LastTime time.Time `json:"lastTime,omitempty"`
-}
-
 // refCounter is a counter of logfile being referenced
 type refCounter struct {
 	mu      sync.Mutex
@@ -80,13 +72,13 @@ type LogFile struct {
 	mu              sync.RWMutex // protects the logfile access
 	f               *os.File     // store for closing
 	closed          bool
-	rotateMu        sync.Mutex // blocks the next rotation until the current rotation is completed
-	capacity        int64      // maximum size of each file
-	currentSize     int64      // current size of the latest file
-	maxFiles        int        // maximum number of files
-	compress        bool       // whether old versions of log files are compressed
-	lastTimestamp   time.Time  // timestamp of the last log
-	filesRefCounter refCounter // keep reference-counted of decompressed files
+	rotateMu        sync.Mutex  // blocks the next rotation until the current rotation is completed
+	capacity        int64       // maximum size of each file
+	currentSize     int64       // current size of the latest file
+	maxFiles        int         // maximum number of files
+	compression     Compression // codec used to compress old versions of log files
+	lastTimestamp   time.Time   // timestamp of the last log
+	filesRefCounter refCounter  // keep reference-counted of decompressed files
 	notifyRotate    *pubsub.Publisher
 	marshal         logger.MarshalFunc
 	createDecoder   makeDecoderFunc
@@ -110,7 +102,7 @@ type SizeReaderAt interface {
 type GetTailReaderFunc func(ctx context.Context, f SizeReaderAt, nLogLines int) (rdr io.Reader, nLines int, err error)
 
 // NewLogFile creates new LogFile
-func NewLogFile(logPath string, capacity int64, maxFiles int, compress bool, marshaller logger.MarshalFunc, decodeFunc makeDecoderFunc, perms os.FileMode, getTailReader GetTailReaderFunc) (*LogFile, error) {
+func NewLogFile(logPath string, capacity int64, maxFiles int, compression Compression, marshaller logger.MarshalFunc, decodeFunc makeDecoderFunc, perms os.FileMode, getTailReader GetTailReaderFunc) (*LogFile, error) {
 	log, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, perms)
 	if err != nil {
 		return nil, err
@@ -126,7 +118,7 @@ func NewLogFile(logPath string, capacity int64, maxFiles int, compress bool, mar
 		capacity:        capacity,
 		currentSize:     size,
 		maxFiles:        maxFiles,
-		compress:        compress,
+		compression:     compression,
 		filesRefCounter: refCounter{counter: make(map[string]int)},
 		notifyRotate:    pubsub.NewPublisher(0, 1),
 		marshal:         marshaller,
@@ -178,7 +170,7 @@ func (w *LogFile) checkCapacityAndRotate() error {
 			w.rotateMu.Unlock()
 			return errors.Wrap(err, "error closing file")
 		}
-		if err := rotate(fname, w.maxFiles, w.compress); err != nil {
+		if err := rotate(fname, w.maxFiles); err != nil {
 			w.rotateMu.Unlock()
 			return err
 		}
@@ -191,13 +183,13 @@ func (w *LogFile) checkCapacityAndRotate() error {
 		w.currentSize = 0
 		w.notifyRotate.Publish(struct{}{})
 
-		if w.maxFiles <= 1 || !w.compress {
+		if w.maxFiles <= 1 || w.compression == CompressionNone {
 			w.rotateMu.Unlock()
 			return nil
 		}
 
 		go func() {
-			compressFile(fname+".1", w.lastTimestamp)
+			compressFile(fname+".1", w.compression, w.lastTimestamp)
 			w.rotateMu.Unlock()
 		}()
 	}
@@ -205,27 +197,30 @@ func (w *LogFile) checkCapacityAndRotate() error {
 	return nil
 }
 
-func rotate(name string, maxFiles int, compress bool) error {
+func rotate(name string, maxFiles int) error {
 	if maxFiles < 2 {
 		return nil
 	}
 
-	var extension string
-	if compress {
-		extension = ".gz"
-	}
+	// Rotated files may have been compressed with any codec, depending on
+	// the configuration at the time they were rotated.
+	suffixes := rotatedFileSuffixes()
 
-	lastFile := fmt.Sprintf("%s.%d%s", name, maxFiles-1, extension)
-	err := os.Remove(lastFile)
-	if err != nil && !os.IsNotExist(err) {
-		return errors.Wrap(err, "error removing oldest log file")
+	for _, suffix := range suffixes {
+		lastFile := fmt.Sprintf("%s.%d%s", name, maxFiles-1, suffix)
+		err := os.Remove(lastFile)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "error removing oldest log file")
+		}
 	}
 
 	for i := maxFiles - 1; i > 1; i-- {
-		toPath := name + "." + strconv.Itoa(i) + extension
-		fromPath := name + "." + strconv.Itoa(i-1) + extension
-		if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
-			return err
+		for _, suffix := range suffixes {
+			toPath := name + "." + strconv.Itoa(i) + suffix
+			fromPath := name + "." + strconv.Itoa(i-1) + suffix
+			if err := os.Rename(fromPath, toPath); err != nil && !os.IsNotExist(err) {
+				return err
+			}
 		}
 	}
 
@@ -236,51 +231,6 @@ func rotate(name string, maxFiles int, compress bool) error {
 	return nil
 }
 
-func compressFile(fileName string, lastTimestamp time.Time) {
-	file, err := os.Open(fileName)
-	if err != nil {
-		logrus.Errorf("Failed to open log file: %v", err)
-		return
-	}
-	defer func() {
-		file.Close()
-		err := os.Remove(fileName)
-		if err != nil {
-			logrus.Errorf("Failed to remove source log file: %v", err)
-		}
-	}()
-
-	outFile, err := os.OpenFile(fileName+".gz", os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0640)
-	if err != nil {
-		logrus.Errorf("Failed to open or create gzip log file: %v", err)
-		return
-	}
-	defer func() {
-		outFile.Close()
-		if err != nil {
-			os.Remove(fileName + ".gz")
-		}
-	}()
-
-	compressWriter := gzip.NewWriter(outFile)
-	defer compressWriter.Close()
-
-	// Add the last log entry timestramp to the gzip header
-	extra := rotateFileMetadata{}
-	extra.LastTime = lastTimestamp
-	compressWriter.Header.Extra, err = json.Marshal(&extra)
-	if err != nil {
-		// Here log the error only and don't return since this is just an optimization.
-		logrus.Warningf("Failed to marshal gzip header as JSON: %v", err)
-	}
-
-	_, err = pools.Copy(compressWriter, file)
-	if err != nil {
-		logrus.WithError(err).WithField("module", "container.logs").WithField("file", fileName).Error("Error compressing log file")
-		return
-	}
-}
-
 // MaxFiles return maximum number of files
 func (w *LogFile) MaxFiles() int {
 	return w.maxFiles
@@ -373,7 +323,7 @@ func (w *LogFile) ReadLogs(config logger.ReadConfig, watcher *logger.LogWatcher)
 
 	notifyRotate := w.notifyRotate.Subscribe()
 	defer w.notifyRotate.Evict(notifyRotate)
-	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config.Since, config.Until)
+	followLogs(currentFile, watcher, notifyRotate, w.createDecoder, config)
 }
 
 func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File, err error) {
@@ -396,21 +346,14 @@ func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File,
 	}()
 
 	for i := w.maxFiles; i > 1; i-- {
-		f, err := os.Open(fmt.Sprintf("%s.%d", w.f.Name(), i-1))
+		baseName := fmt.Sprintf("%s.%d", w.f.Name(), i-1)
+		f, err := os.Open(baseName)
 		if err != nil {
 			if !os.IsNotExist(err) {
 				return nil, errors.Wrap(err, "error opening rotated log file")
 			}
 
-			fileName := fmt.Sprintf("%s.%d.gz", w.f.Name(), i-1)
-			decompressedFileName := fileName + tmpLogfileSuffix
-			tmpFile, err := w.filesRefCounter.GetReference(decompressedFileName, func(refFileName string, exists bool) (*os.File, error) {
-				if exists {
-					return os.Open(refFileName)
-				}
-				return decompressfile(fileName, refFileName, config.Since)
-			})
-
+			tmpFile, err := w.openCompressedFile(baseName, config.Since)
 			if err != nil {
 				if !os.IsNotExist(errors.Cause(err)) {
 					return nil, errors.Wrap(err, "error getting reference to decompressed log file")
@@ -431,42 +374,27 @@ func (w *LogFile) openRotatedFiles(config logger.ReadConfig) (files []*os.File,
 	return files, nil
 }
 
-func decompressfile(fileName, destFileName string, since time.Time) (*os.File, error) {
-	cf, err := os.Open(fileName)
-	if err != nil {
-		return nil, errors.Wrap(err, "error opening file for decompression")
-	}
-	defer cf.Close()
-
-	rc, err := gzip.NewReader(cf)
-	if err != nil {
-		return nil, errors.Wrap(err, "error making gzip reader for compressed log file")
-	}
-	defer rc.Close()
-
-	// Extract the last log entry timestramp from the gzip header
-	extra := &rotateFileMetadata{}
-	err = json.Unmarshal(rc.Header.Extra, extra)
-	if err == nil && extra.LastTime.Before(since) {
-		return nil, nil
-	}
-
-	rs, err := os.OpenFile(destFileName, os.O_CREATE|os.O_RDWR, 0640)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating file for copying decompressed log stream")
-	}
-
-	_, err = pools.Copy(rs, rc)
-	if err != nil {
-		rs.Close()
-		rErr := os.Remove(rs.Name())
-		if rErr != nil && !os.IsNotExist(rErr) {
-			logrus.Errorf("Failed to remove logfile: %v", rErr)
+// openCompressedFile returns the decompressed content of the rotated log file
+// baseName, whatever codec it was compressed with. It returns a nil file if
+// the file does not need to be read because its last entry is older than
+// since.
+func (w *LogFile) openCompressedFile(baseName string, since time.Time) (*os.File, error) {
+	var err error
+	for _, c := range codecs {
+		fileName := baseName + c.ext
+		decompressedFileName := fileName + tmpLogfileSuffix
+		var f *os.File
+		f, err = w.filesRefCounter.GetReference(decompressedFileName, func(refFileName string, exists bool) (*os.File, error) {
+			if exists {
+				return os.Open(refFileName)
+			}
+			return decompressfile(baseName, fileName, refFileName, c, since)
+		})
+		if err == nil || !os.IsNotExist(errors.Cause(err)) {
+			return f, err
 		}
-		return nil, errors.Wrap(err, "error while copying decompressed log stream to file")
 	}
-
-	return rs, nil
+	return nil, err
 }
 
 func newSectionReader(f *os.File) (*io.SectionReader, error) {
@@ -495,7 +423,13 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 
 	readers := make([]io.Reader, 0, len(files))
 
-	if config.Tail > 0 {
+	// When filtering, the number of lines to read from the end of the files
+	// cannot be known upfront, so all files are read and the last matching
+	// messages are kept in tail.
+	filtered := hasFilters(config)
+	var tail []*logger.Message
+
+	if config.Tail > 0 && !filtered {
 		for i := len(files) - 1; i >= 0 && nLines > 0; i-- {
 			tail, n, err := getTailReader(ctx, files[i], nLines)
 			if err != nil {
@@ -518,15 +452,34 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 		if err != nil {
 			if errors.Cause(err) != io.EOF {
 				watcher.Err <- err
+				return
 			}
-			return
+			break
 		}
 		if !config.Since.IsZero() && msg.Timestamp.Before(config.Since) {
 			continue
 		}
 		if !config.Until.IsZero() && msg.Timestamp.After(config.Until) {
+			break
+		}
+		if !matchesFilters(config, msg) {
+			continue
+		}
+		if filtered && config.Tail > 0 {
+			if len(tail) == config.Tail {
+				tail = tail[1:]
+			}
+			tail = append(tail, msg)
+			continue
+		}
+		select {
+		case <-ctx.Done():
 			return
+		case watcher.Msg <- msg:
 		}
+	}
+
+	for _, msg := range tail {
 		select {
 		case <-ctx.Done():
 			return
@@ -535,7 +488,45 @@ func tailFiles(files []SizeReaderAt, watcher *logger.LogWatcher, createDecoder m
 	}
 }
 
-func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, since, until time.Time) {
+// hasFilters returns whether the config restricts the messages to read
+// based on their content.
+func hasFilters(config logger.ReadConfig) bool {
+	return len(config.Sources) > 0 || len(config.Attrs) > 0 || config.Grep != nil
+}
+
+// matchesFilters returns whether the message matches the source, attribute
+// and grep filters of the config.
+func matchesFilters(config logger.ReadConfig, msg *logger.Message) bool {
+	if len(config.Sources) > 0 {
+		var found bool
+		for _, src := range config.Sources {
+			if msg.Source == src {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for k, v := range config.Attrs {
+		var found bool
+		for _, attr := range msg.Attrs {
+			if attr.Key == k && attr.Value == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return config.Grep == nil || config.Grep.Match(msg.Line)
+}
+
+func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan interface{}, createDecoder makeDecoderFunc, config logger.ReadConfig) {
 	decodeLogLine := createDecoder(f)
 
 	name := f.Name()
@@ -649,12 +640,15 @@ func followLogs(f *os.File, logWatcher *logger.LogWatcher, notifyRotate chan int
 		}
 
 		retries = 0 // reset retries since we've succeeded
-		if !since.IsZero() && msg.Timestamp.Before(since) {
+		if !config.Since.IsZero() && msg.Timestamp.Before(config.Since) {
 			continue
 		}
-		if !until.IsZero() && msg.Timestamp.After(until) {
+		if !config.Until.IsZero() && msg.Timestamp.After(config.Until) {
 			return
 		}
+		if !matchesFilters(config, msg) {
+			continue
+		}
 		// send the message, unless the consumer is gone
 		select {
 		case logWatcher.Msg <- msg:
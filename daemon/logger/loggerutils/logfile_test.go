@@ -3,13 +3,17 @@ package loggerutils
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/pkg/tailfile"
 	"gotest.tools/assert"
@@ -77,6 +81,109 @@ func TestTailFiles(t *testing.T) {
 	}
 }
 
+func TestTailFilesWithFilters(t *testing.T) {
+	content := strings.Join([]string{
+		"stdout app=web INFO starting",
+		"stderr app=web ERROR first failure",
+		"stdout app=web ERROR not on stderr",
+		"stderr app=db ERROR other app",
+		"stderr app=web ERROR second failure",
+		"stderr app=web WARN not an error",
+		"stderr app=web ERROR third failure",
+	}, "\n") + "\n"
+
+	// each line is "<source> <key>=<value> <text>"
+	createDecoder := func(r io.Reader) func() (*logger.Message, error) {
+		scanner := bufio.NewScanner(r)
+		return func() (*logger.Message, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			fields := strings.SplitN(scanner.Text(), " ", 3)
+			attr := strings.SplitN(fields[1], "=", 2)
+			return &logger.Message{
+				Source:    fields[0],
+				Attrs:     []backend.LogAttr{{Key: attr[0], Value: attr[1]}},
+				Line:      []byte(fields[2]),
+				Timestamp: time.Now(),
+			}, nil
+		}
+	}
+	tailReader := func(ctx context.Context, r SizeReaderAt, lines int) (io.Reader, int, error) {
+		return tailfile.NewTailReader(ctx, r, lines)
+	}
+
+	for _, tc := range []struct {
+		desc     string
+		config   logger.ReadConfig
+		expected []string
+	}{
+		{
+			desc:     "sources",
+			config:   logger.ReadConfig{Tail: -1, Sources: []string{"stdout"}},
+			expected: []string{"INFO starting", "ERROR not on stderr"},
+		},
+		{
+			desc:     "attrs",
+			config:   logger.ReadConfig{Tail: -1, Attrs: map[string]string{"app": "db"}},
+			expected: []string{"ERROR other app"},
+		},
+		{
+			desc: "all filters",
+			config: logger.ReadConfig{
+				Tail:    -1,
+				Sources: []string{"stderr"},
+				Attrs:   map[string]string{"app": "web"},
+				Grep:    regexp.MustCompile("ERROR"),
+			},
+			expected: []string{"ERROR first failure", "ERROR second failure", "ERROR third failure"},
+		},
+		{
+			desc: "tail counts matching lines",
+			config: logger.ReadConfig{
+				Tail:    2,
+				Sources: []string{"stderr"},
+				Grep:    regexp.MustCompile("^ERROR"),
+			},
+			expected: []string{"ERROR second failure", "ERROR third failure"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			watcher := logger.NewLogWatcher()
+			files := []SizeReaderAt{strings.NewReader(content)}
+			done := make(chan struct{})
+			go func() {
+				tailFiles(files, watcher, createDecoder, tailReader, tc.config)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("timeout waiting for tailFiles() to finish")
+			}
+
+			var lines []string
+		loop:
+			for {
+				select {
+				case msg := <-watcher.Msg:
+					lines = append(lines, string(msg.Line))
+				case err := <-watcher.Err:
+					assert.NilError(t, err)
+				default:
+					break loop
+				}
+			}
+			assert.DeepEqual(t, lines, tc.expected)
+		})
+	}
+}
+
 func TestFollowLogsConsumerGone(t *testing.T) {
 	lw := logger.NewLogWatcher()
 
@@ -94,9 +201,8 @@ func TestFollowLogsConsumerGone(t *testing.T) {
 	}
 
 	followLogsDone := make(chan struct{})
-	var since, until time.Time
 	go func() {
-		followLogs(f, lw, make(chan interface{}), makeDecoder, since, until)
+		followLogs(f, lw, make(chan interface{}), makeDecoder, logger.ReadConfig{})
 		close(followLogsDone)
 	}()
 
@@ -140,11 +246,10 @@ func TestFollowLogsProducerGone(t *testing.T) {
 			return &logger.Message{}, nil
 		}
 	}
-	var since, until time.Time
 
 	followLogsDone := make(chan struct{})
 	go func() {
-		followLogs(f, lw, make(chan interface{}), makeDecoder, since, until)
+		followLogs(f, lw, make(chan interface{}), makeDecoder, logger.ReadConfig{})
 		close(followLogsDone)
 	}()
 
@@ -201,3 +306,96 @@ func TestFollowLogsProducerGone(t *testing.T) {
 	default:
 	}
 }
+
+func TestParseCompression(t *testing.T) {
+	for value, expected := range map[string]Compression{
+		"gzip":  CompressionGzip,
+		"zstd":  CompressionZstd,
+		"none":  CompressionNone,
+		"true":  CompressionGzip,
+		"false": CompressionNone,
+	} {
+		c, err := ParseCompression(value)
+		assert.NilError(t, err)
+		assert.Check(t, c == expected, "%s: got %s", value, c)
+	}
+
+	_, err := ParseCompression("lz4")
+	assert.ErrorContains(t, err, "invalid value for compress")
+}
+
+func TestLogFileMixedCompression(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "container.log")
+
+	marshal := func(msg *logger.Message) ([]byte, error) {
+		return append(msg.Line, '\n'), nil
+	}
+	createDecoder := func(r io.Reader) func() (*logger.Message, error) {
+		scanner := bufio.NewScanner(r)
+		return func() (*logger.Message, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, io.EOF
+			}
+			return &logger.Message{Line: append([]byte(nil), scanner.Bytes()...)}, nil
+		}
+	}
+	tailReader := func(ctx context.Context, r SizeReaderAt, lines int) (io.Reader, int, error) {
+		return tailfile.NewTailReader(ctx, r, lines)
+	}
+
+	var expected []string
+	writeLines := func(compression Compression, prefix string) {
+		// Each line fills a file, so that every line but the last one
+		// ends up in a rotated file.
+		l, err := NewLogFile(logPath, 5, 10, compression, marshal, createDecoder, 0640, tailReader)
+		assert.NilError(t, err)
+		for i := 0; i < 3; i++ {
+			line := fmt.Sprintf("%s-%d", prefix, i)
+			assert.NilError(t, l.WriteLogEntry(&logger.Message{Line: []byte(line), Timestamp: time.Now()}))
+			expected = append(expected, line)
+		}
+		// wait for the compression of the last rotated file
+		l.rotateMu.Lock()
+		l.rotateMu.Unlock()
+		assert.NilError(t, l.Close())
+	}
+	writeLines(CompressionZstd, "zstd")
+	writeLines(CompressionGzip, "gzip")
+	writeLines(CompressionNone, "none")
+
+	// Files are rotated when writing the next line, so each log file
+	// compresses the last file written with the previous configuration.
+	for _, name := range []string{"container.log.8.zst", "container.log.8.meta", "container.log.6.gz", "container.log.6.meta", "container.log.3", "container.log.1"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.Check(t, err, "expected rotated file %s", name)
+	}
+	_, err = os.Stat(filepath.Join(dir, "container.log.3.meta"))
+	assert.Check(t, os.IsNotExist(err), "unexpected metadata for uncompressed file")
+
+	l, err := NewLogFile(logPath, 5, 10, CompressionNone, marshal, createDecoder, 0640, tailReader)
+	assert.NilError(t, err)
+	defer l.Close()
+
+	watcher := logger.NewLogWatcher()
+	l.ReadLogs(logger.ReadConfig{Tail: -1}, watcher)
+
+	var lines []string
+loop:
+	for {
+		select {
+		case msg := <-watcher.Msg:
+			lines = append(lines, string(msg.Line))
+		case err := <-watcher.Err:
+			assert.NilError(t, err)
+		default:
+			break loop
+		}
+	}
+	assert.DeepEqual(t, lines, expected)
+}
@@ -8,6 +8,7 @@ var (
 	logWritesFailedCount metrics.Counter
 	logReadsFailedCount  metrics.Counter
 	totalPartialLogs     metrics.Counter
+	logsDroppedCount     metrics.Counter
 )
 
 func init() {
@@ -16,6 +17,7 @@ func init() {
 	logWritesFailedCount = loggerMetrics.NewCounter("log_write_operations_failed", "Number of log write operations that failed")
 	logReadsFailedCount = loggerMetrics.NewCounter("log_read_operations_failed", "Number of log reads from container stdio that failed")
 	totalPartialLogs = loggerMetrics.NewCounter("log_entries_size_greater_than_buffer", "Number of log entries which are larger than the log buffer")
+	logsDroppedCount = loggerMetrics.NewCounter("log_entries_dropped", "Number of log entries dropped by the log rate limiter")
 
 	metrics.Register(loggerMetrics)
 }
@@ -0,0 +1,127 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultMultilineTimeout is the default time after which an aggregated log
+// entry is sent when no more lines are received.
+const defaultMultilineTimeout = time.Second
+
+// MultilineConfig is the configuration of the aggregation of multi-line log
+// entries, such as stack traces, by the Copier.
+type MultilineConfig struct {
+	// Pattern matches the first line of a log entry. Lines which do not
+	// match it are appended to the previous entry.
+	Pattern *regexp.Regexp
+	// Timeout is the time after which an entry is sent when no more lines
+	// are received.
+	Timeout time.Duration
+}
+
+// ParseMultilineConfig parses the "multiline-pattern" and "multiline-timeout"
+// log options. It returns a nil config if multi-line aggregation is not
+// enabled.
+func ParseMultilineConfig(cfg map[string]string) (*MultilineConfig, error) {
+	s, ok := cfg["multiline-pattern"]
+	if !ok {
+		if _, ok := cfg["multiline-timeout"]; ok {
+			return nil, fmt.Errorf("logger: multiline-timeout option is only supported with the multiline-pattern option")
+		}
+		return nil, nil
+	}
+
+	pattern, err := regexp.Compile(s)
+	if err != nil {
+		return nil, errors.Wrapf(err, "logger: invalid value for multiline-pattern: %q", s)
+	}
+	c := &MultilineConfig{
+		Pattern: pattern,
+		Timeout: defaultMultilineTimeout,
+	}
+
+	if s, ok := cfg["multiline-timeout"]; ok {
+		c.Timeout, err = time.ParseDuration(s)
+		if err != nil || c.Timeout <= 0 {
+			return nil, fmt.Errorf("logger: invalid value for multiline-timeout: %q: must be a positive duration", s)
+		}
+	}
+	return c, nil
+}
+
+// multilineAggregator merges the continuation lines of a log entry into the
+// message of its first line. The pending entry is sent when the first line of
+// the next entry is received, when it would grow larger than maxSize, or when
+// no lines are received for the configured timeout.
+//
+// Messages of partial lines, which are too long for the copier buffer, are
+// never merged, so that they can still be reassembled by readers.
+type multilineAggregator struct {
+	log     func(*Message)
+	pattern *regexp.Regexp
+	timeout time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	pending *Message
+	timer   *time.Timer
+}
+
+func newMultilineAggregator(cfg MultilineConfig, maxSize int, log func(*Message)) *multilineAggregator {
+	a := &multilineAggregator{
+		log:     log,
+		pattern: cfg.Pattern,
+		timeout: cfg.Timeout,
+		maxSize: maxSize,
+	}
+	a.timer = time.AfterFunc(cfg.Timeout, a.flush)
+	a.timer.Stop()
+	return a
+}
+
+// add aggregates the message of a line.
+func (a *multilineAggregator) add(msg *Message) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if msg.PLogMetaData != nil {
+		a.flushLocked()
+		a.log(msg)
+		return
+	}
+
+	if a.pending != nil && !a.pattern.Match(msg.Line) && len(a.pending.Line)+1+len(msg.Line) <= a.maxSize {
+		a.pending.Line = append(a.pending.Line, '\n')
+		a.pending.Line = append(a.pending.Line, msg.Line...)
+		PutMessage(msg)
+	} else {
+		a.flushLocked()
+		a.pending = msg
+	}
+	a.timer.Reset(a.timeout)
+}
+
+// flush sends the pending entry, if any.
+func (a *multilineAggregator) flush() {
+	a.mu.Lock()
+	a.flushLocked()
+	a.mu.Unlock()
+}
+
+func (a *multilineAggregator) flushLocked() {
+	if a.pending != nil {
+		a.log(a.pending)
+		a.pending = nil
+	}
+}
+
+// close sends the pending entry and stops the timer.
+func (a *multilineAggregator) close() {
+	a.timer.Stop()
+	a.flush()
+}
@@ -0,0 +1,141 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sizedMockLogger struct {
+	*mockLogger
+	size int
+}
+
+func (l *sizedMockLogger) BufSize() int {
+	return l.size
+}
+
+func TestParseMultilineConfig(t *testing.T) {
+	c, err := ParseMultilineConfig(map[string]string{})
+	if err != nil || c != nil {
+		t.Fatalf("expected no multiline config, got %+v, %v", c, err)
+	}
+
+	c, err = ParseMultilineConfig(map[string]string{"multiline-pattern": `^\S`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Pattern.String() != `^\S` || c.Timeout != defaultMultilineTimeout {
+		t.Fatalf("unexpected default multiline config: %+v", c)
+	}
+
+	c, err = ParseMultilineConfig(map[string]string{"multiline-pattern": `^\d{4}-`, "multiline-timeout": "250ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Timeout != 250*time.Millisecond {
+		t.Fatalf("unexpected multiline config: %+v", c)
+	}
+
+	for _, cfg := range []map[string]string{
+		{"multiline-timeout": "1s"},
+		{"multiline-pattern": "("},
+		{"multiline-pattern": "^a", "multiline-timeout": "0s"},
+		{"multiline-pattern": "^a", "multiline-timeout": "1"},
+	} {
+		if _, err := ParseMultilineConfig(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
+
+func readLines(c chan *Message) []string {
+	var lines []string
+	for msg := range c {
+		lines = append(lines, string(msg.Line))
+	}
+	return lines
+}
+
+func TestCopierMultiline(t *testing.T) {
+	input := "2019-01-01 Exception in thread \"main\"\n" +
+		"\tat com.example.Main.run(Main.java:10)\n" +
+		"\tat com.example.Main.main(Main.java:5)\n" +
+		"2019-01-01 done\n" +
+		"orphan continuation\n"
+
+	mockLog := &mockLogger{make(chan *Message, 10)}
+	c := NewMultilineCopier(map[string]io.Reader{"stdout": bytes.NewBufferString(input)}, mockLog,
+		MultilineConfig{Pattern: regexp.MustCompile(`^\d{4}-`), Timeout: time.Minute})
+	c.Run()
+	c.Wait()
+	close(mockLog.c)
+
+	lines := readLines(mockLog.c)
+	expected := []string{
+		"2019-01-01 Exception in thread \"main\"\n\tat com.example.Main.run(Main.java:10)\n\tat com.example.Main.main(Main.java:5)",
+		"2019-01-01 done\norphan continuation",
+	}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+}
+
+func TestCopierMultilineTimeout(t *testing.T) {
+	r, w := io.Pipe()
+	mockLog := &mockLogger{make(chan *Message, 10)}
+	c := NewMultilineCopier(map[string]io.Reader{"stdout": r}, mockLog,
+		MultilineConfig{Pattern: regexp.MustCompile(`^\S`), Timeout: 10 * time.Millisecond})
+	c.Run()
+
+	if _, err := w.Write([]byte("first\n  continued\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-mockLog.c:
+		if string(msg.Line) != "first\n  continued" {
+			t.Fatalf("unexpected message %q", msg.Line)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the pending entry to be flushed")
+	}
+
+	w.Close()
+	c.Wait()
+	close(mockLog.c)
+	if lines := readLines(mockLog.c); len(lines) != 0 {
+		t.Fatalf("unexpected messages %q", lines)
+	}
+}
+
+func TestCopierMultilineMaxSize(t *testing.T) {
+	// The first entry is flushed when it would grow larger than the buffer,
+	// and the long line is sent as partial messages which are not merged.
+	input := "start\n  1234\n  5678\n" + strings.Repeat("x", 24) + "\n  after\n"
+
+	mockLog := &sizedMockLogger{&mockLogger{make(chan *Message, 10)}, 16}
+	c := NewMultilineCopier(map[string]io.Reader{"stdout": bytes.NewBufferString(input)}, mockLog,
+		MultilineConfig{Pattern: regexp.MustCompile(`^\S`), Timeout: time.Minute})
+	c.Run()
+	c.Wait()
+	close(mockLog.c)
+
+	var lines []string
+	var partials int
+	for msg := range mockLog.c {
+		lines = append(lines, string(msg.Line))
+		if msg.PLogMetaData != nil {
+			partials++
+		}
+	}
+	expected := []string{"start\n  1234", "  5678", strings.Repeat("x", 16), strings.Repeat("x", 8), "  after"}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+	if partials != 2 {
+		t.Fatalf("expected 2 partial messages, got %d", partials)
+	}
+}
@@ -0,0 +1,144 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gotest.tools/assert"
+)
+
+// collectorMock is a fake OTLP collector recording the exported requests.
+type collectorMock struct {
+	mu       sync.Mutex
+	requests []*exportLogsServiceRequest
+	headers  []map[string]string
+	// failures is the number of exports to fail before accepting them.
+	failures int
+	attempts int
+}
+
+func (c *collectorMock) receive(req *exportLogsServiceRequest, headers map[string]string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.failures > 0 {
+		c.failures--
+		return false
+	}
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, headers)
+	return true
+}
+
+// records returns the log records received by the collector.
+func (c *collectorMock) records() []*logRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var records []*logRecord
+	for _, req := range c.requests {
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+	}
+	return records
+}
+
+// waitForRecords waits for the collector to receive n log records.
+func (c *collectorMock) waitForRecords(t *testing.T, n int) []*logRecord {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		records := c.records()
+		if len(records) >= n {
+			return records
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %d log records, got %d", n, len(records))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+var logsServiceDesc = grpc.ServiceDesc{
+	ServiceName: "opentelemetry.proto.collector.logs.v1.LogsService",
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Export",
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+			req := &exportLogsServiceRequest{}
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			headers := make(map[string]string)
+			md, _ := metadata.FromIncomingContext(ctx)
+			for k, v := range md {
+				headers[k] = v[0]
+			}
+			if !srv.(*collectorMock).receive(req, headers) {
+				return nil, status.Error(codes.Unavailable, "collector unavailable")
+			}
+			return &exportLogsServiceResponse{}, nil
+		},
+	}},
+}
+
+// newGRPCCollectorMock starts a fake gRPC OTLP collector, and returns it with
+// its address and a function stopping it.
+func newGRPCCollectorMock(t *testing.T) (*collectorMock, string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+
+	c := &collectorMock{}
+	s := grpc.NewServer()
+	s.RegisterService(&logsServiceDesc, c)
+	go s.Serve(l)
+
+	return c, l.Addr().String(), s.Stop
+}
+
+// newHTTPCollectorMock starts a fake HTTP OTLP collector, and returns it with
+// its logs endpoint URL and a function stopping it.
+func newHTTPCollectorMock() (*collectorMock, string, func()) {
+	c := &collectorMock{}
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != defaultHTTPPath || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		req := &exportLogsServiceRequest{}
+		if err := req.Unmarshal(body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		headers := make(map[string]string)
+		for k := range r.Header {
+			headers[k] = r.Header.Get(k)
+		}
+		if !c.receive(req, headers) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		b, _ := (&exportLogsServiceResponse{}).Marshal()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.Write(b)
+	}))
+
+	return c, s.URL + defaultHTTPPath, s.Close
+}
@@ -0,0 +1,164 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/docker/docker/pkg/pools"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	exportMethod    = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+	maxResponseSize = 1024
+)
+
+// exporter sends log records to an OTLP collector.
+type exporter interface {
+	// export sends the request to the collector. Errors for which the
+	// request must not be retried are wrapped in a permanentError.
+	export(ctx context.Context, req *exportLogsServiceRequest) error
+	close() error
+}
+
+// permanentError is an error returned by an exporter when the export failed
+// and must not be retried.
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Cause() error {
+	return e.error
+}
+
+type grpcExporter struct {
+	conn    *grpc.ClientConn
+	headers metadata.MD
+}
+
+func newGRPCExporter(endpoint string, insecure bool, headers map[string]string) (*grpcExporter, error) {
+	opts := []grpc.DialOption{grpc.WithUserAgent("docker-otlp-logger")}
+	if insecure {
+		opts = append(opts, grpc.WithInsecure())
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{})))
+	}
+	// Dialing does not block, the connection is established in the
+	// background and re-established when lost.
+	conn, err := grpc.Dial(endpoint, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error connecting to OTLP endpoint %s", endpoint)
+	}
+	return &grpcExporter{conn: conn, headers: metadata.New(headers)}, nil
+}
+
+func (e *grpcExporter) export(ctx context.Context, req *exportLogsServiceRequest) error {
+	if len(e.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.headers)
+	}
+	resp := &exportLogsServiceResponse{}
+	err := e.conn.Invoke(ctx, exportMethod, req, resp)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+			return err
+		default:
+			return permanentError{err}
+		}
+	}
+	return resp.partialSuccessError()
+}
+
+func (e *grpcExporter) close() error {
+	return e.conn.Close()
+}
+
+type httpExporter struct {
+	client    *http.Client
+	transport *http.Transport
+	endpoint  string
+	headers   map[string]string
+}
+
+func newHTTPExporter(endpoint string, headers map[string]string) *httpExporter {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+	return &httpExporter{
+		client:    &http.Client{Transport: transport},
+		transport: transport,
+		endpoint:  endpoint,
+		headers:   headers,
+	}
+}
+
+func (e *httpExporter) export(ctx context.Context, req *exportLogsServiceRequest) error {
+	body, err := req.Marshal()
+	if err != nil {
+		return permanentError{err}
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	httpReq = httpReq.WithContext(ctx)
+	for k, v := range e.headers {
+		httpReq.Header.Set(k, v)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("User-Agent", "docker-otlp-logger")
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		pools.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		r := &exportLogsServiceResponse{}
+		if err := r.Unmarshal(respBody); err != nil {
+			// the records were accepted, even if the response is garbled
+			return nil
+		}
+		return r.partialSuccessError()
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		return fmt.Errorf("%s: failed to export logs - %s - %s", name, resp.Status, string(respBody))
+	default:
+		return permanentError{fmt.Errorf("%s: failed to export logs - %s - %s", name, resp.Status, string(respBody))}
+	}
+}
+
+func (e *httpExporter) close() error {
+	e.transport.CloseIdleConnections()
+	return nil
+}
+
+// partialSuccessError returns an error if the collector rejected some of the
+// exported log records. Such exports must not be retried.
+func (m *exportLogsServiceResponse) partialSuccessError() error {
+	if m.RejectedLogRecords == 0 {
+		return nil
+	}
+	return permanentError{fmt.Errorf("%s: collector rejected %d log records: %s", name, m.RejectedLogRecords, m.ErrorMessage)}
+}
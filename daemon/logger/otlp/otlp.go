@@ -0,0 +1,499 @@
+// Package otlp provides the log driver for forwarding server logs to
+// OpenTelemetry collectors using the OTLP protocol.
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/daemon/logger/loggerutils"
+	"github.com/docker/docker/dockerversion"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	name = "otlp"
+
+	protocolGRPC = "grpc"
+	protocolHTTP = "http/protobuf"
+
+	defaultGRPCEndpoint  = "localhost:4317"
+	defaultHTTPEndpoint  = "http://localhost:4318/v1/logs"
+	defaultHTTPPath      = "/v1/logs"
+	defaultBatchSize     = 512
+	defaultFlushInterval = time.Second
+	defaultBufferLimit   = 8192
+	defaultRetryWait     = time.Second
+	defaultMaxRetries    = 10
+
+	maxRetryWait  = 30 * time.Second
+	exportTimeout = 10 * time.Second
+	closeTimeout  = 10 * time.Second
+
+	// droppedWarnInterval is the minimum interval between the warnings
+	// about the records dropped because the buffer was full.
+	droppedWarnInterval = time.Minute
+
+	scopeName = "github.com/docker/docker/daemon/logger/otlp"
+
+	endpointKey      = "otlp-endpoint"
+	protocolKey      = "otlp-protocol"
+	insecureKey      = "otlp-insecure"
+	headersKey       = "otlp-headers"
+	batchSizeKey     = "otlp-batch-size"
+	flushIntervalKey = "otlp-flush-interval"
+	bufferLimitKey   = "otlp-buffer-limit"
+	retryWaitKey     = "otlp-retry-wait"
+	maxRetriesKey    = "otlp-max-retries"
+)
+
+type otlpLogger struct {
+	// dropped is the number of records dropped because the buffer was full
+	// since the last warning. It is first in the struct for the alignment
+	// required by atomic operations.
+	dropped uint64
+
+	containerID string
+	exporter    exporter
+	resource    *resource
+
+	batchSize     int
+	flushInterval time.Duration
+	retryWait     time.Duration
+	maxRetries    int
+
+	mu      sync.RWMutex
+	closed  bool
+	records chan *logRecord
+
+	lastDroppedWarn time.Time // only accessed by the worker
+
+	// ctx is canceled when the logger is closed and the remaining records
+	// could not be sent in time.
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type config struct {
+	protocol      string
+	endpoint      string
+	insecure      bool
+	headers       map[string]string
+	batchSize     int
+	flushInterval time.Duration
+	bufferLimit   int
+	retryWait     time.Duration
+	maxRetries    int
+}
+
+func init() {
+	if err := logger.RegisterLogDriver(name, New); err != nil {
+		logrus.Fatal(err)
+	}
+	if err := logger.RegisterLogOptValidator(name, ValidateLogOpt); err != nil {
+		logrus.Fatal(err)
+	}
+}
+
+// New creates an otlp logger using the configuration passed in on the
+// context. Log records are batched and sent asynchronously to the collector
+// at otlp-endpoint, retrying with an exponential backoff on failures.
+func New(info logger.Info) (logger.Logger, error) {
+	cfg, err := parseConfig(info.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(info)
+	if err != nil {
+		return nil, err
+	}
+
+	var exp exporter
+	switch cfg.protocol {
+	case protocolGRPC:
+		exp, err = newGRPCExporter(cfg.endpoint, cfg.insecure, cfg.headers)
+		if err != nil {
+			return nil, err
+		}
+	case protocolHTTP:
+		exp = newHTTPExporter(cfg.endpoint, cfg.headers)
+	}
+
+	logrus.WithField("container", info.ContainerID).WithField("protocol", cfg.protocol).WithField("endpoint", cfg.endpoint).
+		Debug("logging driver otlp configured")
+
+	return newLogger(info.ContainerID, exp, res, cfg), nil
+}
+
+func newLogger(containerID string, exp exporter, res *resource, cfg *config) *otlpLogger {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &otlpLogger{
+		containerID:   containerID,
+		exporter:      exp,
+		resource:      res,
+		batchSize:     cfg.batchSize,
+		flushInterval: cfg.flushInterval,
+		retryWait:     cfg.retryWait,
+		maxRetries:    cfg.maxRetries,
+		records:       make(chan *logRecord, cfg.bufferLimit),
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+	go l.worker()
+	return l
+}
+
+// newResource returns the OTLP resource describing the container.
+func newResource(info logger.Info) (*resource, error) {
+	tag, err := loggerutils.ParseLogTag(info, "{{.Name}}")
+	if err != nil {
+		return nil, err
+	}
+	hostname, err := info.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	extra, err := info.ExtraAttributes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := map[string]string{
+		"service.name":         tag,
+		"container.id":         info.ContainerID,
+		"container.name":       info.Name(),
+		"container.image.name": info.ContainerImageName,
+		"container.image.id":   info.ContainerImageID,
+		"container.runtime":    "docker",
+		"host.name":            hostname,
+	}
+	for k, v := range extra {
+		if _, ok := attrs[k]; !ok {
+			attrs[k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := &resource{}
+	for _, k := range keys {
+		if v := attrs[k]; v != "" {
+			res.Attributes = append(res.Attributes, &keyValue{Key: k, Value: v})
+		}
+	}
+	return res, nil
+}
+
+func (l *otlpLogger) Log(msg *logger.Message) error {
+	record := &logRecord{
+		TimeUnixNano:         uint64(msg.Timestamp.UnixNano()),
+		ObservedTimeUnixNano: uint64(time.Now().UnixNano()),
+		Body:                 string(msg.Line),
+		Attributes:           []*keyValue{{Key: "log.iostream", Value: msg.Source}},
+	}
+	switch msg.Source {
+	case "stdout":
+		record.SeverityNumber = severityInfo
+	case "stderr":
+		record.SeverityNumber = severityError
+	}
+	if msg.PLogMetaData != nil {
+		record.Attributes = append(record.Attributes,
+			&keyValue{Key: "partial_message", Value: "true"},
+			&keyValue{Key: "partial_id", Value: msg.PLogMetaData.ID},
+			&keyValue{Key: "partial_ordinal", Value: strconv.Itoa(msg.PLogMetaData.Ordinal)},
+			&keyValue{Key: "partial_last", Value: strconv.FormatBool(msg.PLogMetaData.Last)},
+		)
+	}
+	logger.PutMessage(msg)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return fmt.Errorf("%s: driver is closed", name)
+	}
+	select {
+	case l.records <- record:
+	default:
+		// The record is dropped, like in the non-blocking mode, as returning
+		// an error would log it for every message. The dropped records are
+		// reported by the worker.
+		atomic.AddUint64(&l.dropped, 1)
+	}
+	return nil
+}
+
+// worker batches the queued log records and exports them.
+func (l *otlpLogger) worker() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.flushInterval)
+	defer ticker.Stop()
+
+	var batch []*logRecord
+	for {
+		select {
+		case record, open := <-l.records:
+			if !open {
+				l.export(batch)
+				l.warnDropped(true)
+				return
+			}
+			batch = append(batch, record)
+			if len(batch) >= l.batchSize {
+				l.export(batch)
+				batch = nil
+			}
+		case <-ticker.C:
+			l.export(batch)
+			batch = nil
+			l.warnDropped(false)
+		}
+	}
+}
+
+// warnDropped logs a warning with the number of records dropped because the
+// buffer was full, at most once per droppedWarnInterval unless force is set.
+func (l *otlpLogger) warnDropped(force bool) {
+	now := time.Now()
+	if !force && now.Sub(l.lastDroppedWarn) < droppedWarnInterval {
+		return
+	}
+	n := atomic.SwapUint64(&l.dropped, 0)
+	if n == 0 {
+		return
+	}
+	l.lastDroppedWarn = now
+	logrus.WithField("module", "logger/otlp").WithField("container", l.containerID).
+		Warnf("Dropped %d log records as the buffer was full", n)
+}
+
+// export sends the records to the collector, retrying on failures. Records
+// which could not be sent are dropped.
+func (l *otlpLogger) export(records []*logRecord) {
+	if len(records) == 0 {
+		return
+	}
+	req := &exportLogsServiceRequest{
+		ResourceLogs: []*resourceLogs{{
+			Resource: l.resource,
+			ScopeLogs: []*scopeLogs{{
+				Scope:      &instrumentationScope{Name: scopeName, Version: dockerversion.Version},
+				LogRecords: records,
+			}},
+		}},
+	}
+
+	wait := l.retryWait
+	for retries := 0; ; retries++ {
+		ctx, cancel := context.WithTimeout(l.ctx, exportTimeout)
+		err := l.exporter.export(ctx, req)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		entry := logrus.WithError(err).WithField("module", "logger/otlp")
+		if _, ok := err.(permanentError); ok || retries >= l.maxRetries {
+			entry.Errorf("Failed to export %d log records, dropping them", len(records))
+			return
+		}
+		entry.Warn("Error while exporting logs, retrying")
+
+		select {
+		case <-time.After(wait):
+		case <-l.ctx.Done():
+			entry.Errorf("Failed to export %d log records before the driver was closed, dropping them", len(records))
+			return
+		}
+		if wait *= 2; wait > maxRetryWait {
+			wait = maxRetryWait
+		}
+	}
+}
+
+// Close flushes the queued log records and closes the connection to the
+// collector. Records which cannot be sent within closeTimeout are dropped.
+func (l *otlpLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.records)
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+	case <-time.After(closeTimeout):
+		l.cancel()
+		<-l.done
+	}
+	l.cancel()
+	return l.exporter.close()
+}
+
+func (l *otlpLogger) Name() string {
+	return name
+}
+
+// ValidateLogOpt looks for otlp specific log options.
+func ValidateLogOpt(cfg map[string]string) error {
+	for key := range cfg {
+		switch key {
+		case "env":
+		case "env-regex":
+		case "labels":
+		case "labels-regex":
+		case "tag":
+		case endpointKey:
+		case protocolKey:
+		case insecureKey:
+		case headersKey:
+		case batchSizeKey:
+		case flushIntervalKey:
+		case bufferLimitKey:
+		case retryWaitKey:
+		case maxRetriesKey:
+			// Accepted
+		default:
+			return fmt.Errorf("unknown log opt '%s' for otlp log driver", key)
+		}
+	}
+
+	_, err := parseConfig(cfg)
+	return err
+}
+
+func parseConfig(opts map[string]string) (*config, error) {
+	cfg := &config{
+		protocol:      protocolGRPC,
+		batchSize:     defaultBatchSize,
+		flushInterval: defaultFlushInterval,
+		bufferLimit:   defaultBufferLimit,
+		retryWait:     defaultRetryWait,
+		maxRetries:    defaultMaxRetries,
+	}
+
+	if p, ok := opts[protocolKey]; ok {
+		switch p {
+		case protocolGRPC, protocolHTTP:
+			cfg.protocol = p
+		default:
+			return nil, fmt.Errorf("invalid %s: %q: must be %s or %s", protocolKey, p, protocolGRPC, protocolHTTP)
+		}
+	}
+
+	endpoint, err := parseEndpoint(cfg.protocol, opts[endpointKey])
+	if err != nil {
+		return nil, err
+	}
+	cfg.endpoint = endpoint
+
+	if s, ok := opts[insecureKey]; ok {
+		if cfg.protocol != protocolGRPC {
+			return nil, fmt.Errorf("%s is only supported with %s=%s, use a http:// endpoint instead", insecureKey, protocolKey, protocolGRPC)
+		}
+		if cfg.insecure, err = strconv.ParseBool(s); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", insecureKey)
+		}
+	}
+
+	if s, ok := opts[headersKey]; ok {
+		cfg.headers = make(map[string]string)
+		for _, h := range strings.Split(s, ",") {
+			kv := strings.SplitN(h, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return nil, fmt.Errorf("invalid %s: %q: must be a comma-separated list of key=value pairs", headersKey, s)
+			}
+			cfg.headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	for _, o := range []struct {
+		key   string
+		value *int
+		min   int
+	}{
+		{batchSizeKey, &cfg.batchSize, 1},
+		{bufferLimitKey, &cfg.bufferLimit, 1},
+		{maxRetriesKey, &cfg.maxRetries, 0},
+	} {
+		s, ok := opts[o.key]
+		if !ok {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < o.min {
+			return nil, fmt.Errorf("invalid %s: %q: must be an integer greater than or equal to %d", o.key, s, o.min)
+		}
+		*o.value = v
+	}
+
+	for _, o := range []struct {
+		key   string
+		value *time.Duration
+	}{
+		{flushIntervalKey, &cfg.flushInterval},
+		{retryWaitKey, &cfg.retryWait},
+	} {
+		s, ok := opts[o.key]
+		if !ok {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid %s: %q: must be a positive duration", o.key, s)
+		}
+		*o.value = d
+	}
+
+	return cfg, nil
+}
+
+// parseEndpoint validates the endpoint for the protocol. gRPC endpoints are
+// host:port addresses, and HTTP endpoints URLs, which default to the
+// standard OTLP logs path.
+func parseEndpoint(protocol, endpoint string) (string, error) {
+	if protocol == protocolGRPC {
+		if endpoint == "" {
+			return defaultGRPCEndpoint, nil
+		}
+		if strings.Contains(endpoint, "://") {
+			return "", fmt.Errorf("invalid %s: %q: must be a host:port address for protocol %s", endpointKey, endpoint, protocol)
+		}
+		return endpoint, nil
+	}
+
+	if endpoint == "" {
+		return defaultHTTPEndpoint, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid %s", endpointKey)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid %s: %q: must be a http:// or https:// URL for protocol %s", endpointKey, endpoint, protocol)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = defaultHTTPPath
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,253 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/backend"
+	"github.com/docker/docker/daemon/logger"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+)
+
+func TestValidateLogOpt(t *testing.T) {
+	err := ValidateLogOpt(map[string]string{
+		endpointKey:      "collector:4317",
+		protocolKey:      "grpc",
+		insecureKey:      "true",
+		headersKey:       "authorization=Bearer token,x-tenant=a",
+		batchSizeKey:     "100",
+		flushIntervalKey: "5s",
+		bufferLimitKey:   "1000",
+		retryWaitKey:     "500ms",
+		maxRetriesKey:    "0",
+		"env":            "a",
+		"env-regex":      "^foo",
+		"labels":         "b",
+		"labels-regex":   "^bar",
+		"tag":            "c",
+	})
+	assert.NilError(t, err)
+
+	for _, opts := range []map[string]string{
+		{"not-supported-option": "a"},
+		{protocolKey: "http/json"},
+		{endpointKey: "http://collector:4317"},
+		{protocolKey: "http/protobuf", endpointKey: "collector:4318"},
+		{protocolKey: "http/protobuf", insecureKey: "true"},
+		{insecureKey: "maybe"},
+		{headersKey: "authorization"},
+		{batchSizeKey: "0"},
+		{bufferLimitKey: "a"},
+		{maxRetriesKey: "-1"},
+		{flushIntervalKey: "0s"},
+		{retryWaitKey: "1"},
+	} {
+		assert.Check(t, ValidateLogOpt(opts) != nil, "expected an error for %v", opts)
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		protocol, endpoint, expected string
+	}{
+		{protocolGRPC, "", defaultGRPCEndpoint},
+		{protocolGRPC, "collector:4317", "collector:4317"},
+		{protocolHTTP, "", defaultHTTPEndpoint},
+		{protocolHTTP, "https://collector:4318", "https://collector:4318/v1/logs"},
+		{protocolHTTP, "https://collector:4318/", "https://collector:4318/v1/logs"},
+		{protocolHTTP, "https://collector/otlp/v1/logs", "https://collector/otlp/v1/logs"},
+	} {
+		endpoint, err := parseEndpoint(tc.protocol, tc.endpoint)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(endpoint, tc.expected))
+	}
+}
+
+func newTestInfo(config map[string]string) logger.Info {
+	return logger.Info{
+		Config:             config,
+		ContainerID:        "containeriid",
+		ContainerName:      "/container_name",
+		ContainerImageID:   "contaimageid",
+		ContainerImageName: "container_image_name",
+		ContainerLabels:    map[string]string{"com.example.team": "logs"},
+	}
+}
+
+func attributes(kvs []*keyValue) map[string]string {
+	m := make(map[string]string)
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestGRPCExport(t *testing.T) {
+	collector, addr, stop := newGRPCCollectorMock(t)
+	defer stop()
+
+	l, err := New(newTestInfo(map[string]string{
+		endpointKey:  addr,
+		insecureKey:  "true",
+		headersKey:   "x-tenant=docker",
+		batchSizeKey: "2",
+		"labels":     "com.example.team",
+	}))
+	assert.NilError(t, err)
+
+	now := time.Now()
+	for _, m := range []*logger.Message{
+		{Line: []byte("out"), Source: "stdout", Timestamp: now},
+		{Line: []byte("err"), Source: "stderr", Timestamp: now},
+		{Line: []byte("partial"), Source: "stdout", Timestamp: now, PLogMetaData: &backend.PartialLogMetaData{ID: "p1", Ordinal: 1}},
+	} {
+		assert.NilError(t, l.Log(m))
+	}
+	// The first two records are exported as soon as the batch is full.
+	collector.waitForRecords(t, 2)
+	assert.NilError(t, l.Close())
+
+	records := collector.records()
+	assert.Assert(t, is.Len(records, 3))
+
+	assert.Check(t, is.Equal(records[0].Body, "out"))
+	assert.Check(t, is.Equal(records[0].TimeUnixNano, uint64(now.UnixNano())))
+	assert.Check(t, is.Equal(records[0].SeverityNumber, int32(severityInfo)))
+	assert.Check(t, is.DeepEqual(attributes(records[0].Attributes), map[string]string{"log.iostream": "stdout"}))
+
+	assert.Check(t, is.Equal(records[1].Body, "err"))
+	assert.Check(t, is.Equal(records[1].SeverityNumber, int32(severityError)))
+
+	assert.Check(t, is.Equal(records[2].Body, "partial"))
+	assert.Check(t, is.DeepEqual(attributes(records[2].Attributes), map[string]string{
+		"log.iostream":    "stdout",
+		"partial_message": "true",
+		"partial_id":      "p1",
+		"partial_ordinal": "1",
+		"partial_last":    "false",
+	}))
+
+	hostname, err := os.Hostname()
+	assert.NilError(t, err)
+	res := collector.requests[0].ResourceLogs[0].Resource
+	assert.Check(t, is.DeepEqual(attributes(res.Attributes), map[string]string{
+		"service.name":         "container_name",
+		"container.id":         "containeriid",
+		"container.name":       "container_name",
+		"container.image.name": "container_image_name",
+		"container.image.id":   "contaimageid",
+		"container.runtime":    "docker",
+		"host.name":            hostname,
+		"com.example.team":     "logs",
+	}))
+	assert.Check(t, is.Equal(collector.headers[0]["x-tenant"], "docker"))
+}
+
+func TestHTTPExportRetry(t *testing.T) {
+	collector, endpoint, stop := newHTTPCollectorMock()
+	defer stop()
+	collector.failures = 2
+
+	l, err := New(newTestInfo(map[string]string{
+		protocolKey:      protocolHTTP,
+		endpointKey:      endpoint,
+		headersKey:       "X-Tenant=docker",
+		flushIntervalKey: "10ms",
+		retryWaitKey:     "10ms",
+		"tag":            "{{.ImageName}}",
+	}))
+	assert.NilError(t, err)
+
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("hello"), Source: "stdout", Timestamp: time.Now()}))
+	records := collector.waitForRecords(t, 1)
+	assert.NilError(t, l.Close())
+
+	assert.Check(t, is.Equal(records[0].Body, "hello"))
+	assert.Check(t, is.Equal(collector.attempts, 3))
+	assert.Check(t, is.Equal(collector.headers[0]["X-Tenant"], "docker"))
+	res := attributes(collector.requests[0].ResourceLogs[0].Resource.Attributes)
+	assert.Check(t, is.Equal(res["service.name"], "container_image_name"))
+}
+
+func TestExportMaxRetries(t *testing.T) {
+	collector, endpoint, stop := newHTTPCollectorMock()
+	defer stop()
+	collector.failures = 5
+
+	l, err := New(newTestInfo(map[string]string{
+		protocolKey:   protocolHTTP,
+		endpointKey:   endpoint,
+		retryWaitKey:  "1ms",
+		maxRetriesKey: "1",
+	}))
+	assert.NilError(t, err)
+
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("dropped"), Source: "stdout", Timestamp: time.Now()}))
+	assert.NilError(t, l.Close())
+
+	assert.Check(t, is.Len(collector.records(), 0))
+	assert.Check(t, is.Equal(collector.attempts, 2))
+}
+
+func TestLogAfterClose(t *testing.T) {
+	_, endpoint, stop := newHTTPCollectorMock()
+	defer stop()
+
+	l, err := New(newTestInfo(map[string]string{
+		protocolKey: protocolHTTP,
+		endpointKey: endpoint,
+	}))
+	assert.NilError(t, err)
+	assert.NilError(t, l.Close())
+	assert.Check(t, l.Log(&logger.Message{Line: []byte("late"), Source: "stdout"}) != nil)
+}
+
+// blockingExporter blocks the exports until it is closed.
+type blockingExporter struct {
+	unblock chan struct{}
+}
+
+func (e *blockingExporter) export(ctx context.Context, _ *exportLogsServiceRequest) error {
+	select {
+	case <-e.unblock:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func (e *blockingExporter) close() error { return nil }
+
+func TestLogBufferFull(t *testing.T) {
+	exp := &blockingExporter{unblock: make(chan struct{})}
+	l := newLogger("containerid", exp, &resource{}, &config{
+		batchSize:     1,
+		flushInterval: time.Hour,
+		bufferLimit:   1,
+	})
+
+	// the first record is taken by the worker, which blocks on the export,
+	// and the second one fills the buffer
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("1"), Source: "stdout"}))
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		if len(l.records) == 0 {
+			return poll.Success()
+		}
+		return poll.Continue("waiting for the worker to take the first record")
+	}, poll.WithDelay(time.Millisecond))
+	assert.NilError(t, l.Log(&logger.Message{Line: []byte("2"), Source: "stdout"}))
+
+	// the other records are dropped without error
+	for i := 0; i < 5; i++ {
+		assert.NilError(t, l.Log(&logger.Message{Line: []byte("dropped"), Source: "stdout"}))
+	}
+	assert.Check(t, is.Equal(atomic.LoadUint64(&l.dropped), uint64(5)))
+
+	close(exp.unblock)
+	assert.NilError(t, l.Close())
+	assert.Check(t, is.Equal(atomic.LoadUint64(&l.dropped), uint64(0)))
+}
@@ -0,0 +1,410 @@
+package otlp // import "github.com/docker/docker/daemon/logger/otlp"
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+// This file implements the subset of the OTLP logs protocol buffers
+// (opentelemetry/proto/collector/logs/v1/logs_service.proto and the messages
+// it depends on) needed by the driver. The messages implement the Marshaler
+// and Unmarshaler interfaces of github.com/golang/protobuf/proto, which are
+// used by the gRPC codec, so that they can be sent without generated code.
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Severity numbers, from opentelemetry/proto/logs/v1/logs.proto.
+const (
+	severityInfo  = 9
+	severityError = 17
+)
+
+// exportLogsServiceRequest is opentelemetry.proto.collector.logs.v1.ExportLogsServiceRequest.
+type exportLogsServiceRequest struct {
+	ResourceLogs []*resourceLogs // 1
+}
+
+// exportLogsServiceResponse is opentelemetry.proto.collector.logs.v1.ExportLogsServiceResponse,
+// with the ExportLogsPartialSuccess message inlined.
+type exportLogsServiceResponse struct {
+	RejectedLogRecords int64  // 1.1
+	ErrorMessage       string // 1.2
+}
+
+// resourceLogs is opentelemetry.proto.logs.v1.ResourceLogs.
+type resourceLogs struct {
+	Resource  *resource    // 1
+	ScopeLogs []*scopeLogs // 2
+}
+
+// resource is opentelemetry.proto.resource.v1.Resource.
+type resource struct {
+	Attributes []*keyValue // 1
+}
+
+// scopeLogs is opentelemetry.proto.logs.v1.ScopeLogs.
+type scopeLogs struct {
+	Scope      *instrumentationScope // 1
+	LogRecords []*logRecord          // 2
+}
+
+// instrumentationScope is opentelemetry.proto.common.v1.InstrumentationScope.
+type instrumentationScope struct {
+	Name    string // 1
+	Version string // 2
+}
+
+// logRecord is opentelemetry.proto.logs.v1.LogRecord, with a string body.
+type logRecord struct {
+	TimeUnixNano         uint64      // 1
+	SeverityNumber       int32       // 2
+	SeverityText         string      // 3
+	Body                 string      // 5, as AnyValue.string_value
+	Attributes           []*keyValue // 6
+	ObservedTimeUnixNano uint64      // 11
+}
+
+// keyValue is opentelemetry.proto.common.v1.KeyValue, with a string value.
+type keyValue struct {
+	Key   string // 1
+	Value string // 2, as AnyValue.string_value
+}
+
+// Reset implements proto.Message.
+func (m *exportLogsServiceRequest) Reset() { *m = exportLogsServiceRequest{} }
+
+// String implements proto.Message.
+func (m *exportLogsServiceRequest) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage implements proto.Message.
+func (*exportLogsServiceRequest) ProtoMessage() {}
+
+// Marshal implements proto.Marshaler.
+func (m *exportLogsServiceRequest) Marshal() ([]byte, error) {
+	var b []byte
+	for _, rl := range m.ResourceLogs {
+		b = appendMessage(b, 1, rl.marshal(nil))
+	}
+	return b, nil
+}
+
+// Unmarshal implements proto.Unmarshaler.
+func (m *exportLogsServiceRequest) Unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		if field == 1 && wire == wireBytes {
+			rl := &resourceLogs{}
+			if err := rl.unmarshal(data); err != nil {
+				return err
+			}
+			m.ResourceLogs = append(m.ResourceLogs, rl)
+		}
+		return nil
+	})
+}
+
+// Reset implements proto.Message.
+func (m *exportLogsServiceResponse) Reset() { *m = exportLogsServiceResponse{} }
+
+// String implements proto.Message.
+func (m *exportLogsServiceResponse) String() string { return fmt.Sprintf("%+v", *m) }
+
+// ProtoMessage implements proto.Message.
+func (*exportLogsServiceResponse) ProtoMessage() {}
+
+// Marshal implements proto.Marshaler.
+func (m *exportLogsServiceResponse) Marshal() ([]byte, error) {
+	if m.RejectedLogRecords == 0 && m.ErrorMessage == "" {
+		return nil, nil
+	}
+	var ps []byte
+	ps = appendVarint(ps, 1, uint64(m.RejectedLogRecords))
+	ps = appendString(ps, 2, m.ErrorMessage)
+	return appendMessage(nil, 1, ps), nil
+}
+
+// Unmarshal implements proto.Unmarshaler.
+func (m *exportLogsServiceResponse) Unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		if field != 1 || wire != wireBytes {
+			return nil
+		}
+		return decodeFields(data, func(field, wire int, v uint64, data []byte) error {
+			switch {
+			case field == 1 && wire == wireVarint:
+				m.RejectedLogRecords = int64(v)
+			case field == 2 && wire == wireBytes:
+				m.ErrorMessage = string(data)
+			}
+			return nil
+		})
+	})
+}
+
+func (m *resourceLogs) marshal(b []byte) []byte {
+	if m.Resource != nil {
+		b = appendMessage(b, 1, m.Resource.marshal(nil))
+	}
+	for _, sl := range m.ScopeLogs {
+		b = appendMessage(b, 2, sl.marshal(nil))
+	}
+	return b
+}
+
+func (m *resourceLogs) unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			m.Resource = &resource{}
+			return m.Resource.unmarshal(data)
+		case field == 2 && wire == wireBytes:
+			sl := &scopeLogs{}
+			if err := sl.unmarshal(data); err != nil {
+				return err
+			}
+			m.ScopeLogs = append(m.ScopeLogs, sl)
+		}
+		return nil
+	})
+}
+
+func (m *resource) marshal(b []byte) []byte {
+	for _, kv := range m.Attributes {
+		b = appendMessage(b, 1, kv.marshal(nil))
+	}
+	return b
+}
+
+func (m *resource) unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		if field == 1 && wire == wireBytes {
+			kv := &keyValue{}
+			if err := kv.unmarshal(data); err != nil {
+				return err
+			}
+			m.Attributes = append(m.Attributes, kv)
+		}
+		return nil
+	})
+}
+
+func (m *scopeLogs) marshal(b []byte) []byte {
+	if m.Scope != nil {
+		b = appendMessage(b, 1, m.Scope.marshal(nil))
+	}
+	for _, lr := range m.LogRecords {
+		b = appendMessage(b, 2, lr.marshal(nil))
+	}
+	return b
+}
+
+func (m *scopeLogs) unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			m.Scope = &instrumentationScope{}
+			return m.Scope.unmarshal(data)
+		case field == 2 && wire == wireBytes:
+			lr := &logRecord{}
+			if err := lr.unmarshal(data); err != nil {
+				return err
+			}
+			m.LogRecords = append(m.LogRecords, lr)
+		}
+		return nil
+	})
+}
+
+func (m *instrumentationScope) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Name)
+	return appendString(b, 2, m.Version)
+}
+
+func (m *instrumentationScope) unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			m.Name = string(data)
+		case field == 2 && wire == wireBytes:
+			m.Version = string(data)
+		}
+		return nil
+	})
+}
+
+func (m *logRecord) marshal(b []byte) []byte {
+	b = appendFixed64(b, 1, m.TimeUnixNano)
+	b = appendVarint(b, 2, uint64(m.SeverityNumber))
+	b = appendString(b, 3, m.SeverityText)
+	b = appendMessage(b, 5, marshalStringValue(m.Body))
+	for _, kv := range m.Attributes {
+		b = appendMessage(b, 6, kv.marshal(nil))
+	}
+	return appendFixed64(b, 11, m.ObservedTimeUnixNano)
+}
+
+func (m *logRecord) unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		var err error
+		switch {
+		case field == 1 && wire == wireFixed64:
+			m.TimeUnixNano = v
+		case field == 2 && wire == wireVarint:
+			m.SeverityNumber = int32(v)
+		case field == 3 && wire == wireBytes:
+			m.SeverityText = string(data)
+		case field == 5 && wire == wireBytes:
+			m.Body, err = unmarshalStringValue(data)
+		case field == 6 && wire == wireBytes:
+			kv := &keyValue{}
+			err = kv.unmarshal(data)
+			m.Attributes = append(m.Attributes, kv)
+		case field == 11 && wire == wireFixed64:
+			m.ObservedTimeUnixNano = v
+		}
+		return err
+	})
+}
+
+func (m *keyValue) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Key)
+	return appendMessage(b, 2, marshalStringValue(m.Value))
+}
+
+func (m *keyValue) unmarshal(b []byte) error {
+	return decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		var err error
+		switch {
+		case field == 1 && wire == wireBytes:
+			m.Key = string(data)
+		case field == 2 && wire == wireBytes:
+			m.Value, err = unmarshalStringValue(data)
+		}
+		return err
+	})
+}
+
+// marshalStringValue returns the encoding of an AnyValue holding s.
+func marshalStringValue(s string) []byte {
+	// string_value is part of a oneof, so it must be present even if empty.
+	b := appendTag(nil, 1, wireBytes)
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// unmarshalStringValue decodes an AnyValue holding a string.
+func unmarshalStringValue(b []byte) (string, error) {
+	var s string
+	err := decodeFields(b, func(field, wire int, v uint64, data []byte) error {
+		if field == 1 && wire == wireBytes {
+			s = string(data)
+		}
+		return nil
+	})
+	return s, err
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendTag(b []byte, field, wire int) []byte {
+	return appendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendVarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, field, wireVarint)
+	return appendUvarint(b, v)
+}
+
+func appendFixed64(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendTag(b, field, wireFixed64)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendTag(b, field, wireBytes)
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendMessage(b []byte, field int, m []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendUvarint(b, uint64(len(m)))
+	return append(b, m...)
+}
+
+var errTruncated = errors.New("otlp: truncated protocol buffer message")
+
+// decodeFields calls fn for each field of the encoded message b. For
+// varint and fixed fields, the value is passed in v; for length-delimited
+// fields, the content is passed in data.
+func decodeFields(b []byte, fn func(field, wire int, v uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errTruncated
+		}
+		b = b[n:]
+		field, wire := int(tag>>3), int(tag&7)
+
+		var (
+			v    uint64
+			data []byte
+		)
+		switch wire {
+		case wireVarint:
+			v, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errTruncated
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			v = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			v = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > math.MaxInt32 || uint64(len(b)-n) < l {
+				return errTruncated
+			}
+			data = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			return errors.Errorf("otlp: unsupported wire type %d", wire)
+		}
+
+		if err := fn(field, wire, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitPolicy is the policy applied to log messages exceeding the rate
+// limit.
+type RateLimitPolicy string
+
+const (
+	// RateLimitPolicyDrop drops all the messages exceeding the rate limit.
+	RateLimitPolicyDrop RateLimitPolicy = "drop"
+	// RateLimitPolicySample keeps one in every sampleRate messages
+	// exceeding the rate limit.
+	RateLimitPolicySample RateLimitPolicy = "sample"
+
+	sampleRate = 10
+)
+
+// The log options of rate limiting, which apply to all the logging drivers.
+const (
+	rateLimitOpt       = "rate-limit"
+	rateLimitBurstOpt  = "rate-limit-burst"
+	rateLimitPolicyOpt = "rate-limit-policy"
+)
+
+// RateLimitConfig is the configuration of a RateLimitedLogger.
+type RateLimitConfig struct {
+	// Rate is the number of messages per second which are let through.
+	Rate float64
+	// Burst is the maximum number of messages which are let through at
+	// once.
+	Burst int
+	// Policy is the policy applied to messages exceeding the rate limit.
+	Policy RateLimitPolicy
+}
+
+// ParseRateLimitConfig parses the "rate-limit", "rate-limit-burst"
+// and "rate-limit-policy" log options. It returns a nil config if rate
+// limiting is not enabled.
+func ParseRateLimitConfig(cfg map[string]string) (*RateLimitConfig, error) {
+	s, ok := cfg[rateLimitOpt]
+	if !ok {
+		for _, key := range []string{rateLimitBurstOpt, rateLimitPolicyOpt} {
+			if _, ok := cfg[key]; ok {
+				return nil, fmt.Errorf("logger: %s option is only supported with the %s option", key, rateLimitOpt)
+			}
+		}
+		return nil, nil
+	}
+
+	limit, err := strconv.ParseFloat(s, 64)
+	if err != nil || limit <= 0 || math.IsInf(limit, 0) {
+		return nil, fmt.Errorf("logger: invalid value for %s: %q: must be a positive number of messages per second", rateLimitOpt, s)
+	}
+	c := &RateLimitConfig{
+		Rate:   limit,
+		Burst:  int(math.Ceil(limit)),
+		Policy: RateLimitPolicyDrop,
+	}
+
+	if s, ok := cfg[rateLimitBurstOpt]; ok {
+		c.Burst, err = strconv.Atoi(s)
+		if err != nil || c.Burst < 1 {
+			return nil, fmt.Errorf("logger: invalid value for %s: %q: must be a positive integer", rateLimitBurstOpt, s)
+		}
+	}
+
+	if s, ok := cfg[rateLimitPolicyOpt]; ok {
+		switch p := RateLimitPolicy(s); p {
+		case RateLimitPolicyDrop, RateLimitPolicySample:
+			c.Policy = p
+		default:
+			return nil, fmt.Errorf("logger: invalid value for %s: %q: must be %s or %s", rateLimitPolicyOpt, s, RateLimitPolicyDrop, RateLimitPolicySample)
+		}
+	}
+	return c, nil
+}
+
+// RateLimitedLogger is a Logger which limits the rate of the messages
+// forwarded to the wrapped logger. Messages exceeding the limit are dropped
+// or sampled, and the number of messages dropped from a source is reported
+// in a message sent before the next message forwarded from that source.
+// Partial messages are forwarded or dropped as a whole.
+type RateLimitedLogger struct {
+	l       Logger
+	logInfo Info
+	limiter *rate.Limiter
+	policy  RateLimitPolicy
+
+	mu       sync.Mutex
+	exceeded uint64            // number of messages exceeding the limit, for sampling
+	dropped  map[string]uint64 // number of messages dropped since the last report, per source
+	partials map[string]bool   // whether the partial messages in progress are forwarded, by ID
+}
+
+type rateLimitedWithReader struct {
+	*RateLimitedLogger
+}
+
+func (r *rateLimitedWithReader) ReadLogs(cfg ReadConfig) *LogWatcher {
+	reader, ok := r.l.(LogReader)
+	if !ok {
+		// something is wrong if we get here
+		panic("expected log reader")
+	}
+	return reader.ReadLogs(cfg)
+}
+
+func (r *rateLimitedWithReader) SupportsReadFilters() bool {
+	return SupportsReadFilters(r.l.(LogReader))
+}
+
+// NewRateLimitedLogger creates a new Logger limiting the rate of the messages
+// forwarded to the passed in logger.
+func NewRateLimitedLogger(driver Logger, logInfo Info, cfg RateLimitConfig) Logger {
+	l := &RateLimitedLogger{
+		l:        driver,
+		logInfo:  logInfo,
+		limiter:  rate.NewLimiter(rate.Limit(cfg.Rate), cfg.Burst),
+		policy:   cfg.Policy,
+		dropped:  make(map[string]uint64),
+		partials: make(map[string]bool),
+	}
+	if _, ok := driver.(LogReader); ok {
+		return &rateLimitedWithReader{l}
+	}
+	return l
+}
+
+// Log forwards the message to the underlying logger, unless the rate limit is
+// exceeded.
+func (r *RateLimitedLogger) Log(msg *Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.allowMessage(msg) {
+		PutMessage(msg)
+		return nil
+	}
+
+	if n := r.dropped[msg.Source]; n > 0 {
+		delete(r.dropped, msg.Source)
+		if err := r.l.Log(droppedMessage(msg.Source, n, msg.Timestamp)); err != nil {
+			PutMessage(msg)
+			return err
+		}
+	}
+	return r.l.Log(msg)
+}
+
+// allowMessage returns whether the message must be forwarded. The decision
+// is taken on the first part of a partial message, and applied to all of its
+// parts.
+func (r *RateLimitedLogger) allowMessage(msg *Message) bool {
+	if msg.PLogMetaData == nil {
+		return r.allowCounted(msg.Source)
+	}
+
+	id := msg.PLogMetaData.ID
+	allowed, inProgress := r.partials[id]
+	if !inProgress {
+		allowed = r.allowCounted(msg.Source)
+	}
+	if msg.PLogMetaData.Last {
+		delete(r.partials, id)
+	} else {
+		r.partials[id] = allowed
+	}
+	return allowed
+}
+
+// allowCounted returns whether the next message from source must be
+// forwarded, and counts it as dropped otherwise.
+func (r *RateLimitedLogger) allowCounted(source string) bool {
+	if r.allow() {
+		return true
+	}
+	r.dropped[source]++
+	logsDroppedCount.Inc(1)
+	return false
+}
+
+// allow returns whether the next message must be forwarded.
+func (r *RateLimitedLogger) allow() bool {
+	if r.limiter.Allow() {
+		return true
+	}
+	r.exceeded++
+	return r.policy == RateLimitPolicySample && r.exceeded%sampleRate == 0
+}
+
+// droppedMessage returns a message reporting that n messages from source
+// were dropped.
+func droppedMessage(source string, n uint64, ts time.Time) *Message {
+	m := NewMessage()
+	m.Source = source
+	m.Timestamp = ts
+	m.Line = append(m.Line, fmt.Sprintf("%d log messages dropped by rate limiter", n)...)
+	return m
+}
+
+// Name returns the name of the underlying logger
+func (r *RateLimitedLogger) Name() string {
+	return r.l.Name()
+}
+
+// Close reports any messages dropped since the last report and closes the
+// underlying logger.
+func (r *RateLimitedLogger) Close() error {
+	r.mu.Lock()
+	for source, n := range r.dropped {
+		if err := r.l.Log(droppedMessage(source, n, time.Now())); err != nil {
+			logrus.WithField("driver", r.l.Name()).
+				WithField("container", r.logInfo.ContainerID).
+				WithError(err).
+				Errorf("Error writing log message")
+			break
+		}
+	}
+	r.dropped = make(map[string]uint64)
+	r.mu.Unlock()
+	return r.l.Close()
+}
@@ -0,0 +1,142 @@
+package logger // import "github.com/docker/docker/daemon/logger"
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/docker/docker/api/types/backend"
+)
+
+func TestParseRateLimitConfig(t *testing.T) {
+	c, err := ParseRateLimitConfig(map[string]string{})
+	if err != nil || c != nil {
+		t.Fatalf("expected no rate limit config, got %+v, %v", c, err)
+	}
+
+	c, err = ParseRateLimitConfig(map[string]string{"rate-limit": "2.5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Rate != 2.5 || c.Burst != 3 || c.Policy != RateLimitPolicyDrop {
+		t.Fatalf("unexpected default rate limit config: %+v", c)
+	}
+
+	c, err = ParseRateLimitConfig(map[string]string{"rate-limit": "100", "rate-limit-burst": "500", "rate-limit-policy": "sample"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Rate != 100 || c.Burst != 500 || c.Policy != RateLimitPolicySample {
+		t.Fatalf("unexpected rate limit config: %+v", c)
+	}
+
+	for _, cfg := range []map[string]string{
+		{"rate-limit-burst": "10"},
+		{"rate-limit-policy": "drop"},
+		{"rate-limit": "0"},
+		{"rate-limit": "fast"},
+		{"rate-limit": "10", "rate-limit-burst": "0"},
+		{"rate-limit": "10", "rate-limit-policy": "block"},
+	} {
+		if _, err := ParseRateLimitConfig(cfg); err == nil {
+			t.Fatalf("expected an error for %v", cfg)
+		}
+	}
+}
+
+func TestRateLimitedLoggerDrop(t *testing.T) {
+	mockLog := &mockLogger{make(chan *Message, 100)}
+	// a tiny rate so that only the burst gets through during the test
+	l := NewRateLimitedLogger(mockLog, Info{}, RateLimitConfig{Rate: 0.001, Burst: 2, Policy: RateLimitPolicyDrop})
+
+	for i := 0; i < 10; i++ {
+		if err := l.Log(&Message{Source: "stdout", Line: []byte(strconv.Itoa(i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	close(mockLog.c)
+
+	var lines []string
+	for msg := range mockLog.c {
+		lines = append(lines, msg.Source+": "+string(msg.Line))
+	}
+	expected := []string{"stdout: 0", "stdout: 1", "stdout: 8 log messages dropped by rate limiter"}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected, lines)
+		}
+	}
+}
+
+func TestRateLimitedLoggerSample(t *testing.T) {
+	mockLog := &mockLogger{make(chan *Message, 100)}
+	l := NewRateLimitedLogger(mockLog, Info{}, RateLimitConfig{Rate: 0.001, Burst: 1, Policy: RateLimitPolicySample})
+
+	for i := 0; i < 2*sampleRate+1; i++ {
+		if err := l.Log(&Message{Source: "stderr", Line: []byte(strconv.Itoa(i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(mockLog.c)
+
+	var lines []string
+	for msg := range mockLog.c {
+		lines = append(lines, string(msg.Line))
+	}
+	expected := []string{
+		"0",
+		"9 log messages dropped by rate limiter", "10",
+		"9 log messages dropped by rate limiter", "20",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected, lines)
+		}
+	}
+}
+
+func TestRateLimitedLoggerPartialMessages(t *testing.T) {
+	mockLog := &mockLogger{make(chan *Message, 100)}
+	l := NewRateLimitedLogger(mockLog, Info{}, RateLimitConfig{Rate: 0.001, Burst: 1, Policy: RateLimitPolicyDrop})
+
+	// the first message is let through with all its parts, the second one
+	// is dropped as a whole
+	for _, id := range []string{"a", "b"} {
+		for i := 1; i <= 3; i++ {
+			msg := &Message{
+				Source:       "stdout",
+				Line:         []byte(id + strconv.Itoa(i)),
+				PLogMetaData: &backend.PartialLogMetaData{ID: id, Ordinal: i, Last: i == 3},
+			}
+			if err := l.Log(msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	close(mockLog.c)
+
+	var lines []string
+	for msg := range mockLog.c {
+		lines = append(lines, string(msg.Line))
+	}
+	expected := []string{"a1", "a2", "a3", "1 log messages dropped by rate limiter"}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %q, got %q", expected, lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Fatalf("expected %q, got %q", expected, lines)
+		}
+	}
+}
@@ -151,6 +151,28 @@ func (nopLogger) Name() string       { return "nopLogger" }
 func (nopLogger) Close() error       { return nil }
 func (nopLogger) Log(*Message) error { return nil }
 
+type readerLogger struct {
+	nopLogger
+	filters bool
+}
+
+func (readerLogger) ReadLogs(ReadConfig) *LogWatcher { return NewLogWatcher() }
+func (l readerLogger) SupportsReadFilters() bool     { return l.filters }
+
+func TestRingLoggerSupportsReadFilters(t *testing.T) {
+	for _, filters := range []bool{true, false} {
+		l := NewRingLogger(readerLogger{filters: filters}, Info{}, -1)
+		reader, ok := l.(LogReader)
+		if !ok {
+			t.Fatal("expected the ring logger to be a log reader")
+		}
+		if SupportsReadFilters(reader) != filters {
+			t.Fatalf("expected SupportsReadFilters to be %v", filters)
+		}
+		l.Close()
+	}
+}
+
 func BenchmarkRingLoggerThroughputNoReceiver(b *testing.B) {
 	mockLog := &mockLogger{make(chan *Message)}
 	defer mockLog.Close()
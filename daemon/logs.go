@@ -2,7 +2,9 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"context"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -94,6 +96,12 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 		Tail:   tailLines,
 		Follow: follow,
 	}
+	if err := setLogReadFilters(&readConfig, config); err != nil {
+		return nil, false, err
+	}
+	if (len(readConfig.Attrs) > 0 || readConfig.Grep != nil) && !logger.SupportsReadFilters(logReader) {
+		return nil, false, errdefs.InvalidParameter(errors.Errorf("the %s logging driver does not support filtering logs by attr or grep", container.HostConfig.LogConfig.Type))
+	}
 
 	logs := logReader.ReadLogs(readConfig)
 
@@ -159,6 +167,51 @@ func (daemon *Daemon) ContainerLogs(ctx context.Context, containerName string, c
 	return messageChan, container.Config.Tty, nil
 }
 
+var acceptedLogFilterTags = map[string]bool{
+	"attr": true,
+	"grep": true,
+}
+
+// setLogReadFilters restricts the messages read with the given config to the
+// streams and filters selected in the logs options.
+func setLogReadFilters(readConfig *logger.ReadConfig, config *types.ContainerLogsOptions) error {
+	if config.ShowStdout != config.ShowStderr {
+		if config.ShowStdout {
+			readConfig.Sources = []string{"stdout"}
+		} else {
+			readConfig.Sources = []string{"stderr"}
+		}
+	}
+
+	if err := config.Filters.Validate(acceptedLogFilterTags); err != nil {
+		return err
+	}
+
+	for _, attr := range config.Filters.Get("attr") {
+		kv := strings.SplitN(attr, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return errdefs.InvalidParameter(errors.Errorf("invalid attr filter %q: must be of the form key=value", attr))
+		}
+		if readConfig.Attrs == nil {
+			readConfig.Attrs = make(map[string]string)
+		}
+		readConfig.Attrs[kv[0]] = kv[1]
+	}
+
+	switch exprs := config.Filters.Get("grep"); len(exprs) {
+	case 0:
+	case 1:
+		re, err := regexp.Compile(exprs[0])
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrap(err, "invalid grep filter"))
+		}
+		readConfig.Grep = re
+	default:
+		return errdefs.InvalidParameter(errors.New("only one grep filter is allowed"))
+	}
+	return nil
+}
+
 func (daemon *Daemon) getLogger(container *container.Container) (l logger.Logger, created bool, err error) {
 	container.Lock()
 	if container.State.Running {
@@ -3,7 +3,13 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"testing"
 
+	"github.com/docker/docker/api/types"
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/daemon/logger"
+	"github.com/docker/docker/errdefs"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
 )
 
 func TestMergeAndVerifyLogConfigNilConfig(t *testing.T) {
@@ -13,3 +19,35 @@ func TestMergeAndVerifyLogConfigNilConfig(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestSetLogReadFilters(t *testing.T) {
+	var readConfig logger.ReadConfig
+	err := setLogReadFilters(&readConfig, &types.ContainerLogsOptions{
+		ShowStderr: true,
+		Filters: filters.NewArgs(
+			filters.Arg("attr", "app=web"),
+			filters.Arg("attr", "env=prod=eu"),
+			filters.Arg("grep", "^ERROR"),
+		),
+	})
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(readConfig.Sources, []string{"stderr"}))
+	assert.Check(t, is.DeepEqual(readConfig.Attrs, map[string]string{"app": "web", "env": "prod=eu"}))
+	assert.Assert(t, readConfig.Grep != nil)
+	assert.Check(t, is.Equal(readConfig.Grep.String(), "^ERROR"))
+
+	readConfig = logger.ReadConfig{}
+	err = setLogReadFilters(&readConfig, &types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(readConfig.Sources, 0))
+
+	for _, f := range []filters.Args{
+		filters.NewArgs(filters.Arg("label", "foo")),
+		filters.NewArgs(filters.Arg("attr", "app")),
+		filters.NewArgs(filters.Arg("grep", "(")),
+		filters.NewArgs(filters.Arg("grep", "foo"), filters.Arg("grep", "bar")),
+	} {
+		err := setLogReadFilters(&logger.ReadConfig{}, &types.ContainerLogsOptions{ShowStdout: true, Filters: f})
+		assert.Check(t, errdefs.IsInvalidParameter(err), "%v", err)
+	}
+}
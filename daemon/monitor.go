@@ -39,6 +39,10 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 			return errors.New("received StateOOM from libcontainerd on Windows. This should never happen")
 		}
 
+		// collected before locking the container, as reading its stats
+		// locks it
+		attributes := daemon.oomAttributes(c)
+
 		c.Lock()
 		defer c.Unlock()
 		daemon.updateHealthMonitor(c)
@@ -46,7 +50,7 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 			return err
 		}
 
-		daemon.LogContainerEvent(c, "oom")
+		daemon.LogContainerEventWithAttributes(c, "oom", attributes)
 	case libcontainerdtypes.EventExit:
 		if int(ei.Pid) == c.Pid {
 			c.Lock()
@@ -64,13 +68,26 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 				ExitedAt:  ei.ExitedAt,
 				OOMKilled: ei.OOMKilled,
 			}
-			restart, wait, err := c.RestartManager().ShouldRestart(ei.ExitCode, daemon.IsShuttingDown() || c.HasBeenManuallyStopped, time.Since(c.StartedAt))
+			var (
+				restart bool
+				wait    chan error
+			)
+			if c.UnhealthyRestart && !daemon.IsShuttingDown() {
+				restart, wait, err = c.RestartManager().ShouldRestartUnhealthy(time.Since(c.StartedAt))
+			} else {
+				restart, wait, err = c.RestartManager().ShouldRestart(ei.ExitCode, daemon.IsShuttingDown() || c.HasBeenManuallyStopped, time.Since(c.StartedAt))
+			}
+			c.UnhealthyRestart = false
 			if err == nil && restart {
 				c.RestartCount++
 				c.SetRestarting(&exitStatus)
+				c.RestartDelay = c.RestartManager().Delay()
+				c.RestartTimes = c.RestartManager().Restarts()
 			} else {
 				if ei.Error != nil {
 					c.SetError(ei.Error)
+				} else if err == restartmanager.ErrRestartLimitReached {
+					c.SetError(err)
 				}
 				c.SetStopped(&exitStatus)
 				defer daemon.autoRemove(c)
@@ -159,6 +176,7 @@ func (daemon *Daemon) ProcessEvent(id string, e libcontainerdtypes.EventType, ei
 			if err := c.CheckpointTo(daemon.containersReplica); err != nil {
 				return err
 			}
+			daemon.statsCollector.Track(c)
 			daemon.LogContainerEvent(c, "start")
 		}
 
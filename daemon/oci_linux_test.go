@@ -1,11 +1,14 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	coci "github.com/containerd/containerd/oci"
+	"github.com/docker/docker/api/types/blkiodev"
 	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/config"
@@ -13,6 +16,7 @@ import (
 	"github.com/docker/docker/pkg/containerfs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/libnetwork"
+	"github.com/opencontainers/runtime-spec/specs-go"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 )
@@ -148,3 +152,44 @@ func TestGetSourceMount(t *testing.T) {
 	_, _, err = getSourceMount(cwd)
 	assert.NilError(t, err)
 }
+
+// TestWithResources checks that the resources of the container are set in the
+// spec. On hosts using cgroup v2, the runtime writes them to the files of the
+// unified hierarchy, such as memory.max, cpu.max, io.max and pids.max.
+func TestWithResources(t *testing.T) {
+	pids := int64(100)
+	c := &container.Container{
+		HostConfig: &containertypes.HostConfig{
+			Resources: containertypes.Resources{
+				Memory:             512 * 1024 * 1024,
+				MemorySwap:         1024 * 1024 * 1024,
+				MemoryReservation:  256 * 1024 * 1024,
+				CPUShares:          512,
+				CPUPeriod:          100000,
+				CPUQuota:           50000,
+				CpusetCpus:         "0-1",
+				BlkioWeight:        500,
+				BlkioDeviceReadBps: []*blkiodev.ThrottleDevice{{Path: "/dev/null", Rate: 1024}},
+				PidsLimit:          &pids,
+			},
+		},
+	}
+	s := &coci.Spec{Linux: &specs.Linux{}}
+	assert.NilError(t, WithResources(c)(context.Background(), nil, nil, s))
+
+	r := s.Linux.Resources
+	assert.Check(t, is.Equal(*r.Memory.Limit, int64(512*1024*1024)))
+	assert.Check(t, is.Equal(*r.Memory.Swap, int64(1024*1024*1024)))
+	assert.Check(t, is.Equal(*r.Memory.Reservation, int64(256*1024*1024)))
+	assert.Check(t, is.Equal(*r.CPU.Shares, uint64(512)))
+	assert.Check(t, is.Equal(*r.CPU.Period, uint64(100000)))
+	assert.Check(t, is.Equal(*r.CPU.Quota, int64(50000)))
+	assert.Check(t, is.Equal(r.CPU.Cpus, "0-1"))
+	assert.Check(t, is.Equal(*r.BlockIO.Weight, uint16(500)))
+	assert.Assert(t, is.Len(r.BlockIO.ThrottleReadBpsDevice, 1))
+	readBps := r.BlockIO.ThrottleReadBpsDevice[0]
+	assert.Check(t, is.Equal(readBps.Major, int64(1)))
+	assert.Check(t, is.Equal(readBps.Minor, int64(3)))
+	assert.Check(t, is.Equal(readBps.Rate, uint64(1024)))
+	assert.Check(t, is.Equal(r.Pids.Limit, pids))
+}
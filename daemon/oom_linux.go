@@ -0,0 +1,164 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/container"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// kmsgReadTimeout and kmsgMaxReadSize bound the time spent, and the size
+	// read, looking for the process killed by the OOM killer in the kernel
+	// log, as the oom event is not published until then.
+	kmsgReadTimeout = 100 * time.Millisecond
+	kmsgMaxReadSize = 1024 * 1024
+)
+
+// oomAttributes returns the attributes of the oom event of a container: the
+// process killed by the OOM killer, as reported in the kernel log, and the
+// memory usage of the container when it was killed. The attributes which
+// cannot be read are omitted.
+func (daemon *Daemon) oomAttributes(c *container.Container) map[string]string {
+	attributes := make(map[string]string)
+	if pid, command, ok := findOOMKill(daemon.memcgMatcher(c)); ok {
+		attributes["pid"] = strconv.Itoa(pid)
+		attributes["command"] = command
+	}
+	if s, err := daemon.stats(c); err == nil {
+		attributes["memoryUsage"] = strconv.FormatUint(s.MemoryStats.Usage, 10)
+		if s.MemoryStats.MaxUsage > 0 {
+			attributes["memoryMaxUsage"] = strconv.FormatUint(s.MemoryStats.MaxUsage, 10)
+		}
+		if s.MemoryStats.Limit > 0 {
+			attributes["memoryLimit"] = strconv.FormatUint(s.MemoryStats.Limit, 10)
+		}
+	}
+	return attributes
+}
+
+// memcgMatcher returns a function which returns whether a memory cgroup
+// path is the cgroup of the container, as set by WithCgroups. With the
+// systemd cgroup driver, the path of the slice depends on its expansion by
+// systemd, so only the scope of the container is matched.
+func (daemon *Daemon) memcgMatcher(c *container.Container) func(memcg string) bool {
+	if UsingSystemd(daemon.configStore) {
+		scope := "docker-" + c.ID + ".scope"
+		return func(memcg string) bool {
+			return path.Base(memcg) == scope
+		}
+	}
+	parent := "/docker"
+	if c.HostConfig.CgroupParent != "" {
+		parent = c.HostConfig.CgroupParent
+	} else if daemon.configStore.CgroupParent != "" {
+		parent = daemon.configStore.CgroupParent
+	}
+	cgroupPath := path.Join("/", parent, c.ID)
+	return func(memcg string) bool {
+		return memcg == cgroupPath
+	}
+}
+
+// findOOMKill returns the last process killed by the OOM killer in the
+// kernel log whose memory cgroup is matched by match. The read of the log is
+// bounded by kmsgReadTimeout and kmsgMaxReadSize.
+func findOOMKill(match func(memcg string) bool) (pid int, command string, ok bool) {
+	fd, err := unix.Open("/dev/kmsg", unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, "", false
+	}
+	defer unix.Close(fd)
+
+	// each read returns one record of the log, until EAGAIN is returned at
+	// the end of the log
+	var (
+		buf      = make([]byte, 8192)
+		size     int
+		deadline = time.Now().Add(kmsgReadTimeout)
+	)
+	for size < kmsgMaxReadSize && time.Now().Before(deadline) {
+		n, err := unix.Read(fd, buf)
+		if err == unix.EPIPE {
+			// the next record was overwritten while reading the log
+			continue
+		}
+		if err != nil || n <= 0 {
+			break
+		}
+		size += n
+		if p, cmd, found := parseOOMKill(string(buf[:n]), match); found {
+			pid, command, ok = p, cmd, true
+		}
+	}
+	return pid, command, ok
+}
+
+// parseOOMKill parses a record of the kernel log reporting a process killed
+// by the OOM killer, of the form
+// "6,1234,5678,-;oom-kill:constraint=CONSTRAINT_MEMCG,...,task_memcg=/docker/<id>,task=<command>,pid=<pid>,uid=0".
+// ok is false if the record does not report a process whose memory cgroup is
+// matched by match.
+func parseOOMKill(record string, match func(memcg string) bool) (pid int, command string, ok bool) {
+	if i := strings.IndexByte(record, ';'); i >= 0 {
+		record = record[i+1:]
+	}
+	record = strings.TrimSpace(record)
+	if !strings.HasPrefix(record, "oom-kill:") {
+		return 0, "", false
+	}
+
+	var memcg string
+	for _, field := range strings.Split(strings.TrimPrefix(record, "oom-kill:"), ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "task_memcg":
+			memcg = kv[1]
+		case "task":
+			command = kv[1]
+		case "pid":
+			pid, _ = strconv.Atoi(kv[1])
+		}
+	}
+	if !match(memcg) || pid == 0 {
+		return 0, "", false
+	}
+	return pid, command, true
+}
+
+// readOOMKills returns the number of processes killed by the OOM killer in
+// the cgroup v1 memory cgroup of the process.
+func readOOMKills(pid int) (uint64, error) {
+	groups, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return 0, err
+	}
+	group, ok := groups["memory"]
+	if !ok {
+		return 0, errors.New("no memory cgroup")
+	}
+	mountPoint, err := cgroups.FindCgroupMountpoint("", "memory")
+	if err != nil {
+		return 0, err
+	}
+
+	values, err := readCgroupKeyValues(filepath.Join(mountPoint, group, "memory.oom_control"))
+	if err != nil {
+		return 0, err
+	}
+	kills, ok := values["oom_kill"]
+	if !ok {
+		return 0, errors.New("no oom_kill counter in memory.oom_control")
+	}
+	return kills, nil
+}
@@ -0,0 +1,48 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/config"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestParseOOMKill(t *testing.T) {
+	const id = "3b5a8f2c1d9e"
+	record := "6,1234,5678901,-;oom-kill:constraint=CONSTRAINT_MEMCG,nodemask=(null),cpuset=" + id +
+		",mems_allowed=0,oom_memcg=/docker/" + id + ",task_memcg=/docker/" + id + ",task=stress,pid=4321,uid=0\n"
+	match := func(memcg string) bool { return memcg == "/docker/"+id }
+
+	pid, command, ok := parseOOMKill(record, match)
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal(pid, 4321))
+	assert.Check(t, is.Equal(command, "stress"))
+
+	_, _, ok = parseOOMKill(record, func(string) bool { return false })
+	assert.Check(t, !ok)
+	_, _, ok = parseOOMKill("6,1235,5678902,-;Memory cgroup out of memory: Killed process 4321 (stress)\n", match)
+	assert.Check(t, !ok)
+}
+
+func TestMemcgMatcher(t *testing.T) {
+	const id = "3b5a8f2c1d9e"
+	c := &container.Container{ID: id, HostConfig: &containertypes.HostConfig{}}
+	daemon := &Daemon{configStore: &config.Config{}}
+
+	match := daemon.memcgMatcher(c)
+	assert.Check(t, match("/docker/"+id))
+	assert.Check(t, !match("/docker/"+id+"/nested"))
+	assert.Check(t, !match("/docker/other"+id))
+	assert.Check(t, !match("/custom/"+id))
+
+	c.HostConfig.CgroupParent = "custom"
+	assert.Check(t, daemon.memcgMatcher(c)("/custom/"+id))
+
+	daemon.configStore.ExecOptions = []string{"native.cgroupdriver=systemd"}
+	match = daemon.memcgMatcher(c)
+	assert.Check(t, match("/system.slice/docker-"+id+".scope"))
+	assert.Check(t, !match("/system.slice/docker-other"+id+".scope"))
+}
@@ -0,0 +1,9 @@
+// +build !linux
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import "github.com/docker/docker/container"
+
+func (daemon *Daemon) oomAttributes(c *container.Container) map[string]string {
+	return make(map[string]string)
+}
@@ -15,7 +15,7 @@ import (
 )
 
 // ContainerStart starts a container.
-func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig, checkpoint string, checkpointDir string) error {
+func (daemon *Daemon) ContainerStart(ctx context.Context, name string, hostConfig *containertypes.HostConfig, checkpoint string, checkpointDir string) error {
 	if checkpoint != "" && !daemon.HasExperimental() {
 		return errdefs.InvalidParameter(errors.New("checkpoint is only supported in experimental mode"))
 	}
@@ -92,6 +92,9 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 			return errdefs.InvalidParameter(err)
 		}
 	}
+	if err := daemon.startDependencies(ctx, container); err != nil {
+		return err
+	}
 	return daemon.containerStart(container, checkpoint, checkpointDir, true)
 }
 
@@ -218,6 +221,7 @@ func (daemon *Daemon) containerStart(container *container.Container, checkpoint
 			Errorf("failed to store container")
 	}
 
+	daemon.statsCollector.Track(container)
 	daemon.LogContainerEvent(container, "start")
 	containerActions.WithValues("start").UpdateSince(start)
 
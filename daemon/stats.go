@@ -4,14 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"runtime"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/backend"
+	timetypes "github.com/docker/docker/api/types/time"
 	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/api/types/versions/v1p20"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/ioutils"
 )
 
@@ -130,6 +133,52 @@ func (daemon *Daemon) ContainerStats(ctx context.Context, prefixOrName string, c
 	}
 }
 
+// ContainerStatsHistory returns the stats history of a container, which may
+// have been removed, in which case it must be referred to by its full ID.
+func (daemon *Daemon) ContainerStatsHistory(ctx context.Context, prefixOrName string, config *backend.ContainerStatsHistoryConfig) (*types.StatsHistory, error) {
+	if !daemon.configStore.StatsHistory.Enabled {
+		return nil, errdefs.Unavailable(errors.New("the stats history is not enabled"))
+	}
+
+	var since, until time.Time
+	if config.Since != "" {
+		s, n, err := timetypes.ParseTimestamps(config.Since, 0)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		since = time.Unix(s, n)
+	}
+	if config.Until != "" && config.Until != "0" {
+		s, n, err := timetypes.ParseTimestamps(config.Until, 0)
+		if err != nil {
+			return nil, errdefs.InvalidParameter(err)
+		}
+		until = time.Unix(s, n)
+	}
+	if !until.IsZero() && until.Before(since) {
+		return nil, errdefs.InvalidParameter(errors.New("since must be before until"))
+	}
+	var step time.Duration
+	if config.Step != "" {
+		var err error
+		if step, err = time.ParseDuration(config.Step); err != nil || step < 0 {
+			return nil, errdefs.InvalidParameter(fmt.Errorf("invalid step: %q", config.Step))
+		}
+	}
+
+	id := prefixOrName
+	if c, err := daemon.GetContainer(prefixOrName); err == nil {
+		id = c.ID
+	} else if !errdefs.IsNotFound(err) {
+		return nil, err
+	}
+	history, ok := daemon.statsCollector.History(id, since, until, step)
+	if !ok {
+		return nil, errdefs.NotFound(fmt.Errorf("no stats history for container %s", prefixOrName))
+	}
+	return history, nil
+}
+
 func (daemon *Daemon) subscribeToContainerStats(c *container.Container) chan interface{} {
 	return daemon.statsCollector.Collect(c)
 }
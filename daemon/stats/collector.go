@@ -18,6 +18,7 @@ type Collector struct {
 	interval   time.Duration
 	publishers map[*container.Container]*pubsub.Publisher
 	bufReader  *bufio.Reader
+	history    *history
 
 	// The following fields are not set on Windows currently.
 	clockTicksPerSecond uint64
@@ -37,6 +38,33 @@ func NewCollector(supervisor supervisor, interval time.Duration) *Collector {
 	return s
 }
 
+// EnableHistory makes the collector keep up to maxSamples samples of the
+// stats of each tracked container, taken at most once per interval. The
+// samples of a container are kept for the retention time after it exited.
+// It must be called before Run.
+func (s *Collector) EnableHistory(maxSamples int, interval, retention time.Duration) {
+	s.history = newHistory(maxSamples, interval, retention)
+}
+
+// Track adds the container to the stats history, if enabled, until it exits.
+func (s *Collector) Track(c *container.Container) {
+	if s.history != nil {
+		s.history.track(c)
+	}
+}
+
+// History returns the stats history of the container with the given ID
+// between since and until, aggregated over steps of the given duration. A
+// zero since or until does not bound the samples, and a zero step returns
+// every sample. false is returned if the history is not enabled, or if it
+// has no samples of the container.
+func (s *Collector) History(id string, since, until time.Time, step time.Duration) (*types.StatsHistory, bool) {
+	if s.history == nil {
+		return nil, false
+	}
+	return s.history.get(id, since, until, step)
+}
+
 type supervisor interface {
 	// GetContainerStats collects all the stats related to a container
 	GetContainerStats(container *container.Container) (*types.StatsJSON, error)
@@ -104,6 +132,15 @@ func (s *Collector) Run() {
 			// copy pointers here to release the lock ASAP
 			pairs = append(pairs, publishersPair{container, publisher})
 		}
+		if s.history != nil {
+			// the containers of the history which have no subscriber are
+			// only collected once per interval of the history
+			for _, c := range s.history.due(time.Now()) {
+				if _, exists := s.publishers[c]; !exists {
+					pairs = append(pairs, publishersPair{container: c})
+				}
+			}
+		}
 		s.m.Unlock()
 		if len(pairs) == 0 {
 			continue
@@ -132,21 +169,33 @@ func (s *Collector) Run() {
 				stats.CPUStats.SystemUsage = systemUsage
 				stats.CPUStats.OnlineCPUs = onlineCPUs
 
-				pair.publisher.Publish(*stats)
+				if s.history != nil {
+					s.history.add(pair.container, stats)
+				}
+				if pair.publisher != nil {
+					pair.publisher.Publish(*stats)
+				}
 
 			case notRunningErr, notFoundErr:
+				if s.history != nil {
+					s.history.exited(pair.container, time.Now())
+				}
 				// publish empty stats containing only name and ID if not running or not found
-				pair.publisher.Publish(types.StatsJSON{
-					Name: pair.container.Name,
-					ID:   pair.container.ID,
-				})
+				if pair.publisher != nil {
+					pair.publisher.Publish(types.StatsJSON{
+						Name: pair.container.Name,
+						ID:   pair.container.ID,
+					})
+				}
 
 			default:
 				logrus.Errorf("collecting stats for %s: %v", pair.container.ID, err)
-				pair.publisher.Publish(types.StatsJSON{
-					Name: pair.container.Name,
-					ID:   pair.container.ID,
-				})
+				if pair.publisher != nil {
+					pair.publisher.Publish(types.StatsJSON{
+						Name: pair.container.Name,
+						ID:   pair.container.ID,
+					})
+				}
 			}
 		}
 	}
@@ -0,0 +1,271 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+)
+
+// sample is a sample of the resource usage of a container in its history.
+type sample struct {
+	time        time.Time
+	cpuUsage    uint64
+	systemUsage uint64
+	onlineCPUs  uint32
+	memoryUsage uint64
+	memoryLimit uint64
+	networkRx   uint64
+	networkTx   uint64
+	blockRead   uint64
+	blockWrite  uint64
+	pids        uint64
+}
+
+func newSample(stats *types.StatsJSON) sample {
+	s := sample{
+		time:        stats.Read,
+		cpuUsage:    stats.CPUStats.CPUUsage.TotalUsage,
+		systemUsage: stats.CPUStats.SystemUsage,
+		onlineCPUs:  stats.CPUStats.OnlineCPUs,
+		memoryUsage: stats.MemoryStats.Usage,
+		memoryLimit: stats.MemoryStats.Limit,
+		blockRead:   stats.StorageStats.ReadSizeBytes,
+		blockWrite:  stats.StorageStats.WriteSizeBytes,
+		pids:        stats.PidsStats.Current,
+	}
+	for _, n := range stats.Networks {
+		s.networkRx += n.RxBytes
+		s.networkTx += n.TxBytes
+	}
+	for _, e := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch {
+		case strings.EqualFold(e.Op, "read"):
+			s.blockRead += e.Value
+		case strings.EqualFold(e.Op, "write"):
+			s.blockWrite += e.Value
+		}
+	}
+	return s
+}
+
+// cpuPercent returns the CPU usage between the previous sample and s, where
+// 100 is the usage of a single CPU, and whether it can be computed.
+func (s sample) cpuPercent(prev sample) (float64, bool) {
+	if s.systemUsage <= prev.systemUsage || s.cpuUsage < prev.cpuUsage {
+		return 0, false
+	}
+	cpuDelta := float64(s.cpuUsage - prev.cpuUsage)
+	systemDelta := float64(s.systemUsage - prev.systemUsage)
+	return cpuDelta / systemDelta * float64(s.onlineCPUs) * 100, true
+}
+
+// containerHistory is the ring of the samples of a container.
+type containerHistory struct {
+	container *container.Container
+	name      string
+	samples   []sample
+	next      int       // index of the next sample in the ring
+	exited    time.Time // zero while the container runs
+}
+
+func (h *containerHistory) add(s sample, maxSamples int) {
+	if len(h.samples) < maxSamples {
+		h.samples = append(h.samples, s)
+		return
+	}
+	h.samples[h.next] = s
+	h.next = (h.next + 1) % maxSamples
+}
+
+func (h *containerHistory) last() (sample, bool) {
+	if len(h.samples) == 0 {
+		return sample{}, false
+	}
+	i := h.next - 1
+	if i < 0 {
+		i = len(h.samples) - 1
+	}
+	return h.samples[i], true
+}
+
+// ordered returns the samples in chronological order.
+func (h *containerHistory) ordered() []sample {
+	out := make([]sample, 0, len(h.samples))
+	out = append(out, h.samples[h.next:]...)
+	return append(out, h.samples[:h.next]...)
+}
+
+// history keeps a bounded number of samples of the resource usage of each
+// tracked container, taken at most once per interval. The samples of a
+// container are kept for the retention time after it exited.
+type history struct {
+	mu         sync.Mutex
+	maxSamples int
+	interval   time.Duration
+	retention  time.Duration
+	containers map[string]*containerHistory // by container ID
+}
+
+func newHistory(maxSamples int, interval, retention time.Duration) *history {
+	return &history{
+		maxSamples: maxSamples,
+		interval:   interval,
+		retention:  retention,
+		containers: make(map[string]*containerHistory),
+	}
+}
+
+// track starts keeping the samples of the container.
+func (h *history) track(c *container.Container) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch, ok := h.containers[c.ID]
+	if !ok {
+		ch = &containerHistory{}
+		h.containers[c.ID] = ch
+	}
+	ch.container = c
+	ch.name = c.Name
+	ch.exited = time.Time{}
+}
+
+// due returns the running containers which have no sample since the last
+// interval, and forgets the containers which exited before the retention
+// time.
+func (h *history) due(now time.Time) []*container.Container {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var out []*container.Container
+	for id, ch := range h.containers {
+		if !ch.exited.IsZero() {
+			if now.Sub(ch.exited) > h.retention {
+				delete(h.containers, id)
+			}
+			continue
+		}
+		if last, ok := ch.last(); !ok || now.Sub(last.time) >= h.interval {
+			out = append(out, ch.container)
+		}
+	}
+	return out
+}
+
+// add adds the stats to the samples of the container, if it is tracked and
+// has no sample since the last interval.
+func (h *history) add(c *container.Container, stats *types.StatsJSON) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch, ok := h.containers[c.ID]
+	if !ok || !ch.exited.IsZero() {
+		return
+	}
+	if last, ok := ch.last(); ok && stats.Read.Sub(last.time) < h.interval {
+		return
+	}
+	ch.add(newSample(stats), h.maxSamples)
+}
+
+// exited records that the container exited, after which its samples are
+// kept for the retention time.
+func (h *history) exited(c *container.Container, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if ch, ok := h.containers[c.ID]; ok && ch.exited.IsZero() {
+		ch.exited = now
+	}
+}
+
+// get returns the samples of the container between since and until,
+// aggregated over steps of the given duration. A zero since or until does
+// not bound the samples, and a zero step returns every sample.
+func (h *history) get(id string, since, until time.Time, step time.Duration) (*types.StatsHistory, bool) {
+	h.mu.Lock()
+	ch, ok := h.containers[id]
+	if !ok {
+		h.mu.Unlock()
+		return nil, false
+	}
+	name := ch.name
+	samples := ch.ordered()
+	h.mu.Unlock()
+
+	return &types.StatsHistory{
+		ID:      id,
+		Name:    name,
+		Step:    step,
+		Samples: aggregate(samples, since, until, step),
+	}, true
+}
+
+// aggregate aggregates the samples between since and until over steps of the
+// given duration, starting at since, or at the first sample if since is zero.
+func aggregate(samples []sample, since, until time.Time, step time.Duration) []types.StatsHistorySample {
+	var (
+		out   []types.StatsHistorySample
+		cur   *types.StatsHistorySample
+		start time.Time
+		cpu   float64
+		cpuN  int
+		mem   uint64
+	)
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		if cpuN > 0 {
+			cur.CPUPercent = cpu / float64(cpuN)
+		}
+		cur.MemoryUsage = mem / uint64(cur.Samples)
+		out = append(out, *cur)
+		cur = nil
+	}
+
+	for i, s := range samples {
+		if (!since.IsZero() && s.time.Before(since)) || (!until.IsZero() && s.time.After(until)) {
+			continue
+		}
+		if cur == nil || step == 0 || !s.time.Before(start.Add(step)) {
+			flush()
+			switch {
+			case step == 0:
+				start = s.time
+			case start.IsZero():
+				start = since
+				if start.IsZero() {
+					start = s.time
+				}
+				fallthrough
+			default:
+				// skip the steps without samples
+				start = start.Add(s.time.Sub(start) / step * step)
+			}
+			cur = &types.StatsHistorySample{Time: start}
+			cpu, cpuN, mem = 0, 0, 0
+		}
+
+		cur.Samples++
+		if i > 0 {
+			if p, ok := s.cpuPercent(samples[i-1]); ok {
+				cpu += p
+				cpuN++
+			}
+		}
+		mem += s.memoryUsage
+		if s.memoryUsage > cur.MemoryMaxUsage {
+			cur.MemoryMaxUsage = s.memoryUsage
+		}
+		if s.pids > cur.Pids {
+			cur.Pids = s.pids
+		}
+		cur.MemoryLimit = s.memoryLimit
+		cur.NetworkRxBytes = s.networkRx
+		cur.NetworkTxBytes = s.networkTx
+		cur.BlockReadBytes = s.blockRead
+		cur.BlockWriteBytes = s.blockWrite
+	}
+	flush()
+	return out
+}
@@ -0,0 +1,105 @@
+package stats // import "github.com/docker/docker/daemon/stats"
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func newStats(t time.Time, cpu, system, memory uint64) *types.StatsJSON {
+	s := &types.StatsJSON{}
+	s.Read = t
+	s.CPUStats.CPUUsage.TotalUsage = cpu
+	s.CPUStats.SystemUsage = system
+	s.CPUStats.OnlineCPUs = 2
+	s.MemoryStats.Usage = memory
+	s.Networks = map[string]types.NetworkStats{"eth0": {RxBytes: memory}}
+	return s
+}
+
+func TestHistoryRing(t *testing.T) {
+	h := newHistory(3, time.Second, time.Minute)
+	c := &container.Container{ID: "id", Name: "/name"}
+	start := time.Unix(1000, 0)
+
+	// samples of containers which are not tracked are ignored
+	h.add(c, newStats(start, 0, 0, 1))
+	_, ok := h.get(c.ID, time.Time{}, time.Time{}, 0)
+	assert.Check(t, !ok)
+
+	h.track(c)
+	for i := 0; i < 5; i++ {
+		h.add(c, newStats(start.Add(time.Duration(i)*time.Second), 0, 0, uint64(i)))
+		// samples within the interval are ignored
+		h.add(c, newStats(start.Add(time.Duration(i)*time.Second+time.Millisecond), 0, 0, 100))
+	}
+	history, ok := h.get(c.ID, time.Time{}, time.Time{}, 0)
+	assert.Assert(t, ok)
+	assert.Check(t, is.Equal(history.Name, "/name"))
+	var memory []uint64
+	for _, s := range history.Samples {
+		memory = append(memory, s.MemoryUsage)
+	}
+	assert.Check(t, is.DeepEqual(memory, []uint64{2, 3, 4}))
+}
+
+func TestHistoryDue(t *testing.T) {
+	h := newHistory(10, time.Second, time.Minute)
+	c := &container.Container{ID: "id"}
+	start := time.Unix(1000, 0)
+
+	h.track(c)
+	assert.Check(t, is.Len(h.due(start), 1))
+	h.add(c, newStats(start, 0, 0, 1))
+	assert.Check(t, is.Len(h.due(start.Add(time.Millisecond)), 0))
+	assert.Check(t, is.Len(h.due(start.Add(time.Second)), 1))
+
+	// exited containers are not collected, and are forgotten after the
+	// retention time
+	h.exited(c, start)
+	assert.Check(t, is.Len(h.due(start.Add(time.Second)), 0))
+	_, ok := h.get(c.ID, time.Time{}, time.Time{}, 0)
+	assert.Check(t, ok)
+	h.due(start.Add(2 * time.Minute))
+	_, ok = h.get(c.ID, time.Time{}, time.Time{}, 0)
+	assert.Check(t, !ok)
+}
+
+func TestHistoryAggregate(t *testing.T) {
+	start := time.Unix(1000, 0)
+	var samples []sample
+	for i := 0; i < 6; i++ {
+		// each sample uses half of a CPU out of 2 since the previous one
+		samples = append(samples, newSample(newStats(start.Add(time.Duration(i)*10*time.Second), uint64(i)*100, uint64(i)*400, uint64(i+1)*10)))
+	}
+
+	out := aggregate(samples, start.Add(10*time.Second), start.Add(40*time.Second), 20*time.Second)
+	assert.Check(t, is.DeepEqual(out, []types.StatsHistorySample{
+		{
+			Time:           start.Add(10 * time.Second),
+			Samples:        2,
+			CPUPercent:     50,
+			MemoryUsage:    25,
+			MemoryMaxUsage: 30,
+			NetworkRxBytes: 30,
+		},
+		{
+			Time:           start.Add(30 * time.Second),
+			Samples:        2,
+			CPUPercent:     50,
+			MemoryUsage:    45,
+			MemoryMaxUsage: 50,
+			NetworkRxBytes: 50,
+		},
+	}))
+
+	// steps without samples are skipped
+	out = aggregate([]sample{samples[0], samples[5]}, time.Time{}, time.Time{}, 20*time.Second)
+	assert.Assert(t, is.Len(out, 2))
+	assert.Check(t, is.Equal(out[0].Time, start))
+	assert.Check(t, is.Equal(out[1].Time, start.Add(40*time.Second)))
+}
@@ -0,0 +1,270 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/pkg/errors"
+)
+
+// cgroup2Stats returns the stats of a running container read from the files
+// of its cgroup in the cgroup v2 unified hierarchy, as the metrics of
+// containerd only cover cgroup v1.
+func (daemon *Daemon) cgroup2Stats(c *container.Container) (*types.StatsJSON, error) {
+	groups, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", c.GetPID()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errNotRunning(c.ID)
+		}
+		return nil, err
+	}
+	group, ok := groups[""]
+	if !ok {
+		return nil, errors.Errorf("container %s is not in a cgroup v2 group", c.ID)
+	}
+
+	s, err := readCgroup2Stats(filepath.Join(sysinfo.UnifiedMountpoint, group))
+	if err != nil {
+		return nil, err
+	}
+	// if the container does not set memory limit, use the machineMemory
+	if s.MemoryStats.Limit > daemon.machineMemory && daemon.machineMemory > 0 {
+		s.MemoryStats.Limit = daemon.machineMemory
+	}
+	return s, nil
+}
+
+// readCgroup2Stats reads the stats of the cgroup v2 group at dir. The stats
+// of the controllers which are not enabled in the group are left empty.
+func readCgroup2Stats(dir string) (*types.StatsJSON, error) {
+	s := &types.StatsJSON{}
+	s.Read = time.Now()
+
+	cpu, err := readCgroupKeyValues(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	s.CPUStats = types.CPUStats{
+		CPUUsage: types.CPUUsage{
+			TotalUsage:        cpu["usage_usec"] * 1000,
+			UsageInKernelmode: cpu["system_usec"] * 1000,
+			UsageInUsermode:   cpu["user_usec"] * 1000,
+		},
+		ThrottlingData: types.ThrottlingData{
+			Periods:          cpu["nr_periods"],
+			ThrottledPeriods: cpu["nr_throttled"],
+			ThrottledTime:    cpu["throttled_usec"] * 1000,
+		},
+	}
+
+	if usage, err := readCgroup2Value(filepath.Join(dir, "memory.current")); err == nil {
+		s.MemoryStats.Usage = usage
+		if s.MemoryStats.Limit, err = readCgroup2Value(filepath.Join(dir, "memory.max")); err != nil {
+			return nil, err
+		}
+		if s.MemoryStats.Stats, err = readCgroupKeyValues(filepath.Join(dir, "memory.stat")); err != nil {
+			return nil, err
+		}
+		events, err := readCgroupKeyValues(filepath.Join(dir, "memory.events"))
+		if err != nil {
+			return nil, err
+		}
+		s.MemoryStats.Failcnt = events["max"]
+		s.MemoryStats.OOMEvents = events["oom"]
+		s.MemoryStats.OOMKills = events["oom_kill"]
+		// memory.peak is only available on recent kernels
+		if peak, err := readCgroup2Value(filepath.Join(dir, "memory.peak")); err == nil {
+			s.MemoryStats.MaxUsage = peak
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if current, err := readCgroup2Value(filepath.Join(dir, "pids.current")); err == nil {
+		s.PidsStats.Current = current
+		limit, err := readCgroup2Value(filepath.Join(dir, "pids.max"))
+		if err != nil {
+			return nil, err
+		}
+		if limit != math.MaxUint64 {
+			s.PidsStats.Limit = limit
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if s.BlkioStats, err = readCgroup2IOStats(filepath.Join(dir, "io.stat")); err != nil {
+		return nil, err
+	}
+
+	pressure := &types.PressureStats{}
+	for file, p := range map[string]**types.Pressure{
+		"cpu.pressure":    &pressure.CPU,
+		"memory.pressure": &pressure.Memory,
+		"io.pressure":     &pressure.IO,
+	} {
+		// the pressure files are only available if PSI is enabled
+		if *p, err = readCgroup2Pressure(filepath.Join(dir, file)); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	if pressure.CPU != nil || pressure.Memory != nil || pressure.IO != nil {
+		s.PressureStats = pressure
+	}
+
+	return s, nil
+}
+
+// readCgroup2Value reads a file of a cgroup v2 group containing a single
+// value, where "max" is read as the maximum value.
+func readCgroup2Value(path string) (uint64, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "max" {
+		return math.MaxUint64, nil
+	}
+	v, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid value in %s", path)
+	}
+	return v, nil
+}
+
+// readCgroupKeyValues reads a flat keyed file of a cgroup, such as
+// cpu.stat, with one "key value" pair per line.
+func readCgroupKeyValues(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value of %s in %s", fields[0], path)
+		}
+		values[fields[0]] = v
+	}
+	return values, scanner.Err()
+}
+
+// readCgroup2IOStats reads the io.stat file of a cgroup v2 group, with one
+// "major:minor key=value..." line per device, into blkio stats.
+func readCgroup2IOStats(path string) (types.BlkioStats, error) {
+	stats := types.BlkioStats{}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var major, minor uint64
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &major, &minor); err != nil {
+			return stats, errors.Wrapf(err, "invalid device %s in %s", fields[0], path)
+		}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return stats, errors.Wrapf(err, "invalid value of %s in %s", kv[0], path)
+			}
+			entry := types.BlkioStatEntry{Major: major, Minor: minor, Value: v}
+			switch kv[0] {
+			case "rbytes":
+				entry.Op = "Read"
+				stats.IoServiceBytesRecursive = append(stats.IoServiceBytesRecursive, entry)
+			case "wbytes":
+				entry.Op = "Write"
+				stats.IoServiceBytesRecursive = append(stats.IoServiceBytesRecursive, entry)
+			case "rios":
+				entry.Op = "Read"
+				stats.IoServicedRecursive = append(stats.IoServicedRecursive, entry)
+			case "wios":
+				entry.Op = "Write"
+				stats.IoServicedRecursive = append(stats.IoServicedRecursive, entry)
+			}
+		}
+	}
+	return stats, scanner.Err()
+}
+
+// readCgroup2Pressure reads a pressure file of a cgroup v2 group, with a
+// "some" line and an optional "full" line of the form
+// "some avg10=0.00 avg60=0.00 avg300=0.00 total=0".
+func readCgroup2Pressure(path string) (*types.Pressure, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pressure := &types.Pressure{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var data types.PressureData
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			var err error
+			switch kv[0] {
+			case "avg10":
+				data.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				data.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				data.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				data.Total, err = strconv.ParseUint(kv[1], 10, 64)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid value of %s in %s", kv[0], path)
+			}
+		}
+		switch fields[0] {
+		case "some":
+			pressure.Some = data
+		case "full":
+			pressure.Full = &data
+		}
+	}
+	return pressure, scanner.Err()
+}
@@ -0,0 +1,84 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestReadCgroup2Stats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup2-stats")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	for name, content := range map[string]string{
+		"cpu.stat":        "usage_usec 3000\nuser_usec 2000\nsystem_usec 1000\nnr_periods 10\nnr_throttled 2\nthrottled_usec 500\n",
+		"memory.current":  "4096\n",
+		"memory.max":      "max\n",
+		"memory.stat":     "anon 1024\nfile 2048\ninactive_file 512\n",
+		"memory.events":   "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n",
+		"pids.current":    "5\n",
+		"pids.max":        "100\n",
+		"io.stat":         "8:0 rbytes=100 wbytes=200 rios=1 wios=2 dbytes=0 dios=0\n",
+		"memory.pressure": "some avg10=1.50 avg60=0.25 avg300=0.00 total=1234\nfull avg10=0.50 avg60=0.00 avg300=0.00 total=567\n",
+		"cpu.pressure":    "some avg10=0.00 avg60=0.00 avg300=0.00 total=42\n",
+	} {
+		assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	s, err := readCgroup2Stats(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(s.CPUStats, types.CPUStats{
+		CPUUsage: types.CPUUsage{
+			TotalUsage:        3000000,
+			UsageInKernelmode: 1000000,
+			UsageInUsermode:   2000000,
+		},
+		ThrottlingData: types.ThrottlingData{
+			Periods:          10,
+			ThrottledPeriods: 2,
+			ThrottledTime:    500000,
+		},
+	}))
+	assert.Check(t, is.Equal(s.MemoryStats.Usage, uint64(4096)))
+	assert.Check(t, is.Equal(s.MemoryStats.Limit, ^uint64(0)))
+	assert.Check(t, is.Equal(s.MemoryStats.Failcnt, uint64(3)))
+	assert.Check(t, is.Equal(s.MemoryStats.OOMEvents, uint64(1)))
+	assert.Check(t, is.Equal(s.MemoryStats.OOMKills, uint64(1)))
+	assert.Check(t, is.Equal(s.MemoryStats.Stats["inactive_file"], uint64(512)))
+	assert.Check(t, is.DeepEqual(s.PidsStats, types.PidsStats{Current: 5, Limit: 100}))
+	assert.Check(t, is.DeepEqual(s.BlkioStats.IoServiceBytesRecursive, []types.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 100},
+		{Major: 8, Minor: 0, Op: "Write", Value: 200},
+	}))
+	assert.Check(t, is.DeepEqual(s.BlkioStats.IoServicedRecursive, []types.BlkioStatEntry{
+		{Major: 8, Minor: 0, Op: "Read", Value: 1},
+		{Major: 8, Minor: 0, Op: "Write", Value: 2},
+	}))
+	assert.Assert(t, s.PressureStats != nil)
+	assert.Check(t, is.DeepEqual(s.PressureStats.Memory, &types.Pressure{
+		Some: types.PressureData{Avg10: 1.5, Avg60: 0.25, Total: 1234},
+		Full: &types.PressureData{Avg10: 0.5, Total: 567},
+	}))
+	assert.Check(t, is.DeepEqual(s.PressureStats.CPU, &types.Pressure{Some: types.PressureData{Total: 42}}))
+	assert.Check(t, is.Nil(s.PressureStats.IO))
+}
+
+func TestReadCgroup2StatsNoControllers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup2-stats")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(dir, "cpu.stat"), []byte("usage_usec 1\n"), 0644))
+	s, err := readCgroup2Stats(dir)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(s.CPUStats.CPUUsage.TotalUsage, uint64(1000)))
+	assert.Check(t, is.DeepEqual(s.MemoryStats, types.MemoryStats{}))
+	assert.Check(t, is.DeepEqual(s.PidsStats, types.PidsStats{}))
+	assert.Check(t, is.Nil(s.PressureStats))
+}
@@ -21,6 +21,11 @@ func (daemon *Daemon) newStatsCollector(interval time.Duration) *stats.Collector
 		}
 	}
 	s := stats.NewCollector(daemon, interval)
+	if cfg := daemon.configStore.StatsHistory; cfg.Enabled {
+		// the config is validated when it is loaded
+		maxSamples, historyInterval, retention, _ := cfg.Limits()
+		s.EnableHistory(maxSamples, historyInterval, retention)
+	}
 	go s.Run()
 	return s
 }
@@ -0,0 +1,248 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/opencontainers/runc/libcontainer/system"
+	"github.com/opencontainers/runc/libcontainer/user"
+	"github.com/pkg/errors"
+)
+
+// psTitles are the titles of the columns of the processes listed from /proc,
+// which are the ones of "ps -ef".
+var psTitles = []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}
+
+// listProcesses reads the processes with the given PIDs from /proc, and
+// returns them both as structured data, and as the columns of "ps -ef".
+func listProcesses(pids []uint32) (*container.ContainerTopOKBody, error) {
+	return readProcesses("/proc", pids, time.Now(), lookupUserName)
+}
+
+// lookupUserName returns the name of the user with the given ID on the host.
+func lookupUserName(uid uint32) (string, bool) {
+	u, err := user.LookupUid(int(uid))
+	if err != nil {
+		return "", false
+	}
+	return u.Name, true
+}
+
+func readProcesses(procRoot string, pids []uint32, now time.Time, lookupUser func(uint32) (string, bool)) (*container.ContainerTopOKBody, error) {
+	bootTime, err := readBootTime(procRoot)
+	if err != nil {
+		return nil, err
+	}
+	ticks := uint64(system.GetClockTicks())
+
+	procList := &container.ContainerTopOKBody{
+		Titles:    psTitles,
+		Processes: [][]string{},
+	}
+	users := make(map[uint32]string)
+	sorted := append([]uint32(nil), pids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for _, pid := range sorted {
+		p, start, err := readProcess(procRoot, pid, bootTime, ticks, now)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				// the process exited since the PIDs were listed
+				continue
+			}
+			return nil, err
+		}
+
+		name, ok := users[p.UID]
+		if !ok {
+			if name, ok = lookupUser(p.UID); !ok {
+				name = strconv.FormatUint(uint64(p.UID), 10)
+			}
+			users[p.UID] = name
+		}
+		p.User = name
+
+		procList.ProcessList = append(procList.ProcessList, p)
+		procList.Processes = append(procList.Processes, psFields(p, start, now))
+	}
+	return procList, nil
+}
+
+// readBootTime returns the boot time of the host, from the btime line of
+// /proc/stat.
+func readBootTime(procRoot string) (time.Time, error) {
+	f, err := os.Open(filepath.Join(procRoot, "stat"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "btime" {
+			btime, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, errors.Wrap(err, "invalid boot time")
+			}
+			return time.Unix(btime, 0), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, err
+	}
+	return time.Time{}, errors.New("no boot time in /proc/stat")
+}
+
+// readProcess reads a process from the stat, status and cmdline files of
+// /proc/<pid>, and returns it with its start time.
+func readProcess(procRoot string, pid uint32, bootTime time.Time, ticks uint64, now time.Time) (*container.ContainerTopProcess, time.Time, error) {
+	dir := filepath.Join(procRoot, strconv.FormatUint(uint64(pid), 10))
+	stat, err := ioutil.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// the command is between parentheses, and may contain spaces and
+	// parentheses itself
+	open, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return nil, time.Time{}, errors.Errorf("invalid stat of process %d", pid)
+	}
+	// fields[0] is the third field of the file, the state
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 {
+		return nil, time.Time{}, errors.Errorf("invalid stat of process %d", pid)
+	}
+	var values [22]uint64
+	for _, i := range []int{1, 4, 11, 12, 17, 19, 21} {
+		if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			return nil, time.Time{}, errors.Wrapf(err, "invalid stat of process %d", pid)
+		}
+	}
+	var (
+		ppid      = values[1]
+		ttyNr     = values[4]
+		cpuTicks  = values[11] + values[12] // utime + stime
+		threads   = values[17]
+		startTime = bootTime.Add(time.Duration(values[19]) * time.Second / time.Duration(ticks))
+		rssPages  = values[21]
+		cpuTime   = time.Duration(cpuTicks) * time.Second / time.Duration(ticks)
+	)
+
+	p := &container.ContainerTopProcess{
+		PID:       int64(pid),
+		PPID:      int64(ppid),
+		Command:   string(stat[open+1 : end]),
+		State:     fields[0],
+		Threads:   int64(threads),
+		CPUTime:   int64(cpuTime),
+		RSS:       rssPages * uint64(os.Getpagesize()),
+		StartTime: startTime.Format(time.RFC3339Nano),
+		TTY:       ttyName(ttyNr),
+	}
+	if elapsed := now.Sub(startTime); elapsed > 0 {
+		p.CPUPercent = float64(cpuTime) / float64(elapsed) * 100
+	}
+
+	if p.UID, err = readProcessUID(filepath.Join(dir, "status")); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	cmdline, err := ioutil.ReadFile(filepath.Join(dir, "cmdline"))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	p.Cmdline = []string{}
+	if cmdline = bytes.TrimRight(cmdline, "\x00"); len(cmdline) > 0 {
+		p.Cmdline = strings.Split(string(cmdline), "\x00")
+	}
+	return p, startTime, nil
+}
+
+// readProcessUID returns the real user ID of a process, from the Uid line of
+// /proc/<pid>/status.
+func readProcessUID(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "Uid:" {
+			uid, err := strconv.ParseUint(fields[1], 10, 32)
+			if err != nil {
+				return 0, errors.Wrapf(err, "invalid uid in %s", path)
+			}
+			return uint32(uid), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.Errorf("no uid in %s", path)
+}
+
+// ttyName returns the name of the controlling terminal of a process from its
+// device number, as displayed by ps.
+func ttyName(ttyNr uint64) string {
+	major := (ttyNr >> 8) & 0xfff
+	minor := (ttyNr & 0xff) | ((ttyNr >> 12) & 0xfff00)
+	switch {
+	case ttyNr == 0:
+		return "?"
+	case major >= 136 && major <= 143:
+		return fmt.Sprintf("pts/%d", (major-136)*256+minor)
+	case major == 4 && minor < 64:
+		return fmt.Sprintf("tty%d", minor)
+	case major == 4:
+		return fmt.Sprintf("ttyS%d", minor-64)
+	default:
+		return "?"
+	}
+}
+
+// psFields returns the columns of "ps -ef" for the process.
+func psFields(p *container.ContainerTopProcess, start, now time.Time) []string {
+	start, now = start.Local(), now.Local()
+	stime := start.Format("15:04")
+	switch {
+	case start.Year() != now.Year():
+		stime = start.Format("2006")
+	case start.YearDay() != now.YearDay():
+		stime = start.Format("Jan02")
+	}
+
+	cpuTime := time.Duration(p.CPUTime) / time.Second
+	cputime := fmt.Sprintf("%02d:%02d:%02d", cpuTime/3600%24, cpuTime/60%60, cpuTime%60)
+	if days := cpuTime / (24 * 3600); days > 0 {
+		cputime = fmt.Sprintf("%d-%s", days, cputime)
+	}
+
+	cmd := strings.Join(p.Cmdline, " ")
+	if cmd == "" {
+		cmd = "[" + p.Command + "]"
+	}
+
+	return []string{
+		p.User,
+		strconv.FormatInt(p.PID, 10),
+		strconv.FormatInt(p.PPID, 10),
+		strconv.Itoa(int(p.CPUPercent)),
+		stime,
+		p.TTY,
+		cputime,
+		cmd,
+	}
+}
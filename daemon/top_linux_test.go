@@ -0,0 +1,101 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func writeProcFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	p := filepath.Join(root, name)
+	assert.NilError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	assert.NilError(t, ioutil.WriteFile(p, []byte(content), 0644))
+}
+
+func TestReadProcesses(t *testing.T) {
+	root, err := ioutil.TempDir("", "top-proc")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	bootTime := time.Unix(1500000000, 0)
+	writeProcFile(t, root, "stat", "cpu  1 2 3 4\nbtime 1500000000\nprocesses 42\n")
+	// a shell on pts/0 started 10s after boot, which used 2s of CPU, and
+	// a kernel-like process without a command line
+	writeProcFile(t, root, "10/stat", "10 (bash) S 1 10 10 34816 10 4194560 1 0 0 0 150 50 0 0 20 0 1 0 1000 4194304 512 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n")
+	writeProcFile(t, root, "10/status", "Name:\tbash\nUid:\t0\t0\t0\t0\nGid:\t0\t0\t0\t0\n")
+	writeProcFile(t, root, "10/cmdline", "/bin/bash\x00-l\x00")
+	writeProcFile(t, root, "20/stat", "20 (my (odd) cmd) R 10 20 10 0 20 4194560 1 0 0 0 0 0 0 0 20 0 3 0 2000 4194304 2 18446744073709551615 0 0 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n")
+	writeProcFile(t, root, "20/status", "Name:\tmy (odd) cmd\nUid:\t1000\t1000\t1000\t1000\n")
+	writeProcFile(t, root, "20/cmdline", "")
+
+	users := map[uint32]string{0: "root"}
+	lookupUser := func(uid uint32) (string, bool) {
+		name, ok := users[uid]
+		return name, ok
+	}
+
+	now := bootTime.Add(110 * time.Second)
+	// 30 exited since the PIDs were listed
+	procList, err := readProcesses(root, []uint32{20, 30, 10}, now, lookupUser)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(procList.Titles, psTitles))
+	assert.Assert(t, is.Len(procList.ProcessList, 2))
+	assert.Assert(t, is.Len(procList.Processes, 2))
+
+	bash := procList.ProcessList[0]
+	assert.Check(t, is.Equal(bash.PID, int64(10)))
+	assert.Check(t, is.Equal(bash.PPID, int64(1)))
+	assert.Check(t, is.Equal(bash.UID, uint32(0)))
+	assert.Check(t, is.Equal(bash.User, "root"))
+	assert.Check(t, is.Equal(bash.Command, "bash"))
+	assert.Check(t, is.DeepEqual(bash.Cmdline, []string{"/bin/bash", "-l"}))
+	assert.Check(t, is.Equal(bash.State, "S"))
+	assert.Check(t, is.Equal(bash.Threads, int64(1)))
+	assert.Check(t, is.Equal(bash.TTY, "pts/0"))
+	assert.Check(t, is.Equal(bash.CPUTime, int64(2*time.Second)))
+	assert.Check(t, is.Equal(bash.CPUPercent, float64(2)))
+	assert.Check(t, is.Equal(bash.RSS, uint64(512*os.Getpagesize())))
+	assert.Check(t, is.Equal(bash.StartTime, bootTime.Add(10*time.Second).Format(time.RFC3339Nano)))
+	assert.Check(t, is.DeepEqual(procList.Processes[0][:4], []string{"root", "10", "1", "2"}))
+	assert.Check(t, is.DeepEqual(procList.Processes[0][5:], []string{"pts/0", "00:00:02", "/bin/bash -l"}))
+
+	odd := procList.ProcessList[1]
+	assert.Check(t, is.Equal(odd.PID, int64(20)))
+	assert.Check(t, is.Equal(odd.Command, "my (odd) cmd"))
+	assert.Check(t, is.DeepEqual(odd.Cmdline, []string{}))
+	assert.Check(t, is.Equal(odd.State, "R"))
+	assert.Check(t, is.Equal(odd.Threads, int64(3)))
+	assert.Check(t, is.Equal(odd.TTY, "?"))
+	assert.Check(t, is.Equal(odd.User, "1000"))
+	assert.Check(t, is.Equal(procList.Processes[1][7], "[my (odd) cmd]"))
+}
+
+func TestReadProcessesNoBootTime(t *testing.T) {
+	root, err := ioutil.TempDir("", "top-proc")
+	assert.NilError(t, err)
+	defer os.RemoveAll(root)
+
+	writeProcFile(t, root, "stat", "cpu  1 2 3 4\n")
+	_, err = readProcesses(root, []uint32{1}, time.Now(), lookupUserName)
+	assert.Check(t, is.ErrorContains(err, "no boot time"))
+}
+
+func TestTTYName(t *testing.T) {
+	for ttyNr, expected := range map[uint64]string{
+		0:     "?",
+		34816: "pts/0",   // 136:0
+		34819: "pts/3",   // 136:3
+		35072: "pts/256", // 137:0
+		1025:  "tty1",    // 4:1
+		1088:  "ttyS0",   // 4:64
+		1280:  "?",       // 5:0
+	} {
+		assert.Check(t, is.Equal(ttyName(ttyNr), expected), "tty_nr %d", ttyNr)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/errdefs"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 func validatePSArgs(psArgs string) error {
@@ -131,17 +132,16 @@ func psPidsArg(pids []uint32) string {
 }
 
 // ContainerTop lists the processes running inside of the given
-// container by calling ps with the given args, or with the flags
-// "-ef" if no args are given.  An error is returned if the container
-// is not found, or is not running, or if there are any problems
-// running ps, or parsing the output.
+// container by reading them from /proc if no args are given, or by
+// calling ps with the given args, or with the flags "-ef" if the
+// processes cannot be read from /proc. An error is returned if the
+// container is not found, or is not running, or if there are any
+// problems running ps, or parsing the output.
 func (daemon *Daemon) ContainerTop(name string, psArgs string) (*container.ContainerTopOKBody, error) {
-	if psArgs == "" {
-		psArgs = "-ef"
-	}
-
-	if err := validatePSArgs(psArgs); err != nil {
-		return nil, err
+	if psArgs != "" {
+		if err := validatePSArgs(psArgs); err != nil {
+			return nil, err
+		}
 	}
 
 	container, err := daemon.GetContainer(name)
@@ -162,6 +162,16 @@ func (daemon *Daemon) ContainerTop(name string, psArgs string) (*container.Conta
 		return nil, err
 	}
 
+	if psArgs == "" {
+		procList, err := listProcesses(procs)
+		if err == nil {
+			daemon.LogContainerEvent(container, "top")
+			return procList, nil
+		}
+		logrus.WithError(err).WithField("container", container.ID).Debug("Failed to read processes from /proc, running ps instead")
+		psArgs = "-ef"
+	}
+
 	args := strings.Split(psArgs, " ")
 	pids := psPidsArg(procs)
 	output, err := exec.Command("ps", append(args, pids)...).Output()
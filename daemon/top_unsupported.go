@@ -0,0 +1,13 @@
+// +build !linux,!windows
+
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"errors"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func listProcesses(pids []uint32) (*container.ContainerTopOKBody, error) {
+	return nil, errors.New("listing processes from /proc is not supported on this platform")
+}
@@ -3,14 +3,17 @@ package daemon // import "github.com/docker/docker/daemon"
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/oci"
 	"github.com/pkg/errors"
 )
 
 // ContainerUpdate updates configuration of the container
-func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostConfig) (container.ContainerUpdateOKBody, error) {
+func (daemon *Daemon) ContainerUpdate(name string, updateConfig *container.UpdateConfig) (container.ContainerUpdateOKBody, error) {
 	var warnings []string
 
 	c, err := daemon.GetContainer(name)
@@ -18,54 +21,82 @@ func (daemon *Daemon) ContainerUpdate(name string, hostConfig *container.HostCon
 		return container.ContainerUpdateOKBody{Warnings: warnings}, err
 	}
 
+	hostConfig := &container.HostConfig{
+		Resources:     updateConfig.Resources,
+		RestartPolicy: updateConfig.RestartPolicy,
+	}
+	if updateConfig.OomScoreAdj != nil {
+		hostConfig.OomScoreAdj = *updateConfig.OomScoreAdj
+	}
 	warnings, err = daemon.verifyContainerSettings(c.OS, hostConfig, nil, true)
 	if err != nil {
 		return container.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
 	}
+	if _, err := oci.AppendDevicePermissionsFromCgroupRules(nil, hostConfig.DeviceCgroupRules); err != nil {
+		return container.ContainerUpdateOKBody{Warnings: warnings}, errdefs.InvalidParameter(err)
+	}
+	// the verification discards the resources not supported by the host
+	updateConfig.Resources = hostConfig.Resources
 
-	if err := daemon.update(name, hostConfig); err != nil {
+	w, err := daemon.update(name, updateConfig)
+	warnings = append(warnings, w...)
+	if err != nil {
 		return container.ContainerUpdateOKBody{Warnings: warnings}, err
 	}
 
 	return container.ContainerUpdateOKBody{Warnings: warnings}, nil
 }
 
-func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) error {
-	if hostConfig == nil {
-		return nil
+func (daemon *Daemon) update(name string, updateConfig *container.UpdateConfig) ([]string, error) {
+	if updateConfig == nil {
+		return nil, nil
+	}
+	hostConfig := &container.HostConfig{
+		Resources:     updateConfig.Resources,
+		RestartPolicy: updateConfig.RestartPolicy,
 	}
 
 	container, err := daemon.GetContainer(name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	restoreConfig := false
 	backupHostConfig := *container.HostConfig
+	backupLabels := container.Config.Labels
 	defer func() {
 		if restoreConfig {
 			container.Lock()
 			container.HostConfig = &backupHostConfig
+			container.Config.Labels = backupLabels
 			container.CheckpointTo(daemon.containersReplica)
 			container.Unlock()
 		}
 	}()
 
 	if container.RemovalInProgress || container.Dead {
-		return errCannotUpdate(container.ID, fmt.Errorf("container is marked for removal and cannot be \"update\""))
+		return nil, errCannotUpdate(container.ID, fmt.Errorf("container is marked for removal and cannot be \"update\""))
 	}
 
 	container.Lock()
 	if err := container.UpdateContainer(hostConfig); err != nil {
 		restoreConfig = true
 		container.Unlock()
-		return errCannotUpdate(container.ID, err)
+		return nil, errCannotUpdate(container.ID, err)
+	}
+	if updateConfig.OomScoreAdj != nil {
+		container.HostConfig.OomScoreAdj = *updateConfig.OomScoreAdj
+	}
+	if updateConfig.Labels != nil {
+		container.Config.Labels = updateConfig.Labels
 	}
 	if err := container.CheckpointTo(daemon.containersReplica); err != nil {
 		restoreConfig = true
 		container.Unlock()
-		return errCannotUpdate(container.ID, err)
+		return nil, errCannotUpdate(container.ID, err)
 	}
+	updatedHostConfig := *container.HostConfig
+	changed := changedOptions(&backupHostConfig, &updatedHostConfig, backupLabels, container.Config.Labels)
 	container.Unlock()
 
 	// if Restart Policy changed, we need to update container monitor
@@ -77,17 +108,66 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 	// resources will be updated when the container is started again.
 	// If container is running (including paused), we need to update configs
 	// to the real world.
+	var warnings []string
 	if container.IsRunning() && !container.IsRestarting() {
-		if err := daemon.containerd.UpdateResources(context.Background(), container.ID, toContainerdResources(hostConfig.Resources)); err != nil {
+		resources := liveResources(hostConfig.Resources, updatedHostConfig.Resources)
+		if err := daemon.containerd.UpdateResources(context.Background(), container.ID, toContainerdResources(resources)); err != nil {
 			restoreConfig = true
 			// TODO: it would be nice if containerd responded with better errors here so we can classify this better.
-			return errCannotUpdate(container.ID, errdefs.System(err))
+			return nil, errCannotUpdate(container.ID, errdefs.System(err))
+		}
+		if warnings, err = daemon.updateProcesses(container, &backupHostConfig, &updatedHostConfig); err != nil {
+			restoreConfig = true
+			return warnings, errCannotUpdate(container.ID, errdefs.System(err))
 		}
 	}
 
-	daemon.LogContainerEvent(container, "update")
+	attributes := map[string]string{
+		"changed": strings.Join(changed, ","),
+	}
+	daemon.LogContainerEventWithAttributes(container, "update", attributes)
 
-	return nil
+	return warnings, nil
+}
+
+// changedOptions returns the names of the updatable options which differ
+// between the old and new configurations of a container, as named in the
+// API.
+func changedOptions(old, updated *container.HostConfig, oldLabels, updatedLabels map[string]string) []string {
+	var changed []string
+	oldResources, updatedResources := reflect.ValueOf(old.Resources), reflect.ValueOf(updated.Resources)
+	for i := 0; i < oldResources.NumField(); i++ {
+		if !equalOption(oldResources.Field(i), updatedResources.Field(i)) {
+			field := oldResources.Type().Field(i)
+			name := field.Name
+			if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" {
+				name = tag
+			}
+			changed = append(changed, name)
+		}
+	}
+	if !equalOption(reflect.ValueOf(old.RestartPolicy), reflect.ValueOf(updated.RestartPolicy)) {
+		changed = append(changed, "RestartPolicy")
+	}
+	if old.OomScoreAdj != updated.OomScoreAdj {
+		changed = append(changed, "OomScoreAdj")
+	}
+	if !equalOption(reflect.ValueOf(oldLabels), reflect.ValueOf(updatedLabels)) {
+		changed = append(changed, "Labels")
+	}
+	return changed
+}
+
+// equalOption returns whether two values of an option are equal, where nil
+// and empty slices and maps are equal.
+func equalOption(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
 }
 
 func errCannotUpdate(containerID string, err error) error {
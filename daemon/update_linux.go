@@ -1,11 +1,21 @@
 package daemon // import "github.com/docker/docker/daemon"
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
 	"time"
 
 	"github.com/docker/docker/api/types/container"
+	containerpkg "github.com/docker/docker/container"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
+	"github.com/docker/docker/pkg/sysinfo"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 func toContainerdResources(resources container.Resources) *libcontainerdtypes.Resources {
@@ -53,3 +63,73 @@ func toContainerdResources(resources container.Resources) *libcontainerdtypes.Re
 	r.Pids = getPidsLimit(resources)
 	return &r
 }
+
+// liveResources returns the resources to update on a running container from
+// the updated resources and all the resources of the container.
+func liveResources(update, all container.Resources) container.Resources {
+	if sysinfo.IsCgroup2UnifiedMode() {
+		return cgroup2LiveResources(update, all)
+	}
+	return update
+}
+
+// cgroup2LiveResources returns the resources to update on a running container
+// with cgroup v2, where the swap limit is set apart from the memory limit,
+// from the difference of both, and has to be set again when the memory limit
+// changes.
+func cgroup2LiveResources(update, all container.Resources) container.Resources {
+	update.Memory = all.Memory
+	update.MemorySwap = all.MemorySwap
+	return update
+}
+
+// updateProcesses applies the updated options which are not resources of
+// the cgroups of a running container. The device cgroup rules are applied
+// when the container restarts, as the rules allowed by the runtime, and by
+// the hooks of the container, are not known to the daemon. The OOM score
+// adjustment of the processes is updated immediately.
+func (daemon *Daemon) updateProcesses(c *containerpkg.Container, old, updated *container.HostConfig) ([]string, error) {
+	var warnings []string
+
+	if !updated.Privileged && !equalOption(reflect.ValueOf(old.DeviceCgroupRules), reflect.ValueOf(updated.DeviceCgroupRules)) {
+		warnings = append(warnings, "The device cgroup rules of running containers are not updated, they are applied when the container restarts.")
+	}
+
+	if old.OomScoreAdj != updated.OomScoreAdj {
+		pids, err := daemon.containerd.ListPids(context.Background(), c.ID)
+		if err != nil {
+			return warnings, err
+		}
+		if err := setOomScoreAdj(pids, old.OomScoreAdj, updated.OomScoreAdj); err != nil {
+			return warnings, err
+		}
+	}
+
+	return warnings, nil
+}
+
+// setOomScoreAdj sets the OOM score adjustment of the processes. If it fails
+// for a process, the score of the processes already updated is set back to
+// old.
+func setOomScoreAdj(pids []uint32, old, updated int) error {
+	for i, pid := range pids {
+		// ignore the processes which exited since they were listed
+		if err := writeOomScoreAdj(pid, updated); err != nil && !os.IsNotExist(err) {
+			for _, pid := range pids[:i] {
+				if err := writeOomScoreAdj(pid, old); err != nil && !os.IsNotExist(err) {
+					logrus.WithError(err).Warnf("failed to restore the OOM score adjustment of process %d", pid)
+				}
+			}
+			return errors.Wrapf(err, "failed to set the OOM score adjustment of process %d", pid)
+		}
+	}
+	return nil
+}
+
+var oomScoreAdjPath = func(pid uint32) string {
+	return fmt.Sprintf("/proc/%d/oom_score_adj", pid)
+}
+
+func writeOomScoreAdj(pid uint32, score int) error {
+	return ioutil.WriteFile(oomScoreAdjPath(pid), []byte(strconv.Itoa(score)), 0644)
+}
@@ -0,0 +1,53 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestCgroup2LiveResources(t *testing.T) {
+	all := containertypes.Resources{CPUShares: 512, Memory: 512 * 1024 * 1024, MemorySwap: 1024 * 1024 * 1024}
+	update := containertypes.Resources{CPUShares: 512}
+
+	r := cgroup2LiveResources(update, all)
+	assert.Check(t, is.Equal(r.CPUShares, int64(512)))
+	assert.Check(t, is.Equal(r.Memory, all.Memory))
+	assert.Check(t, is.Equal(r.MemorySwap, all.MemorySwap))
+}
+
+func TestSetOomScoreAdj(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oom-score-adj")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(f func(uint32) string) { oomScoreAdjPath = f }(oomScoreAdjPath)
+	oomScoreAdjPath = func(pid uint32) string {
+		return filepath.Join(dir, strconv.Itoa(int(pid)), "oom_score_adj")
+	}
+	readScore := func(pid uint32) string {
+		b, err := ioutil.ReadFile(oomScoreAdjPath(pid))
+		assert.NilError(t, err)
+		return string(b)
+	}
+
+	// process 2 exited since it was listed
+	assert.NilError(t, os.Mkdir(filepath.Join(dir, "1"), 0755))
+	assert.NilError(t, ioutil.WriteFile(oomScoreAdjPath(1), []byte("0"), 0644))
+	assert.NilError(t, setOomScoreAdj([]uint32{1, 2}, 0, 500))
+	assert.Check(t, is.Equal(readScore(1), "500"))
+	_, err = os.Stat(filepath.Join(dir, "2"))
+	assert.Check(t, os.IsNotExist(err))
+
+	// the score of process 1 is set back when it cannot be set for process 3
+	assert.NilError(t, os.MkdirAll(oomScoreAdjPath(3), 0755))
+	err = setOomScoreAdj([]uint32{1, 3}, 500, 1000)
+	assert.Check(t, is.ErrorContains(err, "failed to set the OOM score adjustment of process 3"))
+	assert.Check(t, is.Equal(readScore(1), "500"))
+}
@@ -0,0 +1,41 @@
+package daemon // import "github.com/docker/docker/daemon"
+
+import (
+	"testing"
+
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestChangedOptions(t *testing.T) {
+	pidsLimit := int64(100)
+	old := &containertypes.HostConfig{
+		Resources: containertypes.Resources{
+			CPUShares: 512,
+			Memory:    1024 * 1024 * 1024,
+			PidsLimit: &pidsLimit,
+		},
+		RestartPolicy: containertypes.RestartPolicy{Name: "no"},
+	}
+
+	updatedPidsLimit := int64(100)
+	updated := &containertypes.HostConfig{
+		Resources: containertypes.Resources{
+			CPUShares:         1024,
+			Memory:            1024 * 1024 * 1024,
+			PidsLimit:         &updatedPidsLimit,
+			Ulimits:           []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}},
+			DeviceCgroupRules: []string{},
+		},
+		RestartPolicy: containertypes.RestartPolicy{Name: "always"},
+		OomScoreAdj:   500,
+	}
+
+	changed := changedOptions(old, updated, nil, map[string]string{})
+	assert.Check(t, is.DeepEqual(changed, []string{"CpuShares", "Ulimits", "RestartPolicy", "OomScoreAdj"}))
+
+	changed = changedOptions(old, old, map[string]string{"foo": "bar"}, map[string]string{"foo": "baz"})
+	assert.Check(t, is.DeepEqual(changed, []string{"Labels"}))
+}
@@ -2,6 +2,7 @@ package daemon // import "github.com/docker/docker/daemon"
 
 import (
 	"github.com/docker/docker/api/types/container"
+	containerpkg "github.com/docker/docker/container"
 	libcontainerdtypes "github.com/docker/docker/libcontainerd/types"
 )
 
@@ -9,3 +10,11 @@ func toContainerdResources(resources container.Resources) *libcontainerdtypes.Re
 	// We don't support update, so do nothing
 	return nil
 }
+
+func liveResources(update, all container.Resources) container.Resources {
+	return update
+}
+
+func (daemon *Daemon) updateProcesses(c *containerpkg.Container, old, updated *container.HostConfig) ([]string, error) {
+	return nil, nil
+}
@@ -24,6 +24,8 @@ type Store interface {
 	GetParent(id ID) (ID, error)
 	SetLastUpdated(id ID) error
 	GetLastUpdated(id ID) (time.Time, error)
+	SetAnnotations(id ID, annotations map[string]string) error
+	GetAnnotations(id ID) (map[string]string, error)
 	Children(id ID) []ID
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
@@ -294,6 +296,30 @@ func (is *store) GetLastUpdated(id ID) (time.Time, error) {
 	return time.Parse(time.RFC3339Nano, string(bytes))
 }
 
+// SetAnnotations stores the annotations of the image ID, which are local
+// metadata and not part of the image config.
+func (is *store) SetAnnotations(id ID, annotations map[string]string) error {
+	data, err := json.Marshal(annotations)
+	if err != nil {
+		return err
+	}
+	return is.fs.SetMetadata(id.Digest(), "annotations", data)
+}
+
+// GetAnnotations returns the annotations of the image ID
+func (is *store) GetAnnotations(id ID) (map[string]string, error) {
+	data, err := is.fs.GetMetadata(id.Digest(), "annotations")
+	if err != nil || len(data) == 0 {
+		// No annotations
+		return nil, nil
+	}
+	var annotations map[string]string
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
 func (is *store) Children(id ID) []ID {
 	is.RLock()
 	defer is.RUnlock()
@@ -172,6 +172,24 @@ func TestGetAndSetLastUpdated(t *testing.T) {
 	assert.Check(t, cmp.Equal(updated.IsZero(), false))
 }
 
+func TestGetAndSetAnnotations(t *testing.T) {
+	store, cleanup := defaultImageStore(t)
+	defer cleanup()
+
+	id, err := store.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	assert.NilError(t, err)
+
+	annotations, err := store.GetAnnotations(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.Len(annotations, 0))
+
+	assert.Check(t, store.SetAnnotations(id, map[string]string{"debug": "true"}))
+
+	annotations, err = store.GetAnnotations(id)
+	assert.NilError(t, err)
+	assert.Check(t, cmp.DeepEqual(annotations, map[string]string{"debug": "true"}))
+}
+
 func TestStoreLen(t *testing.T) {
 	store, cleanup := defaultImageStore(t)
 	defer cleanup()
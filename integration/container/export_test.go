@@ -3,11 +3,13 @@ package container // import "github.com/docker/docker/integration/container"
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/integration/internal/container"
 	"github.com/docker/docker/internal/test/daemon"
 	"github.com/docker/docker/pkg/jsonmessage"
@@ -74,3 +76,48 @@ func TestExportContainerAfterDaemonRestart(t *testing.T) {
 	_, err := c.ContainerExport(ctx, ctrID)
 	assert.NilError(t, err)
 }
+
+// export the writable layer of a container and import it into another
+// container created from the same image
+func TestExportAndImportRWLayer(t *testing.T) {
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "export and import of the rw layer was added in API v1.41")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(ctx, t, client, container.WithCmd("sh", "-c", "echo state > /state && rm /etc/group"))
+	poll.WaitOn(t, container.IsStopped(ctx, client, cID), poll.WithDelay(100*time.Millisecond))
+
+	export, imageID, err := client.ContainerExportRWLayer(ctx, cID)
+	assert.NilError(t, err)
+	defer export.Close()
+
+	restoredID := container.Create(ctx, t, client, container.WithCmd("sh", "-c", "cat /state && test ! -e /etc/group"))
+	err = client.ContainerImportRWLayer(ctx, restoredID, imageID, export)
+	assert.NilError(t, err)
+
+	err = client.ContainerStart(ctx, restoredID, types.ContainerStartOptions{})
+	assert.NilError(t, err)
+	poll.WaitOn(t, container.IsInState(ctx, client, restoredID, "exited"), poll.WithDelay(100*time.Millisecond))
+
+	inspect, err := client.ContainerInspect(ctx, restoredID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inspect.State.ExitCode, 0))
+}
+
+// importing a writable layer exported from a container of another image fails
+func TestImportRWLayerImageMismatch(t *testing.T) {
+	skip.If(t, testEnv.DaemonInfo.OSType == "windows")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "export and import of the rw layer was added in API v1.41")
+
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Create(ctx, t, client)
+
+	err := client.ContainerImportRWLayer(ctx, cID, "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", strings.NewReader(""))
+	assert.Check(t, is.ErrorContains(err, "You cannot import a layer exported from a container of image"))
+}
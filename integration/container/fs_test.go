@@ -0,0 +1,63 @@
+package container // import "github.com/docker/docker/integration/container"
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/integration/internal/container"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/poll"
+	"gotest.tools/skip"
+)
+
+func TestContainerFs(t *testing.T) {
+	skip.If(t, testEnv.OSType == "windows")
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "the fs endpoints were added in API v1.41")
+
+	defer setupTest(t)()
+	ctx := context.Background()
+	apiclient := testEnv.APIClient()
+
+	cid := container.Run(ctx, t, apiclient, container.WithCmd("sh", "-c", "mkdir /dir && echo -n 'hello world' > /dir/b && ln -s /dir/b /dir/a"))
+	poll.WaitOn(t, container.IsStopped(ctx, apiclient, cid), poll.WithDelay(100*time.Millisecond))
+
+	stat, err := apiclient.ContainerFsStat(ctx, cid, "/dir/a")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(stat.Name, "a"))
+	assert.Check(t, stat.Mode&os.ModeSymlink != 0)
+	assert.Check(t, is.Equal(stat.LinkTarget, "/dir/b"))
+
+	entries, err := apiclient.ContainerFsList(ctx, cid, "/dir")
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(entries, 2))
+	assert.Check(t, is.Equal(entries[0].Name, "a"))
+	assert.Check(t, is.Equal(entries[0].LinkTarget, "/dir/b"))
+	assert.Check(t, is.Equal(entries[1].Name, "b"))
+	assert.Check(t, is.Equal(entries[1].Size, int64(len("hello world"))))
+
+	_, err = apiclient.ContainerFsList(ctx, cid, "/dir/b")
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	_, _, err = apiclient.ContainerFsRead(ctx, cid, "/dir", types.ContainerFsReadOptions{})
+	assert.Check(t, errdefs.IsInvalidParameter(err))
+
+	_, _, err = apiclient.ContainerFsRead(ctx, cid, "/dne", types.ContainerFsReadOptions{})
+	assert.Check(t, client.IsErrNotFound(err))
+
+	r, stat, err := apiclient.ContainerFsRead(ctx, cid, "/dir/a", types.ContainerFsReadOptions{Offset: 6, Length: 3})
+	assert.NilError(t, err)
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "wor"))
+	assert.Check(t, stat.Mode.IsRegular())
+	assert.Check(t, is.Equal(stat.Size, int64(len("hello world"))))
+}
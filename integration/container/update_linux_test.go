@@ -8,9 +8,11 @@ import (
 	"time"
 
 	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/integration/internal/container"
 	"github.com/docker/docker/internal/test/request"
+	"github.com/docker/go-units"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
 	"gotest.tools/poll"
@@ -166,3 +168,59 @@ func TestUpdatePidsLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateOomScoreAdjAndUlimits(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "updating OomScoreAdj and Ulimits was added in API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(ctx, t, client)
+	poll.WaitOn(t, container.IsInState(ctx, client, cID, "running"), poll.WithDelay(100*time.Millisecond))
+
+	oomScoreAdj := 500
+	_, err := client.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		Resources: containertypes.Resources{
+			Ulimits: []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}},
+		},
+		OomScoreAdj: &oomScoreAdj,
+	})
+	assert.NilError(t, err)
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(inspect.HostConfig.OomScoreAdj, oomScoreAdj))
+	assert.Check(t, is.DeepEqual(inspect.HostConfig.Ulimits, []*units.Ulimit{{Name: "nofile", Soft: 1024, Hard: 2048}}))
+
+	// the main process of the container is updated
+	res, err := container.Exec(ctx, client, cID, []string{"cat", "/proc/1/oom_score_adj"})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(res.Stderr(), 0))
+	assert.Check(t, is.Equal("500", strings.TrimSpace(res.Stdout())))
+
+	// the ulimits apply to the processes started after the update
+	res, err = container.Exec(ctx, client, cID, []string{"sh", "-c", "ulimit -n"})
+	assert.NilError(t, err)
+	assert.Assert(t, is.Len(res.Stderr(), 0))
+	assert.Check(t, is.Equal("1024", strings.TrimSpace(res.Stdout())))
+}
+
+func TestUpdateLabels(t *testing.T) {
+	skip.If(t, versions.LessThan(testEnv.DaemonAPIVersion(), "1.41"), "updating Labels was added in API v1.41")
+	defer setupTest(t)()
+	client := testEnv.APIClient()
+	ctx := context.Background()
+
+	cID := container.Run(ctx, t, client, func(c *container.TestContainerConfig) {
+		c.Config.Labels = map[string]string{"foo": "bar", "baz": "qux"}
+	})
+
+	_, err := client.ContainerUpdate(ctx, cID, containertypes.UpdateConfig{
+		Labels: map[string]string{"foo": "updated"},
+	})
+	assert.NilError(t, err)
+
+	inspect, err := client.ContainerInspect(ctx, cID)
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(inspect.Config.Labels, map[string]string{"foo": "updated"}))
+}
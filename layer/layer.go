@@ -14,6 +14,7 @@ import (
 	"io"
 
 	"github.com/docker/distribution"
+	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/containerfs"
 	"github.com/opencontainers/go-digest"
@@ -143,6 +144,10 @@ type RWLayer interface {
 	// from the base layer.
 	Changes() ([]archive.Change, error)
 
+	// DiffGetter returns a FileGetCloser to retrieve the contents
+	// of the files of the mutable layer.
+	DiffGetter() (graphdriver.FileGetCloser, error)
+
 	// Metadata returns the low level metadata for the mutable layer
 	Metadata() (map[string]string, error)
 
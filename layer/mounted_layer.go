@@ -4,6 +4,7 @@ import (
 	"io"
 	"sync"
 
+	"github.com/docker/docker/daemon/graphdriver"
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/containerfs"
 )
@@ -56,6 +57,14 @@ func (ml *mountedLayer) Changes() ([]archive.Change, error) {
 	return ml.layerStore.driver.Changes(ml.mountID, ml.cacheParent())
 }
 
+func (ml *mountedLayer) DiffGetter() (graphdriver.FileGetCloser, error) {
+	diffDriver, ok := ml.layerStore.driver.(graphdriver.DiffGetterDriver)
+	if !ok {
+		diffDriver = &naiveDiffPathDriver{ml.layerStore.driver}
+	}
+	return diffDriver.DiffGetter(ml.mountID)
+}
+
 func (ml *mountedLayer) Metadata() (map[string]string, error) {
 	return ml.layerStore.driver.GetMetadata(ml.mountID)
 }
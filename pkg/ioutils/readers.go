@@ -28,6 +28,34 @@ func NewReadCloserWrapper(r io.Reader, closer func() error) io.ReadCloser {
 	}
 }
 
+// ReadSeekCloser is the interface that groups the basic Read, Seek and Close
+// methods.
+type ReadSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// ReadSeekCloserWrapper wraps an io.ReadSeeker, and implements a
+// ReadSeekCloser. It calls the given callback function when closed. It should
+// be constructed with NewReadSeekCloserWrapper
+type ReadSeekCloserWrapper struct {
+	io.ReadSeeker
+	closer func() error
+}
+
+// Close calls back the passed closer function
+func (r *ReadSeekCloserWrapper) Close() error {
+	return r.closer()
+}
+
+// NewReadSeekCloserWrapper returns a new ReadSeekCloser.
+func NewReadSeekCloserWrapper(r io.ReadSeeker, closer func() error) ReadSeekCloser {
+	return &ReadSeekCloserWrapper{
+		ReadSeeker: r,
+		closer:     closer,
+	}
+}
+
 type readerErrWrapper struct {
 	reader io.Reader
 	closer func()
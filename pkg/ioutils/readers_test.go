@@ -3,6 +3,7 @@ package ioutils // import "github.com/docker/docker/pkg/ioutils"
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"strings"
 	"testing"
@@ -30,6 +31,21 @@ func TestReadCloserWrapperClose(t *testing.T) {
 	}
 }
 
+func TestReadSeekCloserWrapper(t *testing.T) {
+	closed := false
+	wrapper := NewReadSeekCloserWrapper(strings.NewReader("A string reader"), func() error {
+		closed = true
+		return nil
+	})
+	_, err := wrapper.Seek(2, io.SeekStart)
+	assert.NilError(t, err)
+	content, err := ioutil.ReadAll(wrapper)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(content), "string reader"))
+	assert.NilError(t, wrapper.Close())
+	assert.Check(t, closed)
+}
+
 func TestReaderErrWrapperReadOnError(t *testing.T) {
 	called := false
 	reader := &errorReader{}
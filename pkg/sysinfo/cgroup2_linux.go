@@ -0,0 +1,113 @@
+package sysinfo // import "github.com/docker/docker/pkg/sysinfo"
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"golang.org/x/sys/unix"
+)
+
+// UnifiedMountpoint is the mount point of the cgroup v2 unified hierarchy.
+const UnifiedMountpoint = "/sys/fs/cgroup"
+
+var (
+	isUnifiedOnce sync.Once
+	isUnified     bool
+)
+
+// IsCgroup2UnifiedMode returns whether the host only uses the cgroup v2
+// unified hierarchy, that is whether a cgroup2 filesystem is mounted on
+// /sys/fs/cgroup.
+func IsCgroup2UnifiedMode() bool {
+	isUnifiedOnce.Do(func() {
+		var st unix.Statfs_t
+		if err := unix.Statfs(UnifiedMountpoint, &st); err != nil {
+			return
+		}
+		isUnified = st.Type == unix.CGROUP2_SUPER_MAGIC
+	})
+	return isUnified
+}
+
+// applyCgroup2Info reads the information of the controllers available in the
+// cgroup of the daemon in the unified hierarchy.
+func applyCgroup2Info(info *SysInfo, _ map[string]string) []string {
+	groups, err := cgroups.ParseCgroupFile("/proc/self/cgroup")
+	if err != nil {
+		return []string{fmt.Sprintf("Failed to parse cgroup information: %v", err)}
+	}
+	group, ok := groups[""]
+	if !ok {
+		return []string{"Unable to find the cgroup v2 group of the daemon"}
+	}
+	return applyCgroup2Controllers(info, path.Join(UnifiedMountpoint, group))
+}
+
+// applyCgroup2Controllers reads the information of the controllers available
+// in the cgroup v2 group at dir.
+func applyCgroup2Controllers(info *SysInfo, dir string) []string {
+	var warnings []string
+
+	// devices are controlled with eBPF programs, not with a controller
+	info.CgroupDevicesEnabled = true
+
+	content, err := ioutil.ReadFile(path.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return append(warnings, fmt.Sprintf("Unable to read cgroup v2 controllers: %v", err))
+	}
+	controllers := make(map[string]bool)
+	for _, c := range strings.Fields(string(content)) {
+		controllers[c] = true
+	}
+
+	if controllers["memory"] {
+		info.MemoryLimit = true
+		info.SwapLimit = true
+		info.MemoryReservation = true
+	} else {
+		warnings = append(warnings, "Unable to find memory controller in cgroup v2")
+	}
+
+	if controllers["cpu"] {
+		info.CPUShares = true
+		info.CPUCfsPeriod = true
+		info.CPUCfsQuota = true
+	} else {
+		warnings = append(warnings, "Unable to find cpu controller in cgroup v2")
+	}
+
+	if controllers["io"] {
+		info.BlkioWeight = true
+		info.BlkioWeightDevice = true
+		info.BlkioReadBpsDevice = true
+		info.BlkioWriteBpsDevice = true
+		info.BlkioReadIOpsDevice = true
+		info.BlkioWriteIOpsDevice = true
+	} else {
+		warnings = append(warnings, "Unable to find io controller in cgroup v2")
+	}
+
+	if controllers["cpuset"] {
+		info.Cpuset = true
+		if cpus, err := ioutil.ReadFile(path.Join(dir, "cpuset.cpus.effective")); err == nil {
+			info.Cpus = strings.TrimSpace(string(cpus))
+		}
+		if mems, err := ioutil.ReadFile(path.Join(dir, "cpuset.mems.effective")); err == nil {
+			info.Mems = strings.TrimSpace(string(mems))
+		}
+	} else {
+		warnings = append(warnings, "Unable to find cpuset controller in cgroup v2")
+	}
+
+	if controllers["pids"] {
+		info.PidsLimit = true
+	} else {
+		warnings = append(warnings, "Unable to find pids controller in cgroup v2")
+	}
+
+	return warnings
+}
@@ -0,0 +1,46 @@
+package sysinfo // import "github.com/docker/docker/pkg/sysinfo"
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+)
+
+func TestApplyCgroup2Controllers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup2-test")
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NilError(t, ioutil.WriteFile(path.Join(dir, "cgroup.controllers"), []byte("cpuset cpu io memory pids\n"), 0644))
+	assert.NilError(t, ioutil.WriteFile(path.Join(dir, "cpuset.cpus.effective"), []byte("0-3\n"), 0644))
+	assert.NilError(t, ioutil.WriteFile(path.Join(dir, "cpuset.mems.effective"), []byte("0\n"), 0644))
+
+	info := &SysInfo{}
+	warnings := applyCgroup2Controllers(info, dir)
+	assert.Check(t, is.Len(warnings, 0))
+	assert.Check(t, info.CgroupDevicesEnabled)
+	assert.Check(t, info.MemoryLimit && info.SwapLimit && info.MemoryReservation)
+	assert.Check(t, !info.KernelMemory && !info.OomKillDisable && !info.MemorySwappiness)
+	assert.Check(t, info.CPUShares && info.CPUCfsPeriod && info.CPUCfsQuota)
+	assert.Check(t, !info.CPURealtimePeriod && !info.CPURealtimeRuntime)
+	assert.Check(t, info.BlkioWeight && info.BlkioReadBpsDevice && info.BlkioWriteIOpsDevice)
+	assert.Check(t, info.PidsLimit)
+	assert.Check(t, info.Cpuset)
+	assert.Check(t, is.Equal(info.Cpus, "0-3"))
+	assert.Check(t, is.Equal(info.Mems, "0"))
+
+	assert.NilError(t, ioutil.WriteFile(path.Join(dir, "cgroup.controllers"), []byte("cpu pids\n"), 0644))
+	info = &SysInfo{}
+	warnings = applyCgroup2Controllers(info, dir)
+	assert.Check(t, is.DeepEqual(warnings, []string{
+		"Unable to find memory controller in cgroup v2",
+		"Unable to find io controller in cgroup v2",
+		"Unable to find cpuset controller in cgroup v2",
+	}))
+	assert.Check(t, !info.MemoryLimit && !info.BlkioWeight && !info.Cpuset)
+	assert.Check(t, info.CPUShares && info.PidsLimit)
+}
@@ -34,11 +34,15 @@ type infoCollector func(info *SysInfo, cgMounts map[string]string) (warnings []s
 func New(quiet bool) *SysInfo {
 	var ops []infoCollector
 	var warnings []string
+	var cgMounts map[string]string
 	sysInfo := &SysInfo{}
-	cgMounts, err := findCgroupMountpoints()
-	if err != nil {
+	if IsCgroup2UnifiedMode() {
+		sysInfo.CgroupUnified = true
+		ops = append(ops, applyCgroup2Info)
+	} else if mounts, err := findCgroupMountpoints(); err != nil {
 		logrus.Warn(err)
 	} else {
+		cgMounts = mounts
 		ops = append(ops, []infoCollector{
 			applyMemoryCgroupInfo,
 			applyCPUCgroupInfo,
@@ -7,3 +7,8 @@ func New(quiet bool) *SysInfo {
 	sysInfo := &SysInfo{}
 	return sysInfo
 }
+
+// IsCgroup2UnifiedMode returns false for non linux, which have no cgroups.
+func IsCgroup2UnifiedMode() bool {
+	return false
+}
@@ -19,10 +19,19 @@ const (
 // canceled and will no longer restart the container.
 var ErrRestartCanceled = errors.New("restart canceled")
 
+// ErrRestartLimitReached is returned when the container was restarted the
+// maximum number of times within the restart window of its policy.
+var ErrRestartLimitReached = errors.New("restart limit reached")
+
 // RestartManager defines object that controls container restarting rules.
 type RestartManager interface {
 	Cancel() error
 	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error)
+	ShouldRestartUnhealthy(executionDuration time.Duration) (bool, chan error, error)
+	// Delay returns the delay before the last restart.
+	Delay() time.Duration
+	// Restarts returns the times of the restarts within the restart window.
+	Restarts() []time.Time
 }
 
 type restartManager struct {
@@ -30,15 +39,22 @@ type restartManager struct {
 	sync.Once
 	policy       container.RestartPolicy
 	restartCount int
+	restarts     []time.Time // times of the restarts within the restart window
 	timeout      time.Duration
 	active       bool
 	cancel       chan struct{}
 	canceled     bool
 }
 
-// New returns a new restartManager based on a policy.
-func New(policy container.RestartPolicy, restartCount int) RestartManager {
-	return &restartManager{policy: policy, restartCount: restartCount, cancel: make(chan struct{})}
+// New returns a new restartManager based on a policy. restarts are the times
+// of the previous restarts, which count towards the restart window.
+func New(policy container.RestartPolicy, restartCount int, restarts []time.Time) RestartManager {
+	return &restartManager{
+		policy:       policy,
+		restartCount: restartCount,
+		restarts:     append([]time.Time(nil), restarts...),
+		cancel:       make(chan struct{}),
+	}
 }
 
 func (rm *restartManager) SetPolicy(policy container.RestartPolicy) {
@@ -51,6 +67,32 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	if rm.policy.IsNone() {
 		return false, nil, nil
 	}
+	return rm.shouldRestart(executionDuration, func() bool {
+		switch {
+		case rm.policy.IsAlways():
+			return true
+		case rm.policy.IsUnlessStopped() && !hasBeenManuallyStopped:
+			return true
+		case rm.policy.IsOnFailure():
+			// the default value of 0 for MaximumRetryCount means that we will not enforce a maximum count
+			if max := rm.policy.MaximumRetryCount; max == 0 || rm.restartCount < max {
+				return exitCode != 0
+			}
+		}
+		return false
+	})
+}
+
+// ShouldRestartUnhealthy is like ShouldRestart for a container which was
+// stopped by the daemon because it was unhealthy. The container is restarted
+// whatever its restart policy, with the same backoff.
+func (rm *restartManager) ShouldRestartUnhealthy(executionDuration time.Duration) (bool, chan error, error) {
+	return rm.shouldRestart(executionDuration, func() bool { return true })
+}
+
+// shouldRestart computes the backoff timeout, and restarts the container
+// after it if restart returns true. restart is called with rm locked.
+func (rm *restartManager) shouldRestart(executionDuration time.Duration, restart func() bool) (bool, chan error, error) {
 	rm.Lock()
 	unlockOnExit := true
 	defer func() {
@@ -66,6 +108,17 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	if rm.active {
 		return false, nil, fmt.Errorf("invalid call on an active restart manager")
 	}
+	initialTimeout, maxTimeout := defaultTimeout, maxRestartTimeout
+	if rm.policy.InitialDelay > 0 {
+		initialTimeout = rm.policy.InitialDelay
+	}
+	if rm.policy.MaximumDelay > 0 {
+		maxTimeout = rm.policy.MaximumDelay
+	}
+	if maxTimeout < initialTimeout {
+		maxTimeout = initialTimeout
+	}
+
 	// if the container ran for more than 10s, regardless of status and policy reset the
 	// the timeout back to the default.
 	if executionDuration.Seconds() >= 10 {
@@ -73,41 +126,40 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	}
 	switch {
 	case rm.timeout == 0:
-		rm.timeout = defaultTimeout
-	case rm.timeout < maxRestartTimeout:
+		rm.timeout = initialTimeout
+	case rm.timeout < maxTimeout:
 		rm.timeout *= backoffMultiplier
 	}
-	if rm.timeout > maxRestartTimeout {
-		rm.timeout = maxRestartTimeout
-	}
-
-	var restart bool
-	switch {
-	case rm.policy.IsAlways():
-		restart = true
-	case rm.policy.IsUnlessStopped() && !hasBeenManuallyStopped:
-		restart = true
-	case rm.policy.IsOnFailure():
-		// the default value of 0 for MaximumRetryCount means that we will not enforce a maximum count
-		if max := rm.policy.MaximumRetryCount; max == 0 || rm.restartCount < max {
-			restart = exitCode != 0
-		}
+	if rm.timeout > maxTimeout {
+		rm.timeout = maxTimeout
 	}
 
-	if !restart {
+	if !restart() {
 		rm.active = false
 		return false, nil, nil
 	}
 
+	if max := rm.policy.MaximumRestartsInWindow; max > 0 && rm.policy.RestartWindow > 0 {
+		now := time.Now()
+		for len(rm.restarts) > 0 && now.Sub(rm.restarts[0]) >= rm.policy.RestartWindow {
+			rm.restarts = rm.restarts[1:]
+		}
+		if len(rm.restarts) >= max {
+			return false, nil, ErrRestartLimitReached
+		}
+		rm.restarts = append(rm.restarts, now)
+	}
+
 	rm.restartCount++
 
 	unlockOnExit = false
 	rm.active = true
+	delay := rm.timeout
 	rm.Unlock()
 
 	ch := make(chan error)
 	go func() {
-		timeout := time.NewTimer(rm.timeout)
+		timeout := time.NewTimer(delay)
 		defer timeout.Stop()
 
 		select {
@@ -125,6 +177,18 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 	return true, ch, nil
 }
 
+func (rm *restartManager) Delay() time.Duration {
+	rm.Lock()
+	defer rm.Unlock()
+	return rm.timeout
+}
+
+func (rm *restartManager) Restarts() []time.Time {
+	rm.Lock()
+	defer rm.Unlock()
+	return append([]time.Time(nil), rm.restarts...)
+}
+
 func (rm *restartManager) Cancel() error {
 	rm.Do(func() {
 		rm.Lock()
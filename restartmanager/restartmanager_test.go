@@ -8,7 +8,7 @@ import (
 )
 
 func TestRestartManagerTimeout(t *testing.T) {
-	rm := New(container.RestartPolicy{Name: "always"}, 0).(*restartManager)
+	rm := New(container.RestartPolicy{Name: "always"}, 0, nil).(*restartManager)
 	var duration = time.Duration(1 * time.Second)
 	should, _, err := rm.ShouldRestart(0, false, duration)
 	if err != nil {
@@ -23,7 +23,7 @@ func TestRestartManagerTimeout(t *testing.T) {
 }
 
 func TestRestartManagerTimeoutReset(t *testing.T) {
-	rm := New(container.RestartPolicy{Name: "always"}, 0).(*restartManager)
+	rm := New(container.RestartPolicy{Name: "always"}, 0, nil).(*restartManager)
 	rm.timeout = 5 * time.Second
 	var duration = time.Duration(10 * time.Second)
 	_, _, err := rm.ShouldRestart(0, false, duration)
@@ -34,3 +34,105 @@ func TestRestartManagerTimeoutReset(t *testing.T) {
 		t.Fatalf("restart manager should have a timeout of 100 ms but has %s", rm.timeout)
 	}
 }
+
+func TestRestartManagerUnhealthy(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "no"}, 0, nil).(*restartManager)
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if should {
+		t.Fatal("container should not be restarted")
+	}
+
+	should, _, err = rm.ShouldRestartUnhealthy(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("unhealthy container should be restarted")
+	}
+	if rm.restartCount != 1 || rm.timeout != defaultTimeout {
+		t.Fatalf("unexpected restart count %d and timeout %s", rm.restartCount, rm.timeout)
+	}
+}
+
+// deactivate allows to call ShouldRestart again without waiting for the
+// pending restart.
+func (rm *restartManager) deactivate() {
+	rm.Lock()
+	rm.active = false
+	rm.Unlock()
+}
+
+func TestRestartManagerDelays(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always", InitialDelay: time.Second, MaximumDelay: 3 * time.Second}, 0, nil).(*restartManager)
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		should, _, err := rm.ShouldRestart(0, false, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !should {
+			t.Fatal("container should be restarted")
+		}
+		if rm.Delay() != expected {
+			t.Fatalf("restart manager should have a delay of %s but has %s", expected, rm.Delay())
+		}
+		rm.deactivate()
+	}
+
+	// the delay is reset after a healthy run
+	if _, _, err := rm.ShouldRestart(0, false, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if rm.Delay() != time.Second {
+		t.Fatalf("restart manager should have a delay of 1s but has %s", rm.Delay())
+	}
+}
+
+func TestRestartManagerWindow(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always", MaximumRestartsInWindow: 2, RestartWindow: time.Hour}, 0, nil).(*restartManager)
+	for i := 0; i < 2; i++ {
+		should, _, err := rm.ShouldRestart(0, false, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !should {
+			t.Fatal("container should be restarted")
+		}
+		rm.deactivate()
+	}
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != ErrRestartLimitReached || should {
+		t.Fatalf("expected the restart limit to be reached, got %v, %v", should, err)
+	}
+
+	// restarts older than the window are not counted
+	rm.restarts[0] = time.Now().Add(-2 * time.Hour)
+	should, _, err = rm.ShouldRestart(0, false, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !should {
+		t.Fatal("container should be restarted")
+	}
+}
+
+func TestRestartManagerWindowRestored(t *testing.T) {
+	policy := container.RestartPolicy{Name: "always", MaximumRestartsInWindow: 2, RestartWindow: time.Hour}
+	rm := New(policy, 0, nil).(*restartManager)
+	for i := 0; i < 2; i++ {
+		if _, _, err := rm.ShouldRestart(0, false, time.Second); err != nil {
+			t.Fatal(err)
+		}
+		rm.deactivate()
+	}
+
+	// the restarts within the window are kept by a new restart manager, as
+	// when the daemon is restarted
+	rm = New(policy, 2, rm.Restarts()).(*restartManager)
+	should, _, err := rm.ShouldRestart(0, false, time.Second)
+	if err != ErrRestartLimitReached || should {
+		t.Fatalf("expected the restart limit to be reached, got %v, %v", should, err)
+	}
+}
@@ -0,0 +1,16 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/copy"
+	"github.com/docker/docker/errdefs"
+)
+
+// copyData copies the content of the src directory to dst, preserving
+// ownership, permissions and extended attributes. Regular files are cloned
+// using reflinks when the filesystem supports it.
+func copyData(src, dst string) error {
+	if err := copy.DirCopy(src, dst, copy.Content, true); err != nil {
+		return errdefs.System(err)
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/errdefs"
+	"github.com/pkg/errors"
+)
+
+func copyData(src, dst string) error {
+	return errdefs.NotImplemented(errors.New("cloning volumes is not supported on this platform"))
+}
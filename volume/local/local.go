@@ -19,6 +19,7 @@ import (
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/volume"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // VolumeDataPathName is the name of the directory where the volume data is stored.
@@ -89,6 +90,14 @@ func New(scope string, rootIdentity idtools.Identity) (*Root, error) {
 				v.opts = &opts
 			}
 
+			if v.quotaSize() > 0 {
+				ctl, err := r.getQuotaCtl()
+				if err != nil {
+					logrus.WithError(err).WithField("volume", name).Warn("Unable to restore the size limit of volume")
+				}
+				v.quotaCtl = ctl
+			}
+
 			// unmount anything that may still be mounted (for example, from an unclean shutdown)
 			mount.Unmount(v.path)
 		}
@@ -105,7 +114,62 @@ type Root struct {
 	scope        string
 	path         string
 	volumes      map[string]*localVolume
+	cloning      map[string]struct{} // names reserved by clones in progress
 	rootIdentity idtools.Identity
+	quotaOnce    sync.Once
+	quotaCtl     quotaController
+	quotaErr     error
+}
+
+// quotaController limits and reports the disk usage of volume directories.
+type quotaController interface {
+	// SetQuota limits the size of dir (including its future content) to size bytes.
+	SetQuota(dir string, size uint64) error
+	// GetQuota returns the size limit and the number of bytes used of dir.
+	GetQuota(dir string) (limit, usage uint64, err error)
+	// MoveQuota updates the bookkeeping of dir after it has been renamed.
+	MoveQuota(oldDir, newDir string)
+}
+
+// getQuotaCtl returns the quota controller for the volumes root, initializing
+// it on first use.
+func (r *Root) getQuotaCtl() (quotaController, error) {
+	r.quotaOnce.Do(func() {
+		r.quotaCtl, r.quotaErr = newQuotaController(r.path)
+	})
+	return r.quotaCtl, r.quotaErr
+}
+
+// createDataPath creates the directories of the given volume. If the volume
+// has a size limit, the quota is applied to the volume directory before the
+// data directory is created, so that the data directory inherits it.
+func (r *Root) createDataPath(v *localVolume) error {
+	if size := v.quotaSize(); size > 0 {
+		dir := filepath.Dir(v.path)
+		if err := idtools.MkdirAllAndChown(dir, 0755, r.rootIdentity); err != nil {
+			return errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", dir)
+		}
+		ctl, err := r.getQuotaCtl()
+		if err == nil {
+			err = ctl.SetQuota(dir, size)
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			return quotaError(err)
+		}
+		v.quotaCtl = ctl
+	}
+	if err := idtools.MkdirAllAndChown(v.path, 0755, r.rootIdentity); err != nil {
+		return errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", v.path)
+	}
+	return nil
+}
+
+func quotaError(err error) error {
+	if errdefs.IsNotImplemented(err) {
+		return errors.Wrap(err, "the size option requires the backing filesystem of the volumes directory to support project quotas")
+	}
+	return errdefs.System(errors.Wrap(err, "error while setting the size limit of volume"))
 }
 
 // List lists all the volumes
@@ -144,19 +208,11 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 	if exists {
 		return v, nil
 	}
-
-	path := r.DataPath(name)
-	if err := idtools.MkdirAllAndChown(path, 0755, r.rootIdentity); err != nil {
-		return nil, errors.Wrapf(errdefs.System(err), "error while creating volume path '%s'", path)
+	if _, cloning := r.cloning[name]; cloning {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s is being created", name))
 	}
 
-	var err error
-	defer func() {
-		if err != nil {
-			os.RemoveAll(filepath.Dir(path))
-		}
-	}()
-
+	path := r.DataPath(name)
 	v = &localVolume{
 		driverName: r.Name(),
 		name:       name,
@@ -164,16 +220,25 @@ func (r *Root) Create(name string, opts map[string]string) (volume.Volume, error
 	}
 
 	if len(opts) != 0 {
-		if err = setOpts(v, opts); err != nil {
+		if err := setOpts(v, opts); err != nil {
 			return nil, err
 		}
-		var b []byte
-		b, err = json.Marshal(v.opts)
+	}
+
+	if err := r.createDataPath(v); err != nil {
+		return nil, err
+	}
+
+	var err error
+	defer func() {
 		if err != nil {
-			return nil, err
+			os.RemoveAll(filepath.Dir(path))
 		}
-		if err = ioutil.WriteFile(filepath.Join(filepath.Dir(path), "opts.json"), b, 600); err != nil {
-			return nil, errdefs.System(errors.Wrap(err, "error while persisting volume options"))
+	}()
+
+	if v.opts != nil {
+		if err = v.saveOpts(); err != nil {
+			return nil, err
 		}
 	}
 
@@ -243,6 +308,178 @@ func (r *Root) Get(name string) (volume.Volume, error) {
 	return v, nil
 }
 
+// Clone creates a new volume with the given name, holding a copy of the data
+// stored in v. Where the backing filesystem supports it (e.g. btrfs or xfs),
+// the data is copied using reflinks, making the clone a cheap copy-on-write
+// snapshot of the original volume.
+func (r *Root) Clone(v volume.Volume, name string) (volume.Volume, error) {
+	if err := r.validateName(name); err != nil {
+		return nil, err
+	}
+
+	lv, ok := v.(*localVolume)
+	if !ok {
+		return nil, errdefs.System(errors.Errorf("unknown volume type %T", v))
+	}
+	if lv.needsMount() {
+		return nil, errdefs.InvalidParameter(errors.Errorf("volume %s has mount options and cannot be cloned", lv.name))
+	}
+
+	// The name is reserved while the data is copied, so that the other
+	// operations on the volumes of the driver are not blocked by the copy.
+	r.m.Lock()
+	if err := r.checkNameAvailable(name); err != nil {
+		r.m.Unlock()
+		return nil, err
+	}
+	if r.cloning == nil {
+		r.cloning = make(map[string]struct{})
+	}
+	r.cloning[name] = struct{}{}
+	r.m.Unlock()
+
+	nv, err := r.cloneData(lv, name)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	delete(r.cloning, name)
+	if err != nil {
+		return nil, err
+	}
+	r.volumes[name] = nv
+	return nv, nil
+}
+
+// checkNameAvailable returns a Conflict error if a volume with the given
+// name exists or is being cloned. The caller must hold r.m.
+func (r *Root) checkNameAvailable(name string) error {
+	if _, exists := r.volumes[name]; exists {
+		return errdefs.Conflict(errors.Errorf("volume %s already exists", name))
+	}
+	if _, cloning := r.cloning[name]; cloning {
+		return errdefs.Conflict(errors.Errorf("volume %s is being created", name))
+	}
+	return nil
+}
+
+// cloneData creates the directory of the clone of lv with the given name,
+// and copies the data of lv into it.
+func (r *Root) cloneData(lv *localVolume, name string) (*localVolume, error) {
+	path := r.DataPath(name)
+	nv := &localVolume{
+		driverName: r.Name(),
+		name:       name,
+		path:       path,
+	}
+	if lv.opts != nil {
+		// the clone gets the same size limit as the original volume
+		opts := *lv.opts
+		nv.opts = &opts
+	}
+
+	if err := r.createDataPath(nv); err != nil {
+		return nil, err
+	}
+
+	if err := copyData(lv.path, path); err != nil {
+		os.RemoveAll(filepath.Dir(path))
+		return nil, errors.Wrapf(err, "error while copying data of volume %s", lv.name)
+	}
+	if nv.opts != nil {
+		if err := nv.saveOpts(); err != nil {
+			os.RemoveAll(filepath.Dir(path))
+			return nil, err
+		}
+	}
+	return nv, nil
+}
+
+// Rename changes the name of the given volume by moving its directory within
+// the volume root. Volumes which are currently mounted cannot be renamed.
+func (r *Root) Rename(v volume.Volume, newName string) (volume.Volume, error) {
+	if err := r.validateName(newName); err != nil {
+		return nil, err
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	lv, ok := v.(*localVolume)
+	if !ok {
+		return nil, errdefs.System(errors.Errorf("unknown volume type %T", v))
+	}
+	if err := r.checkNameAvailable(newName); err != nil {
+		return nil, err
+	}
+
+	lv.m.Lock()
+	defer lv.m.Unlock()
+
+	if lv.active.count > 0 || lv.active.mounted {
+		return nil, errdefs.Conflict(errors.Errorf("volume %s has active mounts", lv.name))
+	}
+
+	newPath := r.DataPath(newName)
+	if err := os.Rename(filepath.Dir(lv.path), filepath.Dir(newPath)); err != nil {
+		return nil, errdefs.System(errors.Wrapf(err, "error while renaming volume %s to %s", lv.name, newName))
+	}
+
+	if lv.quotaCtl != nil {
+		lv.quotaCtl.MoveQuota(filepath.Dir(lv.path), filepath.Dir(newPath))
+	}
+
+	delete(r.volumes, lv.name)
+	lv.name = newName
+	lv.path = newPath
+	r.volumes[newName] = lv
+	return lv, nil
+}
+
+// Update replaces the options of the given volume. Options can only be
+// changed for volumes that were created with options of the same kind, i.e.
+// mount options or a size limit. If the volume is currently mounted, it is
+// remounted with the new options, in which case only the generic mount
+// options ("o") may be changed.
+func (r *Root) Update(v volume.Volume, opts map[string]string) error {
+	lv, ok := v.(*localVolume)
+	if !ok {
+		return errdefs.System(errors.Errorf("unknown volume type %T", v))
+	}
+
+	lv.m.Lock()
+	defer lv.m.Unlock()
+
+	if lv.opts == nil && len(opts) == 0 {
+		return nil
+	}
+
+	updated := &localVolume{name: lv.name, path: lv.path}
+	if err := setOpts(updated, opts); err != nil {
+		return err
+	}
+	if lv.needsMount() != updated.needsMount() || (lv.quotaSize() > 0) != (updated.quotaSize() > 0) {
+		return errdefs.InvalidParameter(errors.Errorf("cannot add or remove the options of volume %s, the volume must be recreated", lv.name))
+	}
+	if lv.active.mounted {
+		if err := lv.remount(updated.opts); err != nil {
+			return err
+		}
+	}
+	if size := updated.quotaSize(); size > 0 {
+		if lv.quotaCtl == nil {
+			return errdefs.System(errors.Errorf("the size limit of volume %s could not be restored", lv.name))
+		}
+		if err := lv.quotaCtl.SetQuota(filepath.Dir(lv.path), size); err != nil {
+			return quotaError(err)
+		}
+	}
+	if err := updated.saveOpts(); err != nil {
+		return err
+	}
+	lv.opts = updated.opts
+	return nil
+}
+
 // Scope returns the local volume scope
 func (r *Root) Scope() string {
 	return volume.LocalScope
@@ -272,6 +509,8 @@ type localVolume struct {
 	opts *optsConfig
 	// active refcounts the active mounts
 	active activeMount
+	// quotaCtl is used to report the usage of volumes with a size limit
+	quotaCtl quotaController
 }
 
 // Name returns the name of the given Volume.
@@ -299,7 +538,7 @@ func (v *localVolume) CachedPath() string {
 func (v *localVolume) Mount(id string) (string, error) {
 	v.m.Lock()
 	defer v.m.Unlock()
-	if v.opts != nil {
+	if v.needsMount() {
 		if !v.active.mounted {
 			if err := v.mount(); err != nil {
 				return "", errdefs.System(err)
@@ -321,7 +560,7 @@ func (v *localVolume) Unmount(id string) error {
 	// Essentially docker doesn't care if this fails, it will send an error, but
 	// ultimately there's nothing that can be done. If we don't decrement the count
 	// this volume can never be removed until a daemon restart occurs.
-	if v.opts != nil {
+	if v.needsMount() {
 		v.active.count--
 	}
 
@@ -333,7 +572,7 @@ func (v *localVolume) Unmount(id string) error {
 }
 
 func (v *localVolume) unmount() error {
-	if v.opts != nil {
+	if v.needsMount() {
 		if err := mount.Unmount(v.path); err != nil {
 			if mounted, mErr := mount.Mounted(v.path); mounted || mErr != nil {
 				return errdefs.System(err)
@@ -344,6 +583,48 @@ func (v *localVolume) unmount() error {
 	return nil
 }
 
+// needsMount returns whether the data of the volume lives on a filesystem
+// which is mounted by the driver.
+func (v *localVolume) needsMount() bool {
+	return v.opts != nil && v.opts.hasMount()
+}
+
+// quotaSize returns the size limit of the volume, or 0 if it has none.
+func (v *localVolume) quotaSize() uint64 {
+	if v.opts == nil {
+		return 0
+	}
+	return v.opts.quotaSize()
+}
+
+// Quota returns the size limit of the volume and the number of bytes it
+// uses, as accounted by the filesystem quota. A zero limit is returned for
+// volumes without a size limit.
+func (v *localVolume) Quota() (limit, usage uint64, err error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+	if v.quotaSize() == 0 {
+		return 0, 0, nil
+	}
+	if v.quotaCtl == nil {
+		return 0, 0, errdefs.System(errors.Errorf("the size limit of volume %s could not be restored", v.name))
+	}
+	return v.quotaCtl.GetQuota(filepath.Dir(v.path))
+}
+
+// saveOpts persists the options of the volume, so that they are restored
+// when the driver is re-initialized.
+func (v *localVolume) saveOpts() error {
+	b, err := json.Marshal(v.opts)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(filepath.Dir(v.path), "opts.json"), b, 600); err != nil {
+		return errdefs.System(errors.Wrap(err, "error while persisting volume options"))
+	}
+	return nil
+}
+
 func (v *localVolume) Status() map[string]interface{} {
 	return nil
 }
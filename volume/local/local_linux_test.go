@@ -0,0 +1,196 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/mount"
+	"gotest.tools/assert"
+	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
+)
+
+type fakeQuotaController struct {
+	limits map[string]uint64
+}
+
+func (q *fakeQuotaController) SetQuota(dir string, size uint64) error {
+	q.limits[dir] = size
+	return nil
+}
+
+func (q *fakeQuotaController) GetQuota(dir string) (uint64, uint64, error) {
+	return q.limits[dir], 42, nil
+}
+
+func (q *fakeQuotaController) MoveQuota(oldDir, newDir string) {
+	q.limits[newDir] = q.limits[oldDir]
+	delete(q.limits, oldDir)
+}
+
+func newQuotaTestRoot(t *testing.T, ctl quotaController) (*Root, func()) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	assert.NilError(t, err)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	if ctl != nil {
+		r.quotaOnce.Do(func() { r.quotaCtl = ctl })
+	}
+	return r, func() { os.RemoveAll(rootDir) }
+}
+
+func TestCreateWithSize(t *testing.T) {
+	ctl := &fakeQuotaController{limits: make(map[string]uint64)}
+	r, cleanup := newQuotaTestRoot(t, ctl)
+	defer cleanup()
+
+	_, err := r.Create("invalid", map[string]string{"size": "notasize"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	_, err = r.Create("invalid", map[string]string{"size": "0"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	_, err = r.Create("invalid", map[string]string{"size": "10m", "type": "tmpfs", "device": "tmpfs"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	_, err = os.Stat(filepath.Join(r.path, "invalid"))
+	assert.Check(t, os.IsNotExist(err), "expected volume directory to be cleaned up")
+
+	vol, err := r.Create("limited", map[string]string{"size": "10m"})
+	assert.NilError(t, err)
+	v := vol.(*localVolume)
+	assert.Check(t, !v.needsMount())
+	assert.Check(t, is.Equal(ctl.limits[filepath.Dir(v.Path())], uint64(10*1024*1024)))
+
+	limit, usage, err := v.Quota()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(limit, uint64(10*1024*1024)))
+	assert.Check(t, is.Equal(usage, uint64(42)))
+
+	// volumes with a size limit are not mounted by the driver
+	p, err := v.Mount("1234")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(p, v.Path()))
+	assert.Check(t, is.Equal(v.active.count, uint64(0)))
+	assert.NilError(t, v.Unmount("1234"))
+
+	// the size limit is carried over to clones
+	clone, err := r.Clone(v, "limited-clone")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(ctl.limits[filepath.Dir(clone.Path())], uint64(10*1024*1024)))
+
+	assert.NilError(t, r.Update(v, map[string]string{"size": "20m"}))
+	assert.Check(t, is.Equal(ctl.limits[filepath.Dir(v.Path())], uint64(20*1024*1024)))
+	err = r.Update(v, map[string]string{"type": "tmpfs", "device": "tmpfs"})
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+	err = r.Update(v, nil)
+	assert.Check(t, errdefs.IsInvalidParameter(err), err)
+
+	oldDir := filepath.Dir(v.Path())
+	renamed, err := r.Rename(v, "limited-renamed")
+	assert.NilError(t, err)
+	_, moved := ctl.limits[oldDir]
+	assert.Check(t, !moved)
+	assert.Check(t, is.Equal(ctl.limits[filepath.Dir(renamed.Path())], uint64(20*1024*1024)))
+
+	// make sure the size limit is persisted
+	r, err = New(filepath.Dir(r.path), idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	restored, exists := r.volumes["limited-renamed"]
+	assert.Assert(t, exists)
+	assert.Check(t, is.Equal(restored.quotaSize(), uint64(20*1024*1024)))
+}
+
+func TestCreateWithSizeUnsupported(t *testing.T) {
+	r, cleanup := newQuotaTestRoot(t, nil)
+	defer cleanup()
+
+	_, err := r.Create("limited", map[string]string{"size": "10m"})
+	if err == nil {
+		t.Skip("the backing filesystem supports project quotas")
+	}
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+	_, err = r.Get("limited")
+	assert.Check(t, err != nil, "expected volume not to be created")
+}
+
+func TestUpdateWithOpts(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires mounts")
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := r.Create("plain", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Update(plain, map[string]string{"device": "tmpfs", "type": "tmpfs", "o": "size=1m"}); err == nil {
+		t.Fatal("expected adding options to a volume without options to fail")
+	}
+
+	vol, err := r.Create("test", map[string]string{"device": "tmpfs", "type": "tmpfs", "o": "size=1m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := vol.(*localVolume)
+
+	if err := r.Update(v, map[string]string{"invalidopt": "notsupported"}); err == nil {
+		t.Fatal("expected invalid opt to cause error")
+	}
+
+	dir, err := v.Mount("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := v.Unmount("1234"); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := ioutil.WriteFile(filepath.Join(dir, "data"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Update(v, map[string]string{"device": "other", "type": "tmpfs", "o": "size=2m"}); err == nil {
+		t.Fatal("expected changing the device of a mounted volume to fail")
+	}
+	if err := r.Update(v, map[string]string{"device": "tmpfs", "type": "tmpfs", "o": "size=2m"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mountInfos, err := mount.GetMounts(mount.SingleEntryFilter(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mountInfos) != 1 {
+		t.Fatalf("expected 1 mount, found %d: %+v", len(mountInfos), mountInfos)
+	}
+	if !strings.Contains(mountInfos[0].VfsOpts, "size=2048k") {
+		t.Fatalf("expected mount info to have size=2048k: %q", mountInfos[0].VfsOpts)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data")); err != nil {
+		t.Fatalf("expected data to survive the update: %v", err)
+	}
+
+	r, err = New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, exists := r.volumes["test"]
+	if !exists {
+		t.Fatal("missing volume on restart")
+	}
+	if v2.opts.MountOpts != "size=2m" {
+		t.Fatalf("expected updated options to be persisted, got %q", v2.opts.MountOpts)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/idtools"
 	"github.com/docker/docker/pkg/mount"
 	"gotest.tools/skip"
@@ -155,6 +156,130 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	skip.If(t, runtime.GOOS != "linux", "cloning volumes is only supported on Linux")
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vol, err := r.Create("source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(vol.Path(), "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(vol.Path(), "dir", "file"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := r.Clone(vol, "clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Name() != "clone" {
+		t.Fatalf("expected volume with name clone, got %s", clone.Name())
+	}
+	b, err := ioutil.ReadFile(filepath.Join(clone.Path(), "dir", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Fatalf("expected cloned file to contain %q, got %q", "hello", string(b))
+	}
+
+	// changes to the clone must not affect the source volume
+	if err := ioutil.WriteFile(filepath.Join(clone.Path(), "dir", "file"), []byte("world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err = ioutil.ReadFile(filepath.Join(vol.Path(), "dir", "file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Fatalf("expected source file to contain %q, got %q", "hello", string(b))
+	}
+
+	if _, err := r.Clone(vol, "clone"); err == nil {
+		t.Fatal("expected an error when cloning to an existing volume name")
+	}
+	if _, err := r.Clone(vol, "../escape"); err == nil {
+		t.Fatal("expected an error when cloning to an invalid volume name")
+	}
+
+	// the name of a clone in progress is reserved
+	r.m.Lock()
+	r.cloning = map[string]struct{}{"pending": {}}
+	r.m.Unlock()
+	if _, err := r.Create("pending", nil); !errdefs.IsConflict(err) {
+		t.Fatalf("expected a conflict when creating a volume being cloned, got %v", err)
+	}
+	if _, err := r.Clone(vol, "pending"); !errdefs.IsConflict(err) {
+		t.Fatalf("expected a conflict when cloning to a volume being cloned, got %v", err)
+	}
+}
+
+func TestRename(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "local-volume-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rootDir)
+
+	r, err := New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vol, err := r.Create("before", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(vol.Path(), "file"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Create("taken", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Rename(vol, "taken"); err == nil {
+		t.Fatal("expected an error when renaming to an existing volume name")
+	}
+
+	renamed, err := r.Rename(vol, "after")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name() != "after" {
+		t.Fatalf("expected volume with name after, got %s", renamed.Name())
+	}
+	if renamed.Path() != r.DataPath("after") {
+		t.Fatalf("expected volume path %s, got %s", r.DataPath("after"), renamed.Path())
+	}
+	if _, err := os.Stat(filepath.Join(renamed.Path(), "file")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Get("before"); err == nil {
+		t.Fatal("expected old volume name to be gone")
+	}
+
+	// make sure the rename is persisted
+	r, err = New(rootDir, idtools.Identity{UID: os.Geteuid(), GID: os.Getegid()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Get("after"); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestValidateName(t *testing.T) {
 	r := &Root{}
 	names := map[string]bool{
@@ -16,6 +16,7 @@ import (
 
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/mount"
+	"github.com/docker/go-units"
 	"github.com/pkg/errors"
 )
 
@@ -26,6 +27,7 @@ var (
 		"type":   {}, // specify the filesystem type for mount, e.g. nfs
 		"o":      {}, // generic mount options
 		"device": {}, // device to mount from
+		"size":   {}, // size limit of the volume, applied as a project quota
 	}
 	mandatoryOpts = map[string]struct{}{
 		"device": {},
@@ -37,12 +39,24 @@ type optsConfig struct {
 	MountType   string
 	MountOpts   string
 	MountDevice string
+	Size        uint64 `json:",omitempty"`
 }
 
 func (o *optsConfig) String() string {
+	if o.Size > 0 {
+		return fmt.Sprintf("size='%d'", o.Size)
+	}
 	return fmt.Sprintf("type='%s' device='%s' o='%s'", o.MountType, o.MountDevice, o.MountOpts)
 }
 
+func (o *optsConfig) hasMount() bool {
+	return o.MountType != "" || o.MountDevice != "" || o.MountOpts != ""
+}
+
+func (o *optsConfig) quotaSize() uint64 {
+	return o.Size
+}
+
 // scopedPath verifies that the path where the volume is located
 // is under Docker's root and the valid local paths.
 func (r *Root) scopedPath(realPath string) bool {
@@ -67,6 +81,18 @@ func setOpts(v *localVolume, opts map[string]string) error {
 		return err
 	}
 
+	if val, ok := opts["size"]; ok {
+		size, err := units.RAMInBytes(val)
+		if err != nil {
+			return errdefs.InvalidParameter(errors.Wrapf(err, "invalid size: %q", val))
+		}
+		if size <= 0 {
+			return errdefs.InvalidParameter(errors.Errorf("invalid size: %q, size must be greater than zero", val))
+		}
+		v.opts = &optsConfig{Size: uint64(size)}
+		return nil
+	}
+
 	v.opts = &optsConfig{
 		MountType:   opts["type"],
 		MountOpts:   opts["o"],
@@ -84,6 +110,12 @@ func validateOpts(opts map[string]string) error {
 			return errdefs.InvalidParameter(errors.Errorf("invalid option: %q", opt))
 		}
 	}
+	if _, ok := opts["size"]; ok {
+		if len(opts) > 1 {
+			return errdefs.InvalidParameter(errors.New("the size option cannot be combined with mount options"))
+		}
+		return nil
+	}
 	for opt := range mandatoryOpts {
 		if _, ok := opts[opt]; !ok {
 			return errdefs.InvalidParameter(errors.Errorf("missing required option: %q", opt))
@@ -110,6 +142,22 @@ func (v *localVolume) mount() error {
 	return errors.Wrap(err, "failed to mount local volume")
 }
 
+// remount applies new options to a volume which is currently mounted.
+// Only the generic mount options can be changed on a mounted volume.
+func (v *localVolume) remount(opts *optsConfig) error {
+	if opts.MountType != v.opts.MountType || opts.MountDevice != v.opts.MountDevice {
+		return errdefs.Conflict(errors.Errorf("cannot change the type or device of volume %s while it is mounted", v.name))
+	}
+	mountOpts := "remount"
+	if opts.MountOpts != "" {
+		mountOpts += "," + opts.MountOpts
+	}
+	if err := mount.Mount(opts.MountDevice, v.path, opts.MountType, mountOpts); err != nil {
+		return errdefs.System(errors.Wrap(err, "failed to remount local volume"))
+	}
+	return nil
+}
+
 func (v *localVolume) CreatedAt() (time.Time, error) {
 	fileInfo, err := os.Stat(v.path)
 	if err != nil {
@@ -16,6 +16,14 @@ import (
 
 type optsConfig struct{}
 
+func (o *optsConfig) hasMount() bool {
+	return false
+}
+
+func (o *optsConfig) quotaSize() uint64 {
+	return 0
+}
+
 // scopedPath verifies that the path where the volume is located
 // is under Docker's root and the valid local paths.
 func (r *Root) scopedPath(realPath string) bool {
@@ -36,6 +44,10 @@ func (v *localVolume) mount() error {
 	return nil
 }
 
+func (v *localVolume) remount(opts *optsConfig) error {
+	return nil
+}
+
 func (v *localVolume) CreatedAt() (time.Time, error) {
 	fileInfo, err := os.Stat(v.path)
 	if err != nil {
@@ -0,0 +1,38 @@
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/quota"
+)
+
+// projectQuota implements quotaController using XFS project quotas.
+type projectQuota struct {
+	ctl *quota.Control
+}
+
+func newQuotaController(root string) (quotaController, error) {
+	ctl, err := quota.NewControl(root)
+	if err != nil {
+		return nil, err
+	}
+	return &projectQuota{ctl: ctl}, nil
+}
+
+func (q *projectQuota) SetQuota(dir string, size uint64) error {
+	return q.ctl.SetQuota(dir, quota.Quota{Size: size})
+}
+
+func (q *projectQuota) GetQuota(dir string) (uint64, uint64, error) {
+	var limit quota.Quota
+	if err := q.ctl.GetQuota(dir, &limit); err != nil {
+		return 0, 0, err
+	}
+	usage, err := q.ctl.GetUsage(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	return limit.Size, usage, nil
+}
+
+func (q *projectQuota) MoveQuota(oldDir, newDir string) {
+	q.ctl.MoveQuota(oldDir, newDir)
+}
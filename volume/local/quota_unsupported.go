@@ -0,0 +1,11 @@
+// +build !linux
+
+package local // import "github.com/docker/docker/volume/local"
+
+import (
+	"github.com/docker/docker/daemon/graphdriver/quota"
+)
+
+func newQuotaController(root string) (quotaController, error) {
+	return nil, quota.ErrQuotaNotSupported
+}
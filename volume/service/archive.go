@@ -0,0 +1,96 @@
+package service // import "github.com/docker/docker/volume/service"
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/chrootarchive"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/volume"
+	"github.com/docker/docker/volume/service/opts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Export writes a tar stream with the content of the named volume to out,
+// compressed with the given compression. File ownership is mapped from the
+// host to the daemon's remapped root, if any.
+func (s *VolumesService) Export(ctx context.Context, name string, compression archive.Compression, out io.Writer) error {
+	return s.withMountedVolume(ctx, name, func(v volume.Volume, path string) error {
+		data, err := archive.TarWithOptions(path, &archive.TarOptions{
+			Compression: compression,
+			UIDMaps:     s.uidMaps(),
+			GIDMaps:     s.gidMaps(),
+		})
+		if err != nil {
+			return err
+		}
+		defer data.Close()
+
+		if _, err := io.Copy(out, data); err != nil {
+			return errors.Wrapf(err, "error exporting volume %s", v.Name())
+		}
+		s.eventLogger.LogVolumeEvent(v.Name(), "export", map[string]string{"driver": v.DriverName()})
+		return nil
+	})
+}
+
+// Import extracts the (optionally compressed) tar stream read from src into
+// the named volume. Existing files in the volume are overwritten. File
+// ownership is mapped from the daemon's remapped root, if any, to the host.
+func (s *VolumesService) Import(ctx context.Context, name string, src io.Reader) error {
+	return s.withMountedVolume(ctx, name, func(v volume.Volume, path string) error {
+		if err := chrootarchive.Untar(src, path, &archive.TarOptions{
+			UIDMaps: s.uidMaps(),
+			GIDMaps: s.gidMaps(),
+		}); err != nil {
+			return errors.Wrapf(err, "error importing volume %s", v.Name())
+		}
+		s.eventLogger.LogVolumeEvent(v.Name(), "import", map[string]string{"driver": v.DriverName()})
+		return nil
+	})
+}
+
+// withMountedVolume mounts the named volume and calls fn with the path of
+// the mounted volume. The volume holds a reference while fn is running, so
+// that it cannot be removed.
+func (s *VolumesService) withMountedVolume(ctx context.Context, name string, fn func(v volume.Volume, path string) error) error {
+	ref := "archive-" + stringid.GenerateRandomID()
+	v, err := s.vs.Get(ctx, name, opts.WithGetReference(ref))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := s.vs.Release(context.Background(), v.Name(), ref); err != nil {
+			logrus.WithError(err).WithField("volume", v.Name()).Warn("Error releasing volume reference")
+		}
+	}()
+
+	path, err := v.Mount(ref)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := v.Unmount(ref); err != nil {
+			logrus.WithError(err).WithField("volume", v.Name()).Warn("Error unmounting volume")
+		}
+	}()
+
+	return fn(v, path)
+}
+
+func (s *VolumesService) uidMaps() []idtools.IDMap {
+	if s.idMapping == nil {
+		return nil
+	}
+	return s.idMapping.UIDs()
+}
+
+func (s *VolumesService) gidMaps() []idtools.IDMap {
+	if s.idMapping == nil {
+		return nil
+	}
+	return s.idMapping.GIDs()
+}
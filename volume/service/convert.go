@@ -28,6 +28,11 @@ type pathCacher interface {
 	CachedPath() string
 }
 
+// quotaReporter is implemented by volumes which can have a size limit.
+type quotaReporter interface {
+	Quota() (limit, usage uint64, err error)
+}
+
 func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volume, opts ...convertOpt) []*types.Volume {
 	var (
 		out        = make([]*types.Volume, 0, len(volumes))
@@ -70,6 +75,13 @@ func (s *VolumesService) volumesToAPI(ctx context.Context, volumes []volume.Volu
 				sz = -1
 			}
 			apiV.UsageData = &types.VolumeUsageData{Size: sz, RefCount: int64(s.vs.CountReferences(v))}
+			if qr, ok := v.(quotaReporter); ok {
+				if limit, _, err := qr.Quota(); err != nil {
+					logrus.WithError(err).WithField("volume", v.Name()).Warnf("Failed to determine size limit of volume")
+				} else {
+					apiV.UsageData.Limit = int64(limit)
+				}
+			}
 		}
 
 		out = append(out, &apiV)
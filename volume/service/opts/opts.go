@@ -87,3 +87,48 @@ func WithPurgeOnError(b bool) RemoveOption {
 		o.PurgeOnError = b
 	}
 }
+
+// CloneConfig is used by `CloneOption` to store config options for cloning
+// and snapshotting volumes.
+type CloneConfig struct {
+	Labels map[string]string
+}
+
+// CloneOption is used to pass options to the volumes service `Clone` and
+// `Snapshot` implementations.
+type CloneOption func(*CloneConfig)
+
+// WithCloneLabels creates a CloneOption which sets the labels of the new
+// volume to the passed in value.
+func WithCloneLabels(labels map[string]string) CloneOption {
+	return func(cfg *CloneConfig) {
+		cfg.Labels = labels
+	}
+}
+
+// UpdateConfig is used by `UpdateOption` to store the changes to apply to a
+// volume. Nil values are left unchanged.
+type UpdateConfig struct {
+	Labels  map[string]string
+	Options map[string]string
+}
+
+// UpdateOption is used to pass options to the volumes service `Update` implementation
+type UpdateOption func(*UpdateConfig)
+
+// WithUpdateLabels creates an UpdateOption which replaces the labels of the
+// volume with the passed in value.
+func WithUpdateLabels(labels map[string]string) UpdateOption {
+	return func(cfg *UpdateConfig) {
+		cfg.Labels = labels
+	}
+}
+
+// WithUpdateOptions creates an UpdateOption which replaces the driver
+// options of the volume with the passed in value. The volume driver must
+// support updating volumes.
+func WithUpdateOptions(options map[string]string) UpdateOption {
+	return func(cfg *UpdateConfig) {
+		cfg.Options = options
+	}
+}
@@ -3,6 +3,7 @@ package service // import "github.com/docker/docker/volume/service"
 import (
 	"context"
 	"sync/atomic"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/filters"
@@ -33,12 +34,13 @@ type VolumesService struct {
 	ds           ds
 	pruneRunning int32
 	eventLogger  volumeEventLogger
+	idMapping    *idtools.IdentityMapping
 }
 
 // NewVolumeService creates a new volume service
-func NewVolumeService(root string, pg plugingetter.PluginGetter, rootIDs idtools.Identity, logger volumeEventLogger) (*VolumesService, error) {
+func NewVolumeService(root string, pg plugingetter.PluginGetter, idMapping *idtools.IdentityMapping, logger volumeEventLogger) (*VolumesService, error) {
 	ds := drivers.NewStore(pg)
-	if err := setupDefaultDriver(ds, root, rootIDs); err != nil {
+	if err := setupDefaultDriver(ds, root, idMapping.RootPair()); err != nil {
 		return nil, err
 	}
 
@@ -46,7 +48,7 @@ func NewVolumeService(root string, pg plugingetter.PluginGetter, rootIDs idtools
 	if err != nil {
 		return nil, err
 	}
-	return &VolumesService{vs: vs, ds: ds, eventLogger: logger}, nil
+	return &VolumesService{vs: vs, ds: ds, eventLogger: logger, idMapping: idMapping}, nil
 }
 
 // GetDriverList gets the list of registered volume drivers
@@ -91,6 +93,19 @@ func (s *VolumesService) Get(ctx context.Context, name string, getOpts ...opts.G
 	if cfg.ResolveStatus {
 		vol.Status = v.Status()
 	}
+
+	if qr, ok := v.(quotaReporter); ok {
+		limit, usage, err := qr.Quota()
+		if err != nil {
+			logrus.WithError(err).WithField("volume", v.Name()).Warn("Failed to determine size limit of volume")
+		} else if limit > 0 {
+			vol.UsageData = &types.VolumeUsageData{
+				Size:     int64(usage),
+				RefCount: int64(s.vs.CountReferences(v)),
+				Limit:    int64(limit),
+			}
+		}
+	}
 	return &vol, nil
 }
 
@@ -167,6 +182,122 @@ func (s *VolumesService) Remove(ctx context.Context, name string, rmOpts ...opts
 	return err
 }
 
+// Update changes the labels and/or driver options of a volume.
+func (s *VolumesService) Update(ctx context.Context, name string, updateOpts ...opts.UpdateOption) (*types.Volume, error) {
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err = s.vs.Update(ctx, v, updateOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	s.eventLogger.LogVolumeEvent(v.Name(), "update", map[string]string{"driver": v.DriverName()})
+	apiV := volumeToAPIType(v)
+	return &apiV, nil
+}
+
+// snapshotSourceLabel is the label set on volumes created by `Snapshot` to
+// record the name of the volume the snapshot was taken from.
+const snapshotSourceLabel = "com.docker.volume.snapshot.source"
+
+// Clone creates a new volume with the given name holding a copy of the data
+// of the named volume. If no labels are passed, the labels of the source
+// volume are copied to the new volume.
+// If newName is empty, a random name is generated.
+func (s *VolumesService) Clone(ctx context.Context, name, newName string, cloneOpts ...opts.CloneOption) (*types.Volume, error) {
+	cfg := opts.CloneConfig{}
+	for _, o := range cloneOpts {
+		o(&cfg)
+	}
+
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := cfg.Labels
+	if labels == nil {
+		if dv, ok := v.(volume.DetailedVolume); ok {
+			labels = dv.Labels()
+		}
+	}
+	return s.clone(ctx, v, newName, labels)
+}
+
+// Snapshot creates a point-in-time copy of the named volume. The snapshot
+// inherits the labels of the source volume, extended with the passed in
+// labels and a label referring to the source volume.
+// If newName is empty, a name is derived from the source name and the
+// current time.
+func (s *VolumesService) Snapshot(ctx context.Context, name, newName string, cloneOpts ...opts.CloneOption) (*types.Volume, error) {
+	cfg := opts.CloneConfig{}
+	for _, o := range cloneOpts {
+		o(&cfg)
+	}
+
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string)
+	if dv, ok := v.(volume.DetailedVolume); ok {
+		for k, val := range dv.Labels() {
+			labels[k] = val
+		}
+	}
+	for k, val := range cfg.Labels {
+		labels[k] = val
+	}
+	labels[snapshotSourceLabel] = v.Name()
+
+	if newName == "" {
+		newName = v.Name() + "-" + time.Now().UTC().Format("20060102T150405Z")
+	}
+	return s.clone(ctx, v, newName, labels)
+}
+
+func (s *VolumesService) clone(ctx context.Context, v volume.Volume, newName string, labels map[string]string) (*types.Volume, error) {
+	if newName == "" {
+		newName = stringid.GenerateRandomID()
+	}
+	nv, err := s.vs.Clone(ctx, v, newName, labels)
+	if err != nil {
+		return nil, err
+	}
+
+	s.eventLogger.LogVolumeEvent(nv.Name(), "create", map[string]string{"driver": nv.DriverName(), "source": v.Name()})
+	apiV := volumeToAPIType(nv)
+	return &apiV, nil
+}
+
+// Rename changes the name of a volume.
+// An error is returned if the volume is still referenced.
+func (s *VolumesService) Rename(ctx context.Context, name, newName string) error {
+	if newName == "" {
+		return errdefs.InvalidParameter(errors.New("new volume name cannot be empty"))
+	}
+
+	v, err := s.vs.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	nv, err := s.vs.Rename(ctx, v, newName)
+	if err != nil {
+		if IsInUse(err) {
+			err = errdefs.Conflict(err)
+		}
+		return err
+	}
+
+	s.eventLogger.LogVolumeEvent(nv.Name(), "rename", map[string]string{"driver": nv.DriverName(), "oldName": v.Name()})
+	return nil
+}
+
 var acceptedPruneFilters = map[string]bool{
 	"label":  true,
 	"label!": true,
@@ -179,15 +310,28 @@ var acceptedListFilters = map[string]bool{
 	"label":    true,
 }
 
+// isLocalData returns whether the data of the volume is stored in the local
+// volume directory. This is not the case for volumes with mount options; the
+// "size" option only limits the size of the volume directory.
+func isLocalData(v volume.Volume) bool {
+	dv, ok := v.(volume.DetailedVolume)
+	if !ok {
+		return false
+	}
+	for k := range dv.Options() {
+		if k != "size" {
+			return false
+		}
+	}
+	return true
+}
+
 // LocalVolumesSize gets all local volumes and fetches their size on disk
 // Note that this intentionally skips volumes which have mount options. Typically
 // volumes with mount options are not really local even if they are using the
 // local driver.
 func (s *VolumesService) LocalVolumesSize(ctx context.Context) ([]*types.Volume, error) {
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
-	})))
+	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), CustomFilter(isLocalData)))
 	if err != nil {
 		return nil, err
 	}
@@ -207,10 +351,7 @@ func (s *VolumesService) Prune(ctx context.Context, filter filters.Args) (*types
 	if err != nil {
 		return nil, err
 	}
-	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(func(v volume.Volume) bool {
-		dv, ok := v.(volume.DetailedVolume)
-		return ok && len(dv.Options()) == 0
-	})))
+	ls, _, err := s.vs.Find(ctx, And(ByDriver(volume.DefaultDriverName), ByReferenced(false), by, CustomFilter(isLocalData)))
 	if err != nil {
 		return nil, err
 	}
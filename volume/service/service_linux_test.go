@@ -1,13 +1,17 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/reexec"
 	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
@@ -15,8 +19,13 @@ import (
 	"github.com/docker/docker/volume/testutils"
 	"gotest.tools/assert"
 	is "gotest.tools/assert/cmp"
+	"gotest.tools/skip"
 )
 
+func init() {
+	reexec.Init()
+}
+
 func TestLocalVolumeSize(t *testing.T) {
 	t.Parallel()
 
@@ -64,3 +73,106 @@ func TestLocalVolumeSize(t *testing.T) {
 		}
 	}
 }
+
+func TestLocalVolumeCloneAndRename(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("fake"), "fake"))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	v1, err := service.Create(ctx, "test1", volume.DefaultDriverName, opts.WithCreateLabels(map[string]string{"foo": "bar"}))
+	assert.NilError(t, err)
+	err = ioutil.WriteFile(filepath.Join(v1.Mountpoint, "data"), []byte("hello"), 0644)
+	assert.NilError(t, err)
+
+	clone, err := service.Clone(ctx, "test1", "test2")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(clone.Name, "test2"))
+	assert.Check(t, is.DeepEqual(clone.Labels, map[string]string{"foo": "bar"}))
+	b, err := ioutil.ReadFile(filepath.Join(clone.Mountpoint, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "hello"))
+
+	_, err = service.Clone(ctx, "test1", "test2")
+	assert.Check(t, IsNameConflict(err), err)
+
+	snap, err := service.Snapshot(ctx, "test1", "", opts.WithCloneLabels(map[string]string{"baz": "qux"}))
+	assert.NilError(t, err)
+	assert.Check(t, snap.Name != "")
+	assert.Check(t, is.DeepEqual(snap.Labels, map[string]string{"foo": "bar", "baz": "qux", snapshotSourceLabel: "test1"}))
+
+	_, err = service.Create(ctx, "test3", "fake")
+	assert.NilError(t, err)
+	_, err = service.Clone(ctx, "test3", "test4")
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+
+	_, err = service.Get(ctx, "test2", opts.WithGetReference("container"))
+	assert.NilError(t, err)
+	err = service.Rename(ctx, "test2", "test5")
+	assert.Check(t, errdefs.IsConflict(err), err)
+	assert.NilError(t, service.Release(ctx, "test2", "container"))
+
+	err = service.Rename(ctx, "test2", "test5")
+	assert.NilError(t, err)
+	_, err = service.Get(ctx, "test2")
+	assert.Check(t, IsNotExist(err), err)
+	renamed, err := service.Get(ctx, "test5")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(renamed.Labels, map[string]string{"foo": "bar"}))
+	b, err = ioutil.ReadFile(filepath.Join(renamed.Mountpoint, "data"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(string(b), "hello"))
+}
+
+func TestLocalVolumeExportImport(t *testing.T) {
+	skip.If(t, os.Getuid() != 0, "requires chroot")
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	dir, err := ioutil.TempDir("", t.Name())
+	assert.NilError(t, err)
+	defer os.RemoveAll(dir)
+
+	l, err := local.New(dir, idtools.Identity{UID: os.Getuid(), GID: os.Getegid()})
+	assert.NilError(t, err)
+	assert.Assert(t, ds.Register(l, volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+
+	ctx := context.Background()
+	src, err := service.Create(ctx, "src", volume.DefaultDriverName)
+	assert.NilError(t, err)
+	assert.NilError(t, os.MkdirAll(filepath.Join(src.Mountpoint, "sub"), 0755))
+	assert.NilError(t, ioutil.WriteFile(filepath.Join(src.Mountpoint, "sub", "data"), []byte("hello"), 0644))
+
+	for _, compression := range []archive.Compression{archive.Uncompressed, archive.Gzip} {
+		var buf bytes.Buffer
+		assert.NilError(t, service.Export(ctx, "src", compression, &buf))
+
+		dst, err := service.Create(ctx, "dst-"+compression.Extension(), volume.DefaultDriverName)
+		assert.NilError(t, err)
+		assert.NilError(t, service.Import(ctx, dst.Name, &buf))
+
+		data, err := ioutil.ReadFile(filepath.Join(dst.Mountpoint, "sub", "data"))
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(string(data), "hello"))
+
+		// the temporary reference must have been released
+		assert.NilError(t, service.Remove(ctx, dst.Name))
+	}
+
+	err = service.Export(ctx, "notexist", archive.Uncompressed, ioutil.Discard)
+	assert.Check(t, errdefs.IsNotFound(err), err)
+}
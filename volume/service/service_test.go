@@ -158,6 +158,43 @@ func TestServiceGet(t *testing.T) {
 	assert.Assert(t, errdefs.IsConflict(err), err)
 }
 
+func TestServiceUpdate(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver("d1"), "d1"))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := service.Update(ctx, "notexist", opts.WithUpdateLabels(map[string]string{"foo": "bar"}))
+	assert.Assert(t, IsNotExist(err), err)
+
+	_, err = service.Create(ctx, "test", "d1", opts.WithCreateLabels(map[string]string{"foo": "bar"}), opts.WithCreateOptions(map[string]string{"a": "b"}))
+	assert.NilError(t, err)
+
+	v, err := service.Update(ctx, "test", opts.WithUpdateLabels(map[string]string{"foo": "baz"}))
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(v.Labels, map[string]string{"foo": "baz"}))
+	assert.Check(t, is.DeepEqual(v.Options, map[string]string{"a": "b"}))
+
+	v, err = service.Get(ctx, "test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(v.Labels, map[string]string{"foo": "baz"}))
+
+	ls, _, err := service.List(ctx, filters.NewArgs(filters.Arg("label", "foo=baz")))
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(ls, 1))
+
+	meta, err := service.vs.getMeta("test")
+	assert.NilError(t, err)
+	assert.Check(t, is.DeepEqual(meta.Labels, map[string]string{"foo": "baz"}))
+
+	_, err = service.Update(ctx, "test", opts.WithUpdateOptions(map[string]string{"a": "c"}))
+	assert.Check(t, errdefs.IsNotImplemented(err), err)
+}
+
 func TestServicePrune(t *testing.T) {
 	t.Parallel()
 
@@ -233,6 +270,32 @@ func TestServicePrune(t *testing.T) {
 	assert.Assert(t, is.Equal(pr.VolumesDeleted[0], "test"))
 }
 
+func TestServicePruneSizeLimited(t *testing.T) {
+	t.Parallel()
+
+	ds := volumedrivers.NewStore(nil)
+	assert.Assert(t, ds.Register(testutils.NewFakeDriver(volume.DefaultDriverName), volume.DefaultDriverName))
+
+	service, cleanup := newTestService(t, ds)
+	defer cleanup()
+	ctx := context.Background()
+
+	// the data of size limited volumes is stored in the volume directory,
+	// whereas volumes with mount options are skipped as no space would be
+	// reclaimed
+	_, err := service.Create(ctx, "limited", volume.DefaultDriverName, opts.WithCreateOptions(map[string]string{"size": "10M"}))
+	assert.NilError(t, err)
+	_, err = service.Create(ctx, "mounted", volume.DefaultDriverName, opts.WithCreateOptions(map[string]string{"type": "tmpfs", "device": "tmpfs"}))
+	assert.NilError(t, err)
+
+	pr, err := service.Prune(ctx, filters.NewArgs())
+	assert.NilError(t, err)
+	assert.Assert(t, is.DeepEqual(pr.VolumesDeleted, []string{"limited"}))
+
+	_, err = service.Get(ctx, "mounted")
+	assert.NilError(t, err)
+}
+
 func newTestService(t *testing.T, ds *volumedrivers.Store) (*VolumesService, func()) {
 	t.Helper()
 
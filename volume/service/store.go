@@ -69,6 +69,13 @@ func (v volumeWrapper) CachedPath() string {
 	return v.Volume.Path()
 }
 
+func (v volumeWrapper) Quota() (limit, usage uint64, err error) {
+	if vv, ok := v.Volume.(quotaReporter); ok {
+		return vv.Quota()
+	}
+	return 0, 0, nil
+}
+
 // NewStore creates a new volume store at the given path
 func NewStore(rootPath string, drivers *drivers.Store) (*VolumeStore, error) {
 	vs := &VolumeStore{
@@ -806,6 +813,272 @@ func (s *VolumeStore) Remove(ctx context.Context, v volume.Volume, rmOpts ...opt
 	return err
 }
 
+// lockNames acquires the name locks for both passed in names in a consistent
+// order, so that concurrent operations on the same pair of volumes cannot
+// deadlock. The returned function releases the locks.
+func (s *VolumeStore) lockNames(a, b string) func() {
+	if b < a {
+		a, b = b, a
+	}
+	s.locks.Lock(a)
+	s.locks.Lock(b)
+	return func() {
+		s.locks.Unlock(b)
+		s.locks.Unlock(a)
+	}
+}
+
+// checkNameAvailable returns a conflict error if a volume with the given name
+// is already known to the store or to the passed in driver.
+// Callers of this function are expected to hold the name lock.
+func (s *VolumeStore) checkNameAvailable(ctx context.Context, name string, vd volume.Driver) error {
+	if v, err := s.checkConflict(ctx, name, ""); err != nil {
+		return err
+	} else if v != nil {
+		return errors.Wrapf(errNameConflict, "volume '%s' already exists", name)
+	}
+	if v, _ := vd.Get(name); v != nil {
+		return errors.Wrapf(errNameConflict, "driver '%s' already has volume '%s'", vd.Name(), name)
+	}
+	return nil
+}
+
+// Clone creates a new volume with the given name holding a copy of the data
+// of the passed in volume. The driver of the volume must implement
+// `volume.Cloner`.
+func (s *VolumeStore) Clone(ctx context.Context, v volume.Volume, newName string, labels map[string]string) (volume.Volume, error) {
+	name := normalizeVolumeName(v.Name())
+	newName = normalizeVolumeName(newName)
+	if name == newName {
+		return nil, &OpErr{Err: errdefs.InvalidParameter(errors.New("source and target volume names must differ")), Name: name, Op: "clone"}
+	}
+
+	unlock := s.lockNames(name, newName)
+	defer unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	parser := volumemounts.NewParser(runtime.GOOS)
+	if err := parser.ValidateVolumeName(newName); err != nil {
+		return nil, &OpErr{Err: err, Name: newName, Op: "clone"}
+	}
+
+	v, err := s.getVolume(ctx, name, v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "clone"}
+	}
+
+	vd, err := s.drivers.CreateDriver(v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: v.DriverName(), Op: "clone"}
+	}
+	var nv volume.Volume
+	defer func() {
+		if nv == nil {
+			if _, err := s.drivers.ReleaseDriver(vd.Name()); err != nil {
+				logrus.WithError(err).WithField("driver", vd.Name()).Error("Error releasing reference to volume driver")
+			}
+		}
+	}()
+
+	cloner, ok := vd.(volume.Cloner)
+	if !ok {
+		return nil, &OpErr{Err: errdefs.NotImplemented(errors.Errorf("volume driver %s does not support cloning volumes", vd.Name())), Name: name, Op: "clone"}
+	}
+	if err := s.checkNameAvailable(ctx, newName, vd); err != nil {
+		return nil, &OpErr{Err: err, Name: newName, Op: "clone"}
+	}
+
+	logrus.Debugf("Cloning volume: driver %s, name %s, new name %s", vd.Name(), name, newName)
+	cloned, err := cloner.Clone(unwrapVolume(v), newName)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "clone"}
+	}
+
+	var options map[string]string
+	if dv, ok := v.(volume.DetailedVolume); ok {
+		options = dv.Options()
+	}
+
+	metadata := volumeMetadata{
+		Name:    newName,
+		Driver:  vd.Name(),
+		Labels:  labels,
+		Options: options,
+	}
+	if err := s.setMeta(newName, metadata); err != nil {
+		// remove the clone, so that no volume is left without metadata
+		if rerr := vd.Remove(cloned); rerr != nil {
+			logrus.WithError(rerr).WithField("volume", newName).Error("Error removing cloned volume after failing to store its metadata")
+		}
+		return nil, &OpErr{Err: err, Name: newName, Op: "clone"}
+	}
+
+	s.globalLock.Lock()
+	s.labels[newName] = labels
+	s.options[newName] = options
+	s.refs[newName] = make(map[string]struct{})
+	s.globalLock.Unlock()
+
+	nv = volumeWrapper{cloned, labels, vd.Scope(), options}
+	s.setNamed(nv, "")
+	return nv, nil
+}
+
+// Rename changes the name of the passed in volume. The driver of the volume
+// must implement `volume.Renamer`. A volume is not renamed if it has any refs.
+func (s *VolumeStore) Rename(ctx context.Context, v volume.Volume, newName string) (volume.Volume, error) {
+	name := normalizeVolumeName(v.Name())
+	newName = normalizeVolumeName(newName)
+	if name == newName {
+		return nil, &OpErr{Err: errdefs.InvalidParameter(errors.New("new name must differ from the current name")), Name: name, Op: "rename"}
+	}
+
+	unlock := s.lockNames(name, newName)
+	defer unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	parser := volumemounts.NewParser(runtime.GOOS)
+	if err := parser.ValidateVolumeName(newName); err != nil {
+		return nil, &OpErr{Err: err, Name: newName, Op: "rename"}
+	}
+
+	if s.hasRef(name) {
+		return nil, &OpErr{Err: errVolumeInUse, Name: name, Op: "rename", Refs: s.getRefs(name)}
+	}
+
+	v, err := s.getVolume(ctx, name, v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "rename"}
+	}
+
+	vd, err := s.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: v.DriverName(), Op: "rename"}
+	}
+	renamer, ok := vd.(volume.Renamer)
+	if !ok {
+		return nil, &OpErr{Err: errdefs.NotImplemented(errors.Errorf("volume driver %s does not support renaming volumes", vd.Name())), Name: name, Op: "rename"}
+	}
+	if err := s.checkNameAvailable(ctx, newName, vd); err != nil {
+		return nil, &OpErr{Err: err, Name: newName, Op: "rename"}
+	}
+
+	meta, err := s.getMeta(name)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "rename"}
+	}
+
+	logrus.Debugf("Renaming volume: driver %s, name %s, new name %s", vd.Name(), name, newName)
+	renamed, err := renamer.Rename(unwrapVolume(v), newName)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "rename"}
+	}
+
+	meta.Name = newName
+	meta.Driver = vd.Name()
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := removeMeta(tx, name); err != nil {
+			return err
+		}
+		return setMeta(tx, newName, meta)
+	}); err != nil {
+		// rename the volume back, so that it stays consistent with its metadata
+		if _, rerr := renamer.Rename(renamed, name); rerr != nil {
+			logrus.WithError(rerr).WithField("volume", newName).Errorf("Error renaming volume back to %s after failing to update its metadata", name)
+		}
+		return nil, &OpErr{Err: err, Name: newName, Op: "rename"}
+	}
+
+	nv := volumeWrapper{renamed, meta.Labels, vd.Scope(), meta.Options}
+
+	s.globalLock.Lock()
+	delete(s.names, name)
+	delete(s.refs, name)
+	delete(s.labels, name)
+	delete(s.options, name)
+	s.names[newName] = nv
+	s.refs[newName] = make(map[string]struct{})
+	s.labels[newName] = meta.Labels
+	s.options[newName] = meta.Options
+	s.globalLock.Unlock()
+
+	return nv, nil
+}
+
+// Update changes the labels and/or driver options of the passed in volume.
+// Option changes are forwarded to the volume driver, which must implement
+// `volume.Updater`. The changes are persisted in the metadata database.
+func (s *VolumeStore) Update(ctx context.Context, v volume.Volume, updateOpts ...opts.UpdateOption) (volume.Volume, error) {
+	var cfg opts.UpdateConfig
+	for _, o := range updateOpts {
+		o(&cfg)
+	}
+
+	name := normalizeVolumeName(v.Name())
+	s.locks.Lock(name)
+	defer s.locks.Unlock(name)
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	v, err := s.getVolume(ctx, name, v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "update"}
+	}
+
+	meta, err := s.getMeta(name)
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "update"}
+	}
+	meta.Name = name
+	meta.Driver = v.DriverName()
+
+	vd, err := s.drivers.GetDriver(v.DriverName())
+	if err != nil {
+		return nil, &OpErr{Err: err, Name: v.DriverName(), Op: "update"}
+	}
+
+	if cfg.Options != nil {
+		updater, ok := vd.(volume.Updater)
+		if !ok {
+			return nil, &OpErr{Err: errdefs.NotImplemented(errors.Errorf("volume driver %s does not support updating volume options", vd.Name())), Name: name, Op: "update"}
+		}
+		if err := updater.Update(unwrapVolume(v), cfg.Options); err != nil {
+			return nil, &OpErr{Err: err, Name: name, Op: "update"}
+		}
+		meta.Options = cfg.Options
+	}
+	if cfg.Labels != nil {
+		meta.Labels = cfg.Labels
+	}
+
+	if err := s.setMeta(name, meta); err != nil {
+		return nil, &OpErr{Err: err, Name: name, Op: "update"}
+	}
+
+	s.globalLock.Lock()
+	s.labels[name] = meta.Labels
+	s.options[name] = meta.Options
+	s.globalLock.Unlock()
+
+	nv := volumeWrapper{unwrapVolume(v), meta.Labels, vd.Scope(), meta.Options}
+	s.setNamed(nv, "")
+	return nv, nil
+}
+
 // Release releases the specified reference to the volume
 func (s *VolumeStore) Release(ctx context.Context, name string, ref string) error {
 	s.locks.Lock(name)
@@ -67,3 +67,25 @@ type DetailedVolume interface {
 	Scope() string
 	Volume
 }
+
+// Cloner is an optional interface implemented by drivers which can create
+// a new volume holding a copy of the data of an existing volume.
+type Cloner interface {
+	// Clone creates a new volume with the given name, populated with a
+	// point-in-time copy of the data stored in vol.
+	Clone(vol Volume, name string) (Volume, error)
+}
+
+// Renamer is an optional interface implemented by drivers which can change
+// the name of an existing volume without touching its data.
+type Renamer interface {
+	// Rename changes the name of vol to newName and returns the renamed volume.
+	Rename(vol Volume, newName string) (Volume, error)
+}
+
+// Updater is an optional interface implemented by drivers which can change
+// the options of an existing volume without recreating it.
+type Updater interface {
+	// Update replaces the driver options of vol with the passed in options.
+	Update(vol Volume, opts map[string]string) error
+}